@@ -0,0 +1,215 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FranciscoBarao/catalog/middleware"
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+	"github.com/FranciscoBarao/catalog/model"
+)
+
+const (
+	ratingPath       = "/api/rating"
+	rankingPath      = "/api/rating/ranking"
+	rankingBatchPath = "/api/rating/ranking/batch"
+	ratingNamespace  = "boardgame"            // Namespace of the ratings of boardgames in the rating-service
+	retryBackoff     = 100 * time.Millisecond // Wait before the first retry, growing with every attempt
+	MaxRankingBatch  = 100                    // Most rankings the rating-service answers with in one request
+)
+
+// ratingRequest is the body the rating-service expects when creating a Rating
+type ratingRequest struct {
+	Username           string `json:"username"`
+	ReferenceNamespace string `json:"reference_namespace"`
+	ReferenceID        string `json:"reference_id"`
+	Value              int    `json:"value"`
+}
+
+// rankingPage is a page of the rankings of a namespace, best scored first
+type rankingPage struct {
+	Items []model.Ranking `json:"items"`
+}
+
+// ratingError is the body the rating-service answers with when a request fails
+type ratingError struct {
+	Status  int
+	Message string
+}
+
+// RatingClient talks to the rating-service, which holds the ratings of every boardgame
+type RatingClient struct {
+	url     string
+	client  *http.Client
+	retries int
+}
+
+// NewRatingClient creates a client for the rating-service at baseURL, giving up on each request after timeout and retrying failed ones
+func NewRatingClient(baseURL string, timeout time.Duration, retries int) *RatingClient {
+	return &RatingClient{
+		url:     strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+		retries: retries,
+	}
+}
+
+// Create sends the Rating of the boardgame with the reference id on behalf of the token's owner, filling it with the created one
+func (rc *RatingClient) Create(rating *model.Rating, referenceID, token string) error {
+	body, err := json.Marshal(ratingRequest{
+		Username:           rating.Username,
+		ReferenceNamespace: ratingNamespace,
+		ReferenceID:        referenceID,
+		Value:              rating.Value,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sent once -> A create that reached the rating-service but whose answer was lost would be retried into a conflict
+	res, err := rc.do(http.MethodPost, ratingPath, body, token)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(rating); err != nil {
+		logging.FromCtx(context.Background()).Error().Err(err).Msg("failed to decode rating-service response")
+		return middleware.NewError(http.StatusBadGateway, "Rating service answered with an invalid rating")
+	}
+	return nil
+}
+
+// GetRankings fetches a page of the rankings of the rated boardgames, best scored first and by reference id on ties
+func (rc *RatingClient) GetRankings(offset, limit int) ([]model.Ranking, error) {
+	query := url.Values{}
+	query.Set("reference_namespace", ratingNamespace)
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	res, err := rc.do(http.MethodGet, rankingPath+"?"+query.Encode(), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var page rankingPage
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		logging.FromCtx(context.Background()).Error().Err(err).Msg("failed to decode rating-service response")
+		return nil, middleware.NewError(http.StatusBadGateway, "Rating service answered with invalid rankings")
+	}
+	return page.Items, nil
+}
+
+// GetRankingsByIds fetches the ranking of each boardgame reference id, in their order -> Boardgames without ratings get the prior score
+func (rc *RatingClient) GetRankingsByIds(referenceIDs []string) ([]model.Ranking, error) {
+	rankings := make([]model.Ranking, 0, len(referenceIDs))
+	for start := 0; start < len(referenceIDs); start += MaxRankingBatch {
+		end := start + MaxRankingBatch
+		if end > len(referenceIDs) {
+			end = len(referenceIDs)
+		}
+
+		query := url.Values{}
+		query.Set("reference_namespace", ratingNamespace)
+		query.Set("reference_ids", strings.Join(referenceIDs[start:end], ","))
+		res, err := rc.do(http.MethodGet, rankingBatchPath+"?"+query.Encode(), nil, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []model.Ranking
+		err = json.NewDecoder(res.Body).Decode(&batch)
+		res.Body.Close()
+		if err != nil || len(batch) != end-start {
+			logging.FromCtx(context.Background()).Error().Err(err).Msg("failed to decode rating-service response")
+			return nil, middleware.NewError(http.StatusBadGateway, "Rating service answered with invalid rankings")
+		}
+		rankings = append(rankings, batch...)
+	}
+	return rankings, nil
+}
+
+// GetPrior fetches the score of boardgames without ratings -> No boardgame has the id 0, so it is never rated
+func (rc *RatingClient) GetPrior() (float64, error) {
+	rankings, err := rc.GetRankingsByIds([]string{"0"})
+	if err != nil {
+		return 0, err
+	}
+	return rankings[0].Score, nil
+}
+
+// do sends the request, retrying idempotent ones while the rating-service can't be reached or fails on its side, and maps its errors
+func (rc *RatingClient) do(method, path string, body []byte, token string) (*http.Response, error) {
+	log := logging.FromCtx(context.Background())
+
+	retries := rc.retries
+	if !isIdempotent(method) {
+		retries = 0
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * retryBackoff)
+		}
+
+		req, err := http.NewRequest(method, rc.url+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" { // Public endpoints need no token
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		res, err := rc.client.Do(req)
+		if err != nil {
+			log.Error().Err(err).Str("path", path).Int("attempt", attempt).Msg("failed to reach rating-service")
+			lastErr = middleware.NewError(http.StatusServiceUnavailable, "Rating service unavailable")
+			continue
+		}
+
+		if res.StatusCode < http.StatusBadRequest {
+			return res, nil
+		}
+
+		lastErr = readError(res)
+		if res.StatusCode < http.StatusInternalServerError && res.StatusCode != http.StatusTooManyRequests {
+			return nil, lastErr // The request itself is wrong -> Retrying won't help
+		}
+		log.Error().Err(lastErr).Str("path", path).Int("attempt", attempt).Int("status", res.StatusCode).Msg("rating-service request failed")
+	}
+	return nil, lastErr
+}
+
+// isIdempotent tells if sending a request of the method twice has the same effect as sending it once, so it can be retried
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// readError maps a failed rating-service response into a MalformedRequest, so it reaches the caller with its status
+func readError(res *http.Response) error {
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return middleware.NewError(http.StatusBadGateway, "Rating service failed")
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	var body ratingError
+	if err := json.Unmarshal(data, &body); err == nil && body.Message != "" {
+		return middleware.NewError(res.StatusCode, body.Message)
+	}
+
+	var message string // The oauth middleware answers with a json string
+	if err := json.Unmarshal(data, &message); err != nil || message == "" {
+		message = http.StatusText(res.StatusCode)
+	}
+	return middleware.NewError(res.StatusCode, message)
+}
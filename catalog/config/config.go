@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type PostgresConfig struct {
@@ -36,3 +38,41 @@ func NewPostgresConfig() (*PostgresConfig, error) {
 func (p *PostgresConfig) String() string {
 	return "host=" + p.Host + " user=" + p.Username + " password=" + p.Password + " dbname=" + p.Database + " port=" + p.Port
 }
+
+type RatingServiceConfig struct {
+	URL     string
+	Timeout time.Duration
+	Retries int
+}
+
+// NewRatingServiceConfig fetches where the rating-service is, with how long to wait for it and how many times to retry
+func NewRatingServiceConfig() (*RatingServiceConfig, error) {
+	url, urlPresent := os.LookupEnv("RATING_SERVICE_URL")
+	if !urlPresent {
+		return nil, fmt.Errorf("failed to fetch rating-service env vars")
+	}
+
+	config := &RatingServiceConfig{
+		URL:     url,
+		Timeout: 5 * time.Second,
+		Retries: 2,
+	}
+
+	if timeout, present := os.LookupEnv("RATING_SERVICE_TIMEOUT"); present {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RATING_SERVICE_TIMEOUT: %w", err)
+		}
+		config.Timeout = duration
+	}
+
+	if retries, present := os.LookupEnv("RATING_SERVICE_RETRIES"); present {
+		number, err := strconv.Atoi(retries)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("failed to parse RATING_SERVICE_RETRIES")
+		}
+		config.Retries = number
+	}
+
+	return config, nil
+}
@@ -14,11 +14,14 @@ import (
 // Declaring the repository interface in the controller package allows us to easily swap out the actual implementation, enforcing loose coupling
 type boardgameService interface {
 	Create(boardgame *model.Boardgame, id string) error
-	GetAll(sort, filterBody, filterValue string) ([]model.Boardgame, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error)
+	GetAllRanked(filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error)
+	Search(query string, page *model.Page) ([]model.BoardgameSearch, error)
+	Suggest(name string, limit int) ([]model.BoardgameSuggestion, error)
 	GetById(id string) (model.Boardgame, error)
 	Update(boardgame *model.Boardgame, id string) error
 	DeleteById(id string) error
-	Rate(rating *model.Rating, id, username string) error
+	Rate(rating *model.Rating, id, username, token string) error
 }
 
 // Controller contains the service, which contains database-related logic, as an injectable dependency, allowing us to decouple business logic from db logic
@@ -74,31 +77,132 @@ func (controller *BoardgameController) Create(w http.ResponseWriter, r *http.Req
 // @Summary 	Fetches all Boardgames
 // @Tags 		boardgames
 // @Produce 	json
-// @Param 		filterBy query string  false  "Filter using field.value (For String partial find) OR field.operator.value"
-// @Success 	200 {object} model.Boardgame
+// @Param 		sortBy query string  false  "Sort using field.order, joined by , for several fields (E.g publisher.asc,name.asc), or rank for the best rated first"
+// @Param 		filterBy query string  false  "Filter using field.value (For String partial find) OR field.operator.value, joined by , (AND) or | (OR)"
+// @Param 		tags query string  false  "Comma separated Tag names the Boardgame must have"
+// @Param 		categories query string  false  "Comma separated Category names the Boardgame must have"
+// @Param 		mechanisms query string  false  "Comma separated Mechanism names the Boardgame must have"
+// @Param 		match query string  false  "Whether any (default) or all of the given association names must match"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
 // @Router 		/boardgame [get]
 func (controller *BoardgameController) GetAll(w http.ResponseWriter, r *http.Request) {
 	sortBy := r.URL.Query().Get("sortBy")
-	sort, err := utils.GetSort(model.Boardgame{}, sortBy)
+	ranked := utils.IsRankSort(sortBy) // Ranking is kept by the rating-service, not by a field
+	var sort string
+	if !ranked {
+		var err error
+		if sort, err = utils.GetSort(model.Boardgame{}, sortBy); err != nil {
+			middleware.ErrorHandler(w, err)
+			return
+		}
+	}
+
+	filterBy := r.URL.Query().Get("filterBy")
+	filterBody, filterValues, err := utils.GetFilters(model.Boardgame{}, filterBy)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	filterBy := r.URL.Query().Get("filterBy")
-	filterBody, filterValue, err := utils.GetFilters(model.Boardgame{}, filterBy)
+	// Associations are matched through their join tables
+	associations := map[string]string{
+		"tags":       r.URL.Query().Get("tags"),
+		"categories": r.URL.Query().Get("categories"),
+		"mechanisms": r.URL.Query().Get("mechanisms"),
+	}
+	associationBody, associationValues, err := utils.GetAssociationFilters(model.Boardgame{}, associations, r.URL.Query().Get("match"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	filterBody, filterValues = utils.CombineFilters(filterBody, filterValues, associationBody, associationValues)
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	var boardgames []model.Boardgame
+	if ranked {
+		if page.GetCursor() != "" {
+			middleware.ErrorHandler(w, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, ranked listings are paged by offset"))
+			return
+		}
+		boardgames, err = controller.service.GetAllRanked(filterBody, filterValues, page)
+	} else {
+		boardgames, err = controller.service.GetAll(sort, filterBody, filterValues, page)
+	}
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := render.New().JSON(w, http.StatusOK, model.NewPaginated(boardgames, page)); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+}
+
+// Search Boardgames godoc
+// @Summary 	Searches Boardgames by name, publisher, tags, categories and mechanisms, most relevant first
+// @Tags 		boardgames
+// @Produce 	json
+// @Param 		q query string  true  "The words to search, matched by prefix and ignoring accents"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Success 	200 {object} model.Paginated
+// @Router 		/boardgame/search [get]
+func (controller *BoardgameController) Search(w http.ResponseWriter, r *http.Request) {
+	query, err := utils.GetSearchQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), "") // Ranked results are paged by offset
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	results, err := controller.service.Search(query, page)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := render.New().JSON(w, http.StatusOK, model.NewPaginated(results, page)); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+}
+
+// Suggest Boardgames godoc
+// @Summary 	Suggests the Boardgames with names resembling the typed one, most similar first
+// @Tags 		boardgames
+// @Produce 	json
+// @Param 		q query string  true  "The name typed so far, typos and missing accents are tolerated"
+// @Param 		limit query int  false  "Number of suggestions (default 10, max 25)"
+// @Success 	200 {array} model.BoardgameSuggestion
+// @Router 		/boardgame/suggest [get]
+func (controller *BoardgameController) Suggest(w http.ResponseWriter, r *http.Request) {
+	name, limit, err := utils.GetSuggestQuery(r.URL.Query().Get("q"), r.URL.Query().Get("limit"))
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	boardgames, err := controller.service.GetAll(sort, filterBody, filterValue)
+	suggestions, err := controller.service.Suggest(name, limit)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	if err := render.New().JSON(w, http.StatusOK, boardgames); err != nil {
+	if err := render.New().JSON(w, http.StatusOK, suggestions); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
@@ -190,6 +294,7 @@ func (controller *BoardgameController) Delete(w http.ResponseWriter, r *http.Req
 // @Tags 		boardgames
 // @Produce 	json
 // @Param 		id path int true "The Boardgame id"
+// @Param 		data body model.Rating true "The Rating, from 0 to 10"
 // @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
 // @Success 	200 {object} model.Rating
 // @Router 		/boardgame/{id}/rate [post]
@@ -217,7 +322,14 @@ func (controller *BoardgameController) Rate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if err := controller.service.Rate(rating, id, user); err != nil {
+	// The rating-service authorizes the rating with the same token
+	token, err := utils.GetAccessToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.Rate(rating, id, user, token); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
@@ -13,7 +13,7 @@ import (
 
 type categoryService interface {
 	Create(category *model.Category) error
-	GetAll(sort string) ([]model.Category, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Category, error)
 	Get(name string) (model.Category, error)
 	Delete(name string) error
 }
@@ -66,8 +66,13 @@ func (controller *CategoryController) Create(w http.ResponseWriter, r *http.Requ
 // @Summary 	Fetches all Categories
 // @Tags 		categories
 // @Produce 	json
+// @Param 		sortBy query string  false  "Sort using field.order, joined by , for several fields (E.g publisher.asc,name.asc)"
+// @Param 		filterBy query string  false  "Filter using field.value (For String partial find) OR field.operator.value, joined by , (AND) or | (OR)"
 // @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
-// @Success 	200 {object} model.Category
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
 // @Router 		/category [get]
 func (controller *CategoryController) GetAll(w http.ResponseWriter, r *http.Request) {
 	sortBy := r.URL.Query().Get("sortBy")
@@ -77,13 +82,26 @@ func (controller *CategoryController) GetAll(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	categories, err := controller.service.GetAll(sort)
+	filterBy := r.URL.Query().Get("filterBy")
+	filterBody, filterValues, err := utils.GetFilters(model.Category{}, filterBy)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	categories, err := controller.service.GetAll(sort, filterBody, filterValues, page)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	if err := render.New().JSON(w, http.StatusOK, categories); err != nil {
+	if err := render.New().JSON(w, http.StatusOK, model.NewPaginated(categories, page)); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
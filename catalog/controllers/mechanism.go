@@ -13,7 +13,7 @@ import (
 
 type mechanismService interface {
 	Create(mechanism *model.Mechanism) error
-	GetAll(sort string) ([]model.Mechanism, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Mechanism, error)
 	Get(name string) (model.Mechanism, error)
 	Delete(name string) error
 }
@@ -66,8 +66,13 @@ func (controller *MechanismController) Create(w http.ResponseWriter, r *http.Req
 // @Summary 	Fetches all Mechanisms
 // @Tags 	mechanisms
 // @Produce 	json
+// @Param 		sortBy query string  false  "Sort using field.order, joined by , for several fields (E.g publisher.asc,name.asc)"
+// @Param 		filterBy query string  false  "Filter using field.value (For String partial find) OR field.operator.value, joined by , (AND) or | (OR)"
 // @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
-// @Success 	200 {object} model.Mechanism
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
 // @Router 		/mechanism [get]
 func (controller *MechanismController) GetAll(w http.ResponseWriter, r *http.Request) {
 	sortBy := r.URL.Query().Get("sortBy")
@@ -77,13 +82,26 @@ func (controller *MechanismController) GetAll(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	mechanisms, err := controller.service.GetAll(sort)
+	filterBy := r.URL.Query().Get("filterBy")
+	filterBody, filterValues, err := utils.GetFilters(model.Mechanism{}, filterBy)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	mechanisms, err := controller.service.GetAll(sort, filterBody, filterValues, page)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	if err := render.New().JSON(w, http.StatusOK, mechanisms); err != nil {
+	if err := render.New().JSON(w, http.StatusOK, model.NewPaginated(mechanisms, page)); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/FranciscoBarao/catalog/middleware"
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+	"github.com/FranciscoBarao/catalog/model"
+)
+
+var schemaCache = &sync.Map{}
+
+// keysetColumn is a column of the listing order and its direction
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// cursor is the position right after the last entry of a page, tied to the order it was created with
+type cursor struct {
+	Order  string            `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// readPage fetches one page of the query ordered by sort and a primary key tie-breaker, so that keyset cursors are stable
+func (instance *Postgres) readPage(query *gorm.DB, value interface{}, page *model.Page, sort string) error {
+	log := logging.FromCtx(context.Background())
+
+	modelSchema, err := schema.Parse(value, schemaCache, instance.db.NamingStrategy)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to parse model schema")
+		return err
+	}
+
+	columns, err := getKeysetColumns(modelSchema, sort)
+	if err != nil {
+		return err
+	}
+	order := constructOrder(columns)
+
+	var total int64
+	if err := query.Model(value).Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("failed to count database entries")
+		return err
+	}
+	page.SetTotal(total)
+
+	find := query.Preload(clause.Associations).Order(order)
+	if page.GetCursor() != "" {
+		search, identifiers, err := decodeCursor(page.GetCursor(), order, columns)
+		if err != nil {
+			return err
+		}
+		find = find.Where(search, identifiers...)
+	}
+
+	if err := find.Offset(page.GetOffset()).Limit(page.GetLimit() + 1).Find(value).Error; err != nil {
+		return err
+	}
+
+	// The extra entry only tells there is a next page
+	entries := reflect.ValueOf(value).Elem()
+	if entries.Len() > page.GetLimit() {
+		next, err := encodeCursor(entries.Index(page.GetLimit()-1), order, columns)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to encode page cursor")
+			return err
+		}
+		page.SetNextCursor(next)
+		entries.Set(entries.Slice(0, page.GetLimit()))
+	}
+
+	log.Debug().Str("order", order).Interface("page", page).Msg("fetched database page")
+	return nil
+}
+
+// getKeysetColumns maps the sort (E.g name asc, id desc) into model fields, appending the primary key when missing
+func getKeysetColumns(modelSchema *schema.Schema, sort string) ([]keysetColumn, error) {
+	var columns []keysetColumn
+	for _, part := range strings.Split(sort, ",") {
+		splits := strings.Fields(part)
+		if len(splits) == 0 {
+			continue
+		}
+
+		field := lookUpField(modelSchema, splits[0])
+		if field == nil {
+			logging.FromCtx(context.Background()).Error().Str("sort", sort).Msg("unknown sort field for pagination")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
+		}
+		columns = append(columns, keysetColumn{field: field, desc: len(splits) > 1 && strings.EqualFold(splits[1], "desc")})
+	}
+
+	primaryKey := modelSchema.PrioritizedPrimaryField
+	if primaryKey == nil {
+		return columns, nil
+	}
+	for _, column := range columns {
+		if column.field == primaryKey {
+			return columns, nil
+		}
+	}
+	return append(columns, keysetColumn{field: primaryKey}), nil
+}
+
+// lookUpField finds a field by column name, struct name or lowercase struct name (E.g player_number, PlayerNumber or playernumber)
+func lookUpField(modelSchema *schema.Schema, name string) *schema.Field {
+	if field := modelSchema.LookUpField(name); field != nil && field.DBName != "" {
+		return field
+	}
+	for _, field := range modelSchema.Fields {
+		if field.DBName != "" && strings.ToLower(field.Name) == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// constructOrder constructs the order clause of the columns
+func constructOrder(columns []keysetColumn) string {
+	var order []string
+	for _, column := range columns {
+		direction := " asc"
+		if column.desc {
+			direction = " desc"
+		}
+		order = append(order, column.field.DBName+direction)
+	}
+	return strings.Join(order, ", ")
+}
+
+// encodeCursor stores the order values of the entry into an opaque cursor
+func encodeCursor(entry reflect.Value, order string, columns []keysetColumn) (string, error) {
+	c := cursor{Order: order}
+	for _, column := range columns {
+		raw, err := json.Marshal(reflect.Indirect(entry).FieldByName(column.field.Name).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, raw)
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor compiles the cursor into the condition selecting the entries after it
+// E.g name asc, id asc ---> (name > ?) OR (name = ? AND id > ?)
+func decodeCursor(encoded, order string, columns []keysetColumn) (string, []interface{}, error) {
+	log := logging.FromCtx(context.Background())
+
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Order != order || len(c.Values) != len(columns) {
+		log.Error().Err(err).Str("cursor", encoded).Str("order", order).Msg("cursor malformed or from another order")
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.field.FieldType)
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			log.Error().Err(err).Str("cursor", encoded).Msg("cursor value malformed")
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var groups []string
+	var identifiers []interface{}
+	for i, column := range columns {
+		var clauses []string
+		for _, previous := range columns[:i] {
+			clauses = append(clauses, previous.field.DBName+" = ?")
+		}
+		operator := " > ?"
+		if column.desc {
+			operator = " < ?"
+		}
+		clauses = append(clauses, column.field.DBName+operator)
+
+		groups = append(groups, "("+strings.Join(clauses, " AND ")+")")
+		identifiers = append(identifiers, values[:i+1]...)
+	}
+	return "(" + strings.Join(groups, " OR ") + ")", identifiers, nil
+}
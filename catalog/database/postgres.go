@@ -49,6 +49,10 @@ func Connect(config *config.PostgresConfig) (*Postgres, error) {
 
 	log.Debug().Msg("database migration completed")
 
+	if err = setupSearch(db); err != nil {
+		return nil, err
+	}
+
 	return &Postgres{db}, nil
 }
 
@@ -81,22 +85,27 @@ func (instance *Postgres) Create(value interface{}) error {
 	return nil
 }
 
-func (instance *Postgres) Read(value interface{}, sort, search, identifier string) error {
+// Read fetches one entry or, when value is a slice, all entries matching the parameterized search with its identifiers
+// When a page is given only that page is fetched, and the page is filled with the total and the next cursor
+func (instance *Postgres) Read(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
 	log := logging.FromCtx(context.Background())
 
+	query := instance.db
+	if search != "" {
+		query = query.Where("("+search+")", identifiers...).Session(&gorm.Session{}) // Session allows reusing the filters for counting and finding
+	}
+
 	var err error
-	if isSliceOrArray(value) {
-		if search == "" {
-			err = instance.db.Preload(clause.Associations).Order(sort).Find(value).Error // Find all with sort and NO filters
-		} else {
-			err = instance.db.Preload(clause.Associations).Order(sort).Find(value, search, identifier).Error // Find all with filters and sort
-		}
+	if !isSliceOrArray(value) {
+		err = query.Preload(clause.Associations).First(value).Error // Find 1 Specific
+	} else if page == nil {
+		err = query.Preload(clause.Associations).Order(sort).Find(value).Error // Find all with filters and sort
 	} else {
-		err = instance.db.Preload(clause.Associations).First(value, search, identifier).Error // Find 1 Specific
+		err = instance.readPage(query, value, page, sort) // Find a page with filters and sort
 	}
 
 	if err != nil {
-		log.Error().Err(err).Str("search", search).Str("identifier", identifier).Msg("failed to read database entry")
+		log.Error().Err(err).Str("search", search).Interface("identifiers", identifiers).Msg("failed to read database entry")
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return middleware.NewError(http.StatusNotFound, "Record not found")
 		}
@@ -107,6 +116,20 @@ func (instance *Postgres) Read(value interface{}, sort, search, identifier strin
 	return nil
 }
 
+// Raw scans the result of a parameterized query into value, for reads the generic methods can't express (E.g full-text search)
+func (instance *Postgres) Raw(value interface{}, query string, values ...interface{}) error {
+	log := logging.FromCtx(context.Background())
+
+	err := instance.db.Raw(query, values...).Scan(value).Error
+	if err != nil {
+		log.Error().Err(err).Str("query", query).Interface("values", values).Msg("failed to run raw query")
+		return err
+	}
+
+	log.Debug().Str("query", query).Msg("fetched raw query")
+	return nil
+}
+
 func (instance *Postgres) Update(value interface{}) error {
 	log := logging.FromCtx(context.Background())
 
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+)
+
+// searchSchema keeps a weighted tsvector of every boardgame up to date, folding accents through the catalog_search configuration
+// Weights: A -> name, B -> publisher, C -> tag, category and mechanism names
+const searchSchema = `
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'catalog_search') THEN
+		CREATE TEXT SEARCH CONFIGURATION catalog_search (COPY = simple);
+		ALTER TEXT SEARCH CONFIGURATION catalog_search ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+	END IF;
+END $$;
+
+ALTER TABLE boardgames ADD COLUMN IF NOT EXISTS search_vector tsvector;
+CREATE INDEX IF NOT EXISTS idx_boardgames_search_vector ON boardgames USING GIN (search_vector);
+
+CREATE OR REPLACE FUNCTION refresh_boardgame_search_vector(bg_id bigint) RETURNS void AS $$
+	UPDATE boardgames SET search_vector =
+		setweight(to_tsvector('catalog_search', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('catalog_search', coalesce(publisher, '')), 'B') ||
+		setweight(to_tsvector('catalog_search', concat_ws(' ',
+			(SELECT string_agg(tag_name, ' ') FROM boardgame_tags WHERE boardgame_id = bg_id),
+			(SELECT string_agg(category_name, ' ') FROM boardgame_categories WHERE boardgame_id = bg_id),
+			(SELECT string_agg(mechanism_name, ' ') FROM boardgame_mechanisms WHERE boardgame_id = bg_id)
+		)), 'C')
+	WHERE id = bg_id;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION boardgame_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+	IF TG_TABLE_NAME = 'boardgames' THEN
+		PERFORM refresh_boardgame_search_vector(NEW.id);
+	ELSIF TG_OP = 'DELETE' THEN
+		PERFORM refresh_boardgame_search_vector(OLD.boardgame_id);
+	ELSE
+		PERFORM refresh_boardgame_search_vector(NEW.boardgame_id);
+	END IF;
+	RETURN NULL;
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS boardgames_search_vector ON boardgames;
+CREATE TRIGGER boardgames_search_vector AFTER INSERT OR UPDATE OF name, publisher ON boardgames
+	FOR EACH ROW EXECUTE FUNCTION boardgame_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS boardgame_tags_search_vector ON boardgame_tags;
+CREATE TRIGGER boardgame_tags_search_vector AFTER INSERT OR DELETE ON boardgame_tags
+	FOR EACH ROW EXECUTE FUNCTION boardgame_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS boardgame_categories_search_vector ON boardgame_categories;
+CREATE TRIGGER boardgame_categories_search_vector AFTER INSERT OR DELETE ON boardgame_categories
+	FOR EACH ROW EXECUTE FUNCTION boardgame_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS boardgame_mechanisms_search_vector ON boardgame_mechanisms;
+CREATE TRIGGER boardgame_mechanisms_search_vector AFTER INSERT OR DELETE ON boardgame_mechanisms
+	FOR EACH ROW EXECUTE FUNCTION boardgame_search_vector_trigger();
+
+SELECT refresh_boardgame_search_vector(id) FROM boardgames WHERE search_vector IS NULL;
+`
+
+// suggestSchema indexes the trigrams of every boardgame name, lowercased and without accents, so typos can still be matched
+// unaccent is only stable, so it is wrapped into an immutable function that indexes accept
+const suggestSchema = `
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION catalog_unaccent(text) RETURNS text AS $$
+	SELECT unaccent('unaccent'::regdictionary, $1);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX IF NOT EXISTS idx_boardgames_name_trgm ON boardgames USING GIN (catalog_unaccent(lower(name)) gin_trgm_ops);
+`
+
+// setupSearch creates the full-text search column, index and triggers, filling the boardgames that existed before, and the name suggestions index
+func setupSearch(db *gorm.DB) error {
+	log := logging.FromCtx(context.Background())
+
+	if err := db.Exec(searchSchema).Error; err != nil {
+		log.Error().Err(err).Msg("failed to setup full-text search")
+		return err
+	}
+
+	if err := db.Exec(suggestSchema).Error; err != nil {
+		log.Error().Err(err).Msg("failed to setup name suggestions")
+		return err
+	}
+
+	log.Debug().Msg("full-text search setup completed")
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/FranciscoBarao/catalog/clients"
 	"github.com/FranciscoBarao/catalog/config"
 	"github.com/FranciscoBarao/catalog/controllers"
 	"github.com/FranciscoBarao/catalog/database"
@@ -28,6 +29,12 @@ import (
 func main() {
 	ctx := context.Background()
 	log := logging.FromCtx(ctx)
+	// Fetch rating-service configs
+	ratingConfig, err := config.NewRatingServiceConfig()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to fetch rating-service env variables")
+	}
+
 	// Fetch DB configs
 	config, err := config.NewPostgresConfig()
 	if err != nil {
@@ -48,7 +55,8 @@ func main() {
 
 	// Initialize Repositories & Services & controllers
 	repositories := repositories.InitRepositories(db)
-	services := services.InitServices(repositories)
+	ratingClient := clients.NewRatingClient(ratingConfig.URL, ratingConfig.Timeout, ratingConfig.Retries)
+	services := services.InitServices(repositories, ratingClient)
 	controllers := controllers.InitControllers(services)
 
 	// Creates routing
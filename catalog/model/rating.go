@@ -12,3 +12,14 @@ type Rating struct {
 func (rating *Rating) SetUsername(username string) {
 	rating.Username = username
 }
+
+// Ranking is the Bayesian average score the rating-service keeps of a boardgame -> Unrated ones have no count and the prior score
+type Ranking struct {
+	ReferenceID string  `json:"reference_id"`
+	Count       int64   `json:"count"`
+	Score       float64 `json:"score"`
+}
+
+func (ranking *Ranking) IsRated() bool {
+	return ranking.Count > 0
+}
@@ -0,0 +1,24 @@
+package model
+
+// BoardgameSearch is a Boardgame matching a full-text search, with its relevance and the matched text highlighted
+type BoardgameSearch struct {
+	Boardgame Boardgame `json:"boardgame"`
+	Rank      float64   `json:"rank"`
+	Highlight string    `json:"highlight"`
+}
+
+func NewBoardgameSearch(boardgame Boardgame, rank float64, highlight string) BoardgameSearch {
+	return BoardgameSearch{
+		Boardgame: boardgame,
+		Rank:      rank,
+		Highlight: highlight,
+	}
+}
+
+// BoardgameSuggestion is a Boardgame whose name resembles the one typed, with how similar both are from 0 to 1
+type BoardgameSuggestion struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Publisher  string  `json:"publisher"`
+	Similarity float64 `json:"similarity"`
+}
@@ -7,6 +7,28 @@ import (
 	"github.com/FranciscoBarao/catalog/model"
 )
 
+const (
+	searchCountQuery = `SELECT count(*) FROM boardgames WHERE deleted_at IS NULL AND search_vector @@ to_tsquery('catalog_search', ?)`
+	searchQuery      = `SELECT id, ts_rank(search_vector, query) AS rank,
+		ts_headline('catalog_search', concat_ws(' - ', name, publisher), query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight
+		FROM boardgames, to_tsquery('catalog_search', ?) query
+		WHERE deleted_at IS NULL AND search_vector @@ query
+		ORDER BY rank DESC, id ASC
+		LIMIT ? OFFSET ?`
+	suggestQuery = `SELECT id, name, publisher, word_similarity(typed, catalog_unaccent(lower(name))) AS similarity
+		FROM boardgames, catalog_unaccent(lower(?)) typed
+		WHERE deleted_at IS NULL AND typed <% catalog_unaccent(lower(name))
+		ORDER BY similarity DESC, name ASC, id ASC
+		LIMIT ?`
+)
+
+// searchHit is the id of a Boardgame matching a full-text search with its rank and highlight
+type searchHit struct {
+	ID        uint
+	Rank      float64
+	Highlight string
+}
+
 type BoardgameRepository struct {
 	db Database
 }
@@ -21,14 +43,108 @@ func (repo *BoardgameRepository) Create(boardgame *model.Boardgame) error {
 	return repo.db.Create(boardgame)
 }
 
-func (repo *BoardgameRepository) GetAll(sort, filterBody, filterValue string) ([]model.Boardgame, error) {
+func (repo *BoardgameRepository) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error) {
 	var bg []model.Boardgame
-	return bg, repo.db.Read(&bg, sort, filterBody, filterValue)
+	return bg, repo.db.Read(&bg, page, sort, filterBody, filterValues...)
+}
+
+// Search fetches a page of the Boardgames matching the tsquery, most relevant first
+func (repo *BoardgameRepository) Search(query string, page *model.Page) ([]model.BoardgameSearch, error) {
+	var total int64
+	if err := repo.db.Raw(&total, searchCountQuery, query); err != nil {
+		return nil, err
+	}
+	page.SetTotal(total)
+
+	var hits []searchHit
+	if err := repo.db.Raw(&hits, searchQuery, query, page.GetLimit(), page.GetOffset()); err != nil {
+		return nil, err
+	}
+
+	results := []model.BoardgameSearch{}
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(hits))
+	byId := make(map[uint]searchHit, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+		byId[hit.ID] = hit
+	}
+	bgs, err := repo.GetByIds(ids) // Keeps the rank order
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bg := range bgs {
+		hit := byId[*bg.GetId()]
+		results = append(results, model.NewBoardgameSearch(bg, hit.Rank, hit.Highlight))
+	}
+	return results, nil
+}
+
+// Suggest fetches the Boardgames whose names most resemble the typed one, tolerating typos and missing accents
+func (repo *BoardgameRepository) Suggest(name string, limit int) ([]model.BoardgameSuggestion, error) {
+	suggestions := []model.BoardgameSuggestion{}
+	return suggestions, repo.db.Raw(&suggestions, suggestQuery, name, limit)
+}
+
+// Count counts the Boardgames matching the filters
+func (repo *BoardgameRepository) Count(filterBody string, filterValues []interface{}) (int64, error) {
+	var total int64
+	return total, repo.db.Raw(&total, idsQuery("SELECT count(*)", "", filterBody), filterValues...)
+}
+
+// GetIdsIn fetches which of the ids belong to Boardgames matching the filters, for orders computed outside the database
+func (repo *BoardgameRepository) GetIdsIn(filterBody string, filterValues []interface{}, ids []uint) ([]uint, error) {
+	var matched []uint
+	return matched, repo.db.Raw(&matched, idsQuery("SELECT id", "id IN ?", filterBody), append([]interface{}{ids}, filterValues...)...)
+}
+
+// GetIdsAfter fetches the ids of the next Boardgames matching the filters, following the id, in order
+func (repo *BoardgameRepository) GetIdsAfter(filterBody string, filterValues []interface{}, after uint, limit int) ([]uint, error) {
+	var ids []uint
+	values := append(append([]interface{}{after}, filterValues...), limit)
+	return ids, repo.db.Raw(&ids, idsQuery("SELECT id", "id > ?", filterBody)+" ORDER BY id LIMIT ?", values...)
+}
+
+// idsQuery builds the query of the Boardgames meeting the condition and matching the filters, whose values follow the condition's
+func idsQuery(selection, condition, filterBody string) string {
+	query := selection + " FROM boardgames WHERE deleted_at IS NULL"
+	if condition != "" {
+		query += " AND " + condition
+	}
+	if filterBody != "" {
+		query += " AND (" + filterBody + ")"
+	}
+	return query
+}
+
+// GetByIds fetches the Boardgames with their associations, in the order of the ids
+func (repo *BoardgameRepository) GetByIds(ids []uint) ([]model.Boardgame, error) {
+	var bgs []model.Boardgame
+	if err := repo.db.Read(&bgs, nil, "", "id IN ?", ids); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uint]model.Boardgame, len(bgs))
+	for _, bg := range bgs {
+		byId[*bg.GetId()] = bg
+	}
+
+	ordered := make([]model.Boardgame, 0, len(ids))
+	for _, id := range ids {
+		if bg, ok := byId[id]; ok {
+			ordered = append(ordered, bg)
+		}
+	}
+	return ordered, nil
 }
 
 func (repo *BoardgameRepository) GetById(id string) (model.Boardgame, error) {
 	var bg model.Boardgame
-	err := repo.db.Read(&bg, "", "id = ?", id)
+	err := repo.db.Read(&bg, nil, "", "id = ?", id)
 
 	var mr *middleware.MalformedRequest
 	if err != nil && errors.As(err, &mr) {
@@ -21,14 +21,14 @@ func (repo *CategoryRepository) Create(category *model.Category) error {
 	return repo.db.Create(category)
 }
 
-func (repo *CategoryRepository) GetAll(sort string) ([]model.Category, error) {
+func (repo *CategoryRepository) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Category, error) {
 	var categories []model.Category
-	return categories, repo.db.Read(&categories, sort, "", "")
+	return categories, repo.db.Read(&categories, page, sort, filterBody, filterValues...)
 }
 
 func (repo *CategoryRepository) Get(name string) (model.Category, error) {
 	var category model.Category
-	err := repo.db.Read(&category, "", "name = ?", name)
+	err := repo.db.Read(&category, nil, "", "name = ?", name)
 
 	var mr *middleware.MalformedRequest
 	if err != nil && errors.As(err, &mr) {
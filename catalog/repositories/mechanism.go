@@ -21,14 +21,14 @@ func (repo *MechanismRepository) Create(mechanism *model.Mechanism) error {
 	return repo.db.Create(mechanism)
 }
 
-func (repo *MechanismRepository) GetAll(sort string) ([]model.Mechanism, error) {
+func (repo *MechanismRepository) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Mechanism, error) {
 	var mechanisms []model.Mechanism
-	return mechanisms, repo.db.Read(&mechanisms, sort, "", "")
+	return mechanisms, repo.db.Read(&mechanisms, page, sort, filterBody, filterValues...)
 }
 
 func (repo *MechanismRepository) Get(name string) (model.Mechanism, error) {
 	var mechanism model.Mechanism
-	err := repo.db.Read(&mechanism, "", "name = ?", name)
+	err := repo.db.Read(&mechanism, nil, "", "name = ?", name)
 
 	var mr *middleware.MalformedRequest
 	if err != nil && errors.As(err, &mr) {
@@ -1,10 +1,13 @@
 package repositories
 
+import "github.com/FranciscoBarao/catalog/model"
+
 //go:generate mockgen --build_flags=--mod=mod -package repositories -destination=database_mock.go . Database
 
 type Database interface {
 	Create(value interface{}) error
-	Read(value interface{}, sort, search, identifier string) error
+	Read(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error
+	Raw(value interface{}, query string, values ...interface{}) error
 	Update(value interface{}) error
 	Delete(value interface{}) error
 	ReplaceAssociatons(model interface{}, association string, values interface{}) error
@@ -21,14 +21,14 @@ func (repo *TagRepository) Create(tag *model.Tag) error {
 	return repo.db.Create(tag)
 }
 
-func (repo *TagRepository) GetAll(sort string) ([]model.Tag, error) {
+func (repo *TagRepository) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Tag, error) {
 	var tags []model.Tag
-	return tags, repo.db.Read(&tags, sort, "", "")
+	return tags, repo.db.Read(&tags, page, sort, filterBody, filterValues...)
 }
 
 func (repo *TagRepository) Get(name string) (model.Tag, error) {
 	var tag model.Tag
-	err := repo.db.Read(&tag, "", "name = ?", name)
+	err := repo.db.Read(&tag, nil, "", "name = ?", name)
 
 	var mr *middleware.MalformedRequest
 	if err != nil && errors.As(err, &mr) {
@@ -2,6 +2,7 @@ package route
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
 
 	"github.com/FranciscoBarao/catalog/controllers"
 )
@@ -15,15 +16,23 @@ func AddBoardGameRouter(router chi.Router, oauthKey string, boardGameControler *
 		router.Post("/api/boardgame", boardGameControler.Create)
 		router.Patch("/api/boardgame/{id}", boardGameControler.Update)
 		router.Delete("/api/boardgame/{id}", boardGameControler.Delete)
-		router.Post("/api/boardgame/{id}/rate", boardGameControler.Rate)
 
 		router.Post("/api/boardgame/{id}/expansion", boardGameControler.Create)
 
 	})
 
+	// Rating layer -> Always authorized, as the username and token are forwarded to the rating-service
+	router.Group(func(router chi.Router) {
+		router.Use(oauth.Authorize(oauthKey, nil))
+
+		router.Post("/api/boardgame/{id}/rate", boardGameControler.Rate)
+	})
+
 	// Public layer
 	router.Group(func(r chi.Router) {
 		router.Get("/api/boardgame", boardGameControler.GetAll)
+		router.Get("/api/boardgame/search", boardGameControler.Search)
+		router.Get("/api/boardgame/suggest", boardGameControler.Suggest)
 		router.Get("/api/boardgame/{id}", boardGameControler.Get)
 	})
 }
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"net/http"
+	"strconv"
 
+	"github.com/FranciscoBarao/catalog/clients"
 	"github.com/FranciscoBarao/catalog/middleware"
 	"github.com/FranciscoBarao/catalog/middleware/logging"
 	"github.com/FranciscoBarao/catalog/model"
@@ -12,27 +14,43 @@ import (
 
 type boardgameRepository interface {
 	Create(boardgame *model.Boardgame) error
-	GetAll(sort, filterBody, filterValue string) ([]model.Boardgame, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error)
+	Search(query string, page *model.Page) ([]model.BoardgameSearch, error)
+	Suggest(name string, limit int) ([]model.BoardgameSuggestion, error)
+	Count(filterBody string, filterValues []interface{}) (int64, error)
+	GetIdsIn(filterBody string, filterValues []interface{}, ids []uint) ([]uint, error)
+	GetIdsAfter(filterBody string, filterValues []interface{}, after uint, limit int) ([]uint, error)
+	GetByIds(ids []uint) ([]model.Boardgame, error)
 	GetById(id string) (model.Boardgame, error)
 	Update(boardgame *model.Boardgame) error
 	DeleteById(boardgame *model.Boardgame) error
 }
 
+// ratingClient sends the ratings of boardgames to the service that holds them
+type ratingClient interface {
+	Create(rating *model.Rating, referenceID, token string) error
+	GetRankings(offset, limit int) ([]model.Ranking, error)
+	GetRankingsByIds(referenceIDs []string) ([]model.Ranking, error)
+	GetPrior() (float64, error)
+}
+
 // Controller contains the service, which contains database-related logic, as an injectable dependency, allowing us to decouple business logic from db logic
 type BoardgameService struct {
 	repo         boardgameRepository
 	tagSvc       *TagService
 	categorySvc  *CategoryService
 	mechanismSvc *MechanismService
+	ratings      ratingClient
 }
 
 // InitBoardgameService initializes the boardgame and the associations controller
-func InitBoardgameService(boardgameRepo *repositories.BoardgameRepository, tagService *TagService, categoryService *CategoryService, mechanismService *MechanismService) *BoardgameService {
+func InitBoardgameService(boardgameRepo *repositories.BoardgameRepository, tagService *TagService, categoryService *CategoryService, mechanismService *MechanismService, ratingClient *clients.RatingClient) *BoardgameService {
 	return &BoardgameService{
 		repo:         boardgameRepo,
 		tagSvc:       tagService,
 		categorySvc:  categoryService,
 		mechanismSvc: mechanismService,
+		ratings:      ratingClient,
 	}
 }
 
@@ -50,8 +68,46 @@ func (svc *BoardgameService) Create(boardgame *model.Boardgame, id string) error
 	return svc.repo.Create(boardgame)
 }
 
-func (svc *BoardgameService) GetAll(sort, filterBody, filterValue string) ([]model.Boardgame, error) {
-	return svc.repo.GetAll(sort, filterBody, filterValue)
+func (svc *BoardgameService) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error) {
+	return svc.repo.GetAll(sort, filterBody, filterValues, page)
+}
+
+// GetAllRanked fetches a page of the Boardgames matching the filters, best ranked by the rating-service first
+// Only the rankings and ids up to the end of the page are read, merging the rated boardgames the rating-service pages by score with the unrated ones, all on the prior score, by id
+func (svc *BoardgameService) GetAllRanked(filterBody string, filterValues []interface{}, page *model.Page) ([]model.Boardgame, error) {
+	total, err := svc.repo.Count(filterBody, filterValues)
+	if err != nil {
+		return nil, err
+	}
+	page.SetTotal(total)
+
+	ranked := newRankedBoardgames(svc.repo, svc.ratings, filterBody, filterValues)
+	var ids []uint
+	for position := 0; position < page.GetOffset()+page.GetLimit() && int64(position) < total; position++ {
+		next, ok, err := ranked.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if position >= page.GetOffset() {
+			ids = append(ids, next.id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return []model.Boardgame{}, nil
+	}
+	return svc.repo.GetByIds(ids)
+}
+
+func (svc *BoardgameService) Search(query string, page *model.Page) ([]model.BoardgameSearch, error) {
+	return svc.repo.Search(query, page)
+}
+
+func (svc *BoardgameService) Suggest(name string, limit int) ([]model.BoardgameSuggestion, error) {
+	return svc.repo.Suggest(name, limit)
 }
 
 func (svc *BoardgameService) GetById(id string) (model.Boardgame, error) {
@@ -86,18 +142,17 @@ func (svc *BoardgameService) DeleteById(id string) error {
 	return svc.repo.DeleteById(&boardgame)
 }
 
-func (svc *BoardgameService) Rate(rating *model.Rating, id, username string) error {
+func (svc *BoardgameService) Rate(rating *model.Rating, id, username, token string) error {
 	// Check if boardgame exists
-	_, err := svc.repo.GetById(id)
+	boardgame, err := svc.repo.GetById(id)
 	if err != nil {
 		return err
 	}
 
 	rating.SetUsername(username)
 
-	// TODO - Redirect rating to another service
-
-	return nil
+	// Ratings are kept by the rating-service, referencing the boardgame by its id
+	return svc.ratings.Create(rating, strconv.FormatUint(uint64(*boardgame.GetId()), 10), token)
 }
 
 // connectBoardgameToExpansion checks if we are dealing with expansions and creates connection to boardgame parent
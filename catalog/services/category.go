@@ -7,7 +7,7 @@ import (
 
 type categoryRepository interface {
 	Create(category *model.Category) error
-	GetAll(sort string) ([]model.Category, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Category, error)
 	Get(name string) (model.Category, error)
 	Delete(category *model.Category) error
 }
@@ -26,8 +26,8 @@ func (svc *CategoryService) Create(category *model.Category) error {
 	return svc.repo.Create(category)
 }
 
-func (svc *CategoryService) GetAll(sort string) ([]model.Category, error) {
-	return svc.repo.GetAll(sort)
+func (svc *CategoryService) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Category, error) {
+	return svc.repo.GetAll(sort, filterBody, filterValues, page)
 }
 
 func (svc *CategoryService) Get(name string) (model.Category, error) {
@@ -7,7 +7,7 @@ import (
 
 type mechanismRepository interface {
 	Create(mechanism *model.Mechanism) error
-	GetAll(sort string) ([]model.Mechanism, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Mechanism, error)
 	Get(name string) (model.Mechanism, error)
 	Delete(mechanism *model.Mechanism) error
 }
@@ -26,8 +26,8 @@ func (svc *MechanismService) Create(mechanism *model.Mechanism) error {
 	return svc.repo.Create(mechanism)
 }
 
-func (svc *MechanismService) GetAll(sort string) ([]model.Mechanism, error) {
-	return svc.repo.GetAll(sort)
+func (svc *MechanismService) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Mechanism, error) {
+	return svc.repo.GetAll(sort, filterBody, filterValues, page)
 }
 
 func (svc *MechanismService) Get(name string) (model.Mechanism, error) {
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/FranciscoBarao/catalog/clients"
+)
+
+// rankedBoardgame is the id of a Boardgame with its ranking score
+type rankedBoardgame struct {
+	id    uint
+	score float64
+}
+
+// rankingStream reads boardgames in ranking order a batch at a time, only when the ones read so far were taken
+type rankingStream struct {
+	fetch  func() ([]rankedBoardgame, bool, error) // Next batch, and whether more may follow
+	buffer []rankedBoardgame
+	done   bool
+}
+
+// peek returns the next boardgame of the stream without taking it, fetching batches until one has any
+func (stream *rankingStream) peek() (rankedBoardgame, bool, error) {
+	for len(stream.buffer) == 0 && !stream.done {
+		batch, more, err := stream.fetch()
+		if err != nil {
+			return rankedBoardgame{}, false, err
+		}
+		stream.buffer, stream.done = batch, !more
+	}
+	if len(stream.buffer) == 0 {
+		return rankedBoardgame{}, false, nil
+	}
+	return stream.buffer[0], true, nil
+}
+
+func (stream *rankingStream) take() {
+	stream.buffer = stream.buffer[1:]
+}
+
+// rankedBoardgames merges the rated boardgames, paged by score by the rating-service, with the unrated ones, paged by id by the database
+// Unrated ones are only read once the rated ones reach the prior score, as every boardgame scored above it comes first
+type rankedBoardgames struct {
+	rated   *rankingStream
+	unrated *rankingStream
+	prior   func() (float64, error)
+}
+
+func newRankedBoardgames(repo boardgameRepository, ratings ratingClient, filterBody string, filterValues []interface{}) *rankedBoardgames {
+	// Rated boardgames, best scored first, keeping those matching the filters
+	offset := 0
+	rated := func() ([]rankedBoardgame, bool, error) {
+		rankings, err := ratings.GetRankings(offset, clients.MaxRankingBatch)
+		if err != nil {
+			return nil, false, err
+		}
+		offset += len(rankings)
+
+		scores := make(map[uint]float64, len(rankings))
+		ids := make([]uint, 0, len(rankings))
+		for _, ranking := range rankings {
+			id, err := strconv.ParseUint(ranking.ReferenceID, 10, 0)
+			if err != nil { // Not a boardgame id
+				continue
+			}
+			scores[uint(id)] = ranking.Score
+			ids = append(ids, uint(id))
+		}
+
+		var batch []rankedBoardgame
+		if len(ids) > 0 {
+			matched, err := repo.GetIdsIn(filterBody, filterValues, ids)
+			if err != nil {
+				return nil, false, err
+			}
+			kept := make(map[uint]bool, len(matched))
+			for _, id := range matched {
+				kept[id] = true
+			}
+			for _, id := range ids { // In the order of the rankings
+				if kept[id] {
+					batch = append(batch, rankedBoardgame{id: id, score: scores[id]})
+				}
+			}
+		}
+		return batch, len(rankings) == clients.MaxRankingBatch, nil
+	}
+
+	// Unrated boardgames matching the filters, by id, all on the prior score
+	var after uint
+	unrated := func() ([]rankedBoardgame, bool, error) {
+		ids, err := repo.GetIdsAfter(filterBody, filterValues, after, clients.MaxRankingBatch)
+		if err != nil || len(ids) == 0 {
+			return nil, false, err
+		}
+		after = ids[len(ids)-1]
+
+		references := make([]string, len(ids))
+		for i, id := range ids {
+			references[i] = strconv.FormatUint(uint64(id), 10)
+		}
+		rankings, err := ratings.GetRankingsByIds(references)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var batch []rankedBoardgame
+		for i, ranking := range rankings { // In the order of the ids
+			if !ranking.IsRated() {
+				batch = append(batch, rankedBoardgame{id: ids[i], score: ranking.Score})
+			}
+		}
+		return batch, len(ids) == clients.MaxRankingBatch, nil
+	}
+
+	// Score of boardgames without ratings, asked once
+	var prior *float64
+	getPrior := func() (float64, error) {
+		if prior == nil {
+			score, err := ratings.GetPrior()
+			if err != nil {
+				return 0, err
+			}
+			prior = &score
+		}
+		return *prior, nil
+	}
+
+	return &rankedBoardgames{
+		rated:   &rankingStream{fetch: rated},
+		unrated: &rankingStream{fetch: unrated},
+		prior:   getPrior,
+	}
+}
+
+// next takes the best ranked boardgame left -> Equal scores keep the lowest id first
+func (ranked *rankedBoardgames) next() (rankedBoardgame, bool, error) {
+	rated, hasRated, err := ranked.rated.peek()
+	if err != nil {
+		return rankedBoardgame{}, false, err
+	}
+	if hasRated {
+		prior, err := ranked.prior()
+		if err != nil {
+			return rankedBoardgame{}, false, err
+		}
+		if rated.score > prior { // Above every unrated boardgame
+			ranked.rated.take()
+			return rated, true, nil
+		}
+	}
+
+	unrated, hasUnrated, err := ranked.unrated.peek()
+	if err != nil {
+		return rankedBoardgame{}, false, err
+	}
+
+	switch {
+	case hasRated && (!hasUnrated || rated.score > unrated.score || rated.score == unrated.score && rated.id < unrated.id):
+		ranked.rated.take()
+		return rated, true, nil
+	case hasUnrated:
+		ranked.unrated.take()
+		return unrated, true, nil
+	}
+	return rankedBoardgame{}, false, nil
+}
@@ -1,6 +1,9 @@
 package services
 
-import "github.com/FranciscoBarao/catalog/repositories"
+import (
+	"github.com/FranciscoBarao/catalog/clients"
+	"github.com/FranciscoBarao/catalog/repositories"
+)
 
 // Repositories contains all the repo structs
 type Services struct {
@@ -11,11 +14,11 @@ type Services struct {
 }
 
 // InitRepositories should be called in main.go
-func InitServices(repositories *repositories.Repositories) *Services {
+func InitServices(repositories *repositories.Repositories, ratingClient *clients.RatingClient) *Services {
 	tagService := InitTagService(repositories.TagRepository)
 	mechanismService := InitMechanismService(repositories.MechanismRepository)
 	categoryService := InitCategoryService(repositories.CategoryRepository)
-	boardgameService := InitBoardgameService(repositories.BoardgameRepository, tagService, categoryService, mechanismService)
+	boardgameService := InitBoardgameService(repositories.BoardgameRepository, tagService, categoryService, mechanismService, ratingClient)
 
 	return &Services{
 		BoardgameService: boardgameService,
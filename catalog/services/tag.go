@@ -7,7 +7,7 @@ import (
 
 type tagRepository interface {
 	Create(tag *model.Tag) error
-	GetAll(sort string) ([]model.Tag, error)
+	GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Tag, error)
 	Get(name string) (model.Tag, error)
 	Delete(tag *model.Tag) error
 }
@@ -26,8 +26,8 @@ func (svc *TagService) Create(tag *model.Tag) error {
 	return svc.repo.Create(tag)
 }
 
-func (svc *TagService) GetAll(sort string) ([]model.Tag, error) {
-	return svc.repo.GetAll(sort)
+func (svc *TagService) GetAll(sort, filterBody string, filterValues []interface{}, page *model.Page) ([]model.Tag, error) {
+	return svc.repo.GetAll(sort, filterBody, filterValues, page)
 }
 
 func (svc *TagService) Get(name string) (model.Tag, error) {
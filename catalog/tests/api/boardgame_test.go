@@ -3,9 +3,11 @@ package tests
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 	"strconv"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/steinfletcher/apitest"
 	"github.com/stretchr/testify/suite"
 
@@ -49,7 +51,7 @@ func (suite *BoardGameSuite) TestPostExpansion() {
 	parentID := uint(u64)
 	parentBg := new(model.Boardgame)
 	suite.base.dbMock.EXPECT().
-		Read(parentBg, "", "id = ?", parentIDStr).
+		Read(parentBg, nil, "", "id = ?", parentIDStr).
 		Return(nil)
 
 	// Boardgame expansion creation Mock
@@ -76,7 +78,7 @@ func (suite *BoardGameSuite) TestGetBoardgame() {
 	bgID := "test"
 	bg := new(model.Boardgame)
 	suite.base.dbMock.EXPECT().
-		Read(bg, "", "id = ?", bgID).
+		Read(bg, nil, "", "id = ?", bgID).
 		Return(nil)
 
 	apitest.New().
@@ -88,11 +90,212 @@ func (suite *BoardGameSuite) TestGetBoardgame() {
 		End()
 }
 
+func (suite *BoardGameSuite) TestGetBoardgamesByAssociations() {
+	suite.base.dbMock.EXPECT().
+		Read(new([]model.Boardgame), model.NewPage(20, 0, ""), "", "(player_number >= ?) AND (id IN (SELECT boardgame_id FROM boardgame_tags WHERE tag_name IN ? GROUP BY boardgame_id HAVING COUNT(DISTINCT tag_name) = ?) AND id IN (SELECT boardgame_id FROM boardgame_mechanisms WHERE mechanism_name IN ? GROUP BY boardgame_id HAVING COUNT(DISTINCT mechanism_name) = ?))",
+			2, []interface{}{"Cooperative", "Family"}, 2, []interface{}{"WorkerPlacement"}, 1).
+		Return(nil)
+
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame").
+		Query("filterBy", "playernumber.ge.2").
+		Query("tags", "Cooperative,Family").
+		Query("mechanisms", "WorkerPlacement").
+		Query("match", "all").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Unknown match
+			HandlerFunc(suite.base.router.ServeHTTP).
+			Get("/api/boardgame").
+			Query("tags", "Cooperative").
+			Query("match", "some").
+			Expect(suite.T()).
+			Status(http.StatusUnprocessableEntity).
+			End()
+}
+
+func (suite *BoardGameSuite) TestGetBoardgamesByRank() {
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), "SELECT count(*) FROM boardgames WHERE deleted_at IS NULL").
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*int64) = 3
+			return nil
+		})
+
+	// Rating-service ranks the second boardgame and a deleted one above the prior, the others have no ratings
+	suite.base.ratingHandler = func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("reference_namespace") != "boardgame":
+			w.WriteHeader(http.StatusBadRequest)
+		case r.URL.Path == "/api/rating/ranking" && query.Get("offset") == "0" && query.Get("limit") == "100":
+			w.Write([]byte(`{"items":[{"reference_id":"2","count":3,"score":8.4},{"reference_id":"9","count":1,"score":7}],"limit":100,"offset":0,"total":2}`))
+		case r.URL.Path == "/api/rating/ranking/batch" && query.Get("reference_ids") == "0":
+			w.Write([]byte(`[{"reference_id":"0","count":0,"score":5}]`))
+		case r.URL.Path == "/api/rating/ranking/batch" && query.Get("reference_ids") == "1,3":
+			w.Write([]byte(`[{"reference_id":"1","count":0,"score":5},{"reference_id":"3","count":0,"score":5}]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+
+	// Only the second of the rated boardgames exists, and the unrated ones are read once the rated ones reach the prior
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), "SELECT id FROM boardgames WHERE deleted_at IS NULL AND id IN ?", []uint{2, 9}).
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*[]uint) = []uint{2}
+			return nil
+		})
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), "SELECT id FROM boardgames WHERE deleted_at IS NULL AND id > ? ORDER BY id LIMIT ?", uint(0), 100).
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*[]uint) = []uint{1, 3}
+			return nil
+		})
+
+	first, second := model.Boardgame{Name: "Catan"}, model.Boardgame{Name: "Azul"}
+	first.ID, second.ID = 1, 2
+	suite.base.dbMock.EXPECT().
+		Read(new([]model.Boardgame), nil, "", "id IN ?", []uint{2, 1}).
+		DoAndReturn(func(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
+			*value.(*[]model.Boardgame) = []model.Boardgame{first, second}
+			return nil
+		})
+
+	expected, _ := json.Marshal(model.NewPaginated([]model.Boardgame{second, first}, &model.Page{Limit: 2, Total: 3}))
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame").
+		Query("sortBy", "rank").
+		Query("limit", "2").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(string(expected)).
+		End()
+
+	// Ranked listings can't be paged by cursor
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame").
+		Query("sortBy", "rank").
+		Query("cursor", "eyJvIjoiIn0").
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *BoardGameSuite) TestGetBoardgamesByRankRetried() {
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), "SELECT count(*) FROM boardgames WHERE deleted_at IS NULL").
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*int64) = 1
+			return nil
+		})
+
+	// Rating-service keeps failing -> Reading rankings is retried and then a bad gateway
+	attempts := 0
+	suite.base.ratingHandler = func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame").
+		Query("sortBy", "rank").
+		Expect(suite.T()).
+		Status(http.StatusBadGateway).
+		End()
+	suite.Equal(2, attempts)
+}
+
+func (suite *BoardGameSuite) TestSearchBoardgames() {
+	bg := model.Boardgame{Name: "Catan", Publisher: "Kosmos", PlayerNumber: 4}
+	bg.ID = 1
+
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), gomock.Any(), "catan:*").
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*int64) = 1
+			return nil
+		})
+	suite.base.dbMock.EXPECT().
+		Raw(gomock.Any(), gomock.Any(), "catan:*", 20, 0).
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			hits := reflect.ValueOf(value).Elem() // Slice of the repository's unexported hits
+			hit := reflect.New(hits.Type().Elem()).Elem()
+			hit.FieldByName("ID").SetUint(1)
+			hit.FieldByName("Rank").SetFloat(0.5)
+			hit.FieldByName("Highlight").SetString("<mark>Catan</mark> - Kosmos")
+			hits.Set(reflect.Append(hits, hit))
+			return nil
+		})
+	suite.base.dbMock.EXPECT().
+		Read(new([]model.Boardgame), nil, "", "id IN ?", []uint{1}).
+		DoAndReturn(func(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
+			*value.(*[]model.Boardgame) = []model.Boardgame{bg}
+			return nil
+		})
+
+	expected, _ := json.Marshal(model.NewPaginated([]model.BoardgameSearch{model.NewBoardgameSearch(bg, 0.5, "<mark>Catan</mark> - Kosmos")}, &model.Page{Limit: 20, Total: 1}))
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame/search").
+		Query("q", "Catan").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(string(expected)).
+		End()
+
+	// Search without words
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame/search").
+		Query("q", "&|!").
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *BoardGameSuite) TestSuggestBoardgames() {
+	suggestions := []model.BoardgameSuggestion{{ID: 1, Name: "Catan", Publisher: "Kosmos", Similarity: 0.6}}
+
+	suite.base.dbMock.EXPECT().
+		Raw(&[]model.BoardgameSuggestion{}, gomock.Any(), "catn", 5).
+		DoAndReturn(func(value interface{}, query string, values ...interface{}) error {
+			*value.(*[]model.BoardgameSuggestion) = suggestions
+			return nil
+		})
+
+	expected, _ := json.Marshal(suggestions)
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame/suggest").
+		Query("q", "  catn ").
+		Query("limit", "5").
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(string(expected)).
+		End()
+
+	// Too short to suggest
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/boardgame/suggest").
+		Query("q", "c").
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
 func (suite *BoardGameSuite) TestDeleteBoardgameSuccess() {
 	bgID := "1"
 	bg := new(model.Boardgame)
 	suite.base.dbMock.EXPECT().
-		Read(bg, "", "id = ?", bgID).
+		Read(bg, nil, "", "id = ?", bgID).
 		Return(nil)
 
 	suite.base.dbMock.EXPECT().
@@ -224,7 +427,7 @@ func (suite *BoardGameSuite) TestPostBoardgameAssociationFailures() {
 	tagName := "test"
 	tag := new(model.Tag)
 	suite.base.dbMock.EXPECT().
-		Read(tag, "", "name = ?", tagName).
+		Read(tag, nil, "", "name = ?", tagName).
 		Return(middleware.NewError(http.StatusNotFound, "Record not found"))
 
 	apitest.New(). // Invalid Struct -> Tag does not previously exist
@@ -249,7 +452,7 @@ func (suite *BoardGameSuite) TestPostBoardgameAssociationFailures() {
 	categoryName := "test"
 	category := new(model.Category)
 	suite.base.dbMock.EXPECT().
-		Read(category, "", "name = ?", categoryName).
+		Read(category, nil, "", "name = ?", categoryName).
 		Return(middleware.NewError(http.StatusNotFound, "Record not found"))
 
 	apitest.New(). // Invalid Struct -> Category does not previously exist
@@ -273,7 +476,7 @@ func (suite *BoardGameSuite) TestPostBoardgameAssociationFailures() {
 	mechName := "test"
 	mech := new(model.Mechanism)
 	suite.base.dbMock.EXPECT().
-		Read(mech, "", "name = ?", mechName).
+		Read(mech, nil, "", "name = ?", mechName).
 		Return(middleware.NewError(http.StatusNotFound, "Record not found"))
 
 	apitest.New(). // Invalid Struct -> Mechanism does not previously exist
@@ -298,7 +501,7 @@ func (suite *BoardGameSuite) TestGetBoardgameFailure() {
 	bgID := "1"
 	bg := new(model.Boardgame)
 	suite.base.dbMock.EXPECT().
-		Read(bg, "", "id = ?", bgID).
+		Read(bg, nil, "", "id = ?", bgID).
 		Return(middleware.NewError(http.StatusNotFound, "Boardgame not found with name: "+bgID))
 
 	// Record not found
@@ -315,7 +518,7 @@ func (suite *BoardGameSuite) TestDeleteBoardgameFailure() {
 	bgID := "1"
 	bg := new(model.Boardgame)
 	suite.base.dbMock.EXPECT().
-		Read(bg, "", "id = ?", bgID).
+		Read(bg, nil, "", "id = ?", bgID).
 		Return(middleware.NewError(http.StatusNotFound, "Boardgame not found with id: "+bgID))
 
 	// Record not found
@@ -331,3 +534,83 @@ func (suite *BoardGameSuite) TestDeleteBoardgameFailure() {
 func TestBoardGameSuite(t *testing.T) {
 	suite.Run(t, new(BoardGameSuite))
 }
+
+func (suite *BoardGameSuite) TestRateBoardgame() {
+	suite.base.dbMock.EXPECT().
+		Read(new(model.Boardgame), nil, "", "id = ?", "1").
+		DoAndReturn(func(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
+			value.(*model.Boardgame).ID = 1
+			return nil
+		})
+
+	// Rating-service receives the rating of the boardgame on behalf of the token's owner
+	suite.base.ratingHandler = func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Method != http.MethodPost || r.URL.Path != "/api/rating" ||
+			r.Header.Get("Authorization") != "Bearer "+suite.base.oauthHeader || body["username"] != testUsername ||
+			body["reference_namespace"] != "boardgame" || body["reference_id"] != "1" || body["value"] != float64(7) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","username":"tester","reference_namespace":"boardgame","reference_id":"1","value":7}`))
+	}
+
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Post("/api/boardgame/1/rate").
+		JSON(`{"value": 7}`).
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"username":"tester","value":7}`).
+		End()
+}
+
+func (suite *BoardGameSuite) TestRateBoardgameFailure() {
+	suite.base.dbMock.EXPECT().
+		Read(new(model.Boardgame), nil, "", "id = ?", "1").
+		DoAndReturn(func(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
+			value.(*model.Boardgame).ID = 1
+			return nil
+		}).
+		Times(2)
+
+	// Rating-service rejects the rating -> Its status and message reach the caller
+	suite.base.ratingHandler = func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"Status":409,"Message":"Rating already exists"}`, http.StatusConflict)
+	}
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Post("/api/boardgame/1/rate").
+		JSON(`{"value": 7}`).
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusConflict).
+		End()
+
+	// Rating-service fails -> Not retried, as the rating may have been created, and a bad gateway
+	attempts := 0
+	suite.base.ratingHandler = func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Post("/api/boardgame/1/rate").
+		JSON(`{"value": 7}`).
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusBadGateway).
+		End()
+	suite.Equal(1, attempts)
+
+	// Without a token
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Post("/api/boardgame/1/rate").
+		JSON(`{"value": 7}`).
+		Expect(suite.T()).
+		Status(http.StatusUnauthorized).
+		End()
+}
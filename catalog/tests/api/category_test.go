@@ -47,8 +47,8 @@ func (suite *CategorySuite) TestGetCategory() {
 	categoryName := "test"
 	category := new(model.Category)
 	suite.base.dbMock.EXPECT().
-		Read(category, "", "name = ?", categoryName).
-		Do(func(category *model.Category, sort, query, field string) error {
+		Read(category, nil, "", "name = ?", categoryName).
+		Do(func(category *model.Category, page *model.Page, sort, query, field string) error {
 			category.Name = categoryName
 			return nil
 		}).
@@ -68,7 +68,7 @@ func (suite *CategorySuite) TestDeleteCategory() {
 	categoryName := "test"
 	category := new(model.Category)
 	suite.base.dbMock.EXPECT().
-		Read(category, "", "name = ?", categoryName).
+		Read(category, nil, "", "name = ?", categoryName).
 		Return(nil)
 
 	suite.base.dbMock.EXPECT().
@@ -160,7 +160,7 @@ func (suite *CategorySuite) TestGetCategoryFailure() {
 	categoryName := "test"
 	category := new(model.Category)
 	suite.base.dbMock.EXPECT().
-		Read(category, "", "name = ?", categoryName).
+		Read(category, nil, "", "name = ?", categoryName).
 		Return(middleware.NewError(http.StatusNotFound, "Category not found with name: "+categoryName))
 
 	// Record not found
@@ -177,7 +177,7 @@ func (suite *CategorySuite) TestDeleteCategoryFailure() {
 	categoryName := "test"
 	category := new(model.Category)
 	suite.base.dbMock.EXPECT().
-		Read(category, "", "name = ?", categoryName).
+		Read(category, nil, "", "name = ?", categoryName).
 		Return(middleware.NewError(http.StatusNotFound, "Category not found with name: "+categoryName))
 
 	// Record not found
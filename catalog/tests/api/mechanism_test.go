@@ -47,8 +47,8 @@ func (suite *MechanismSuite) TestGetMechanism() {
 	mechName := "test"
 	mech := new(model.Mechanism)
 	suite.base.dbMock.EXPECT().
-		Read(mech, "", "name = ?", mechName).
-		Do(func(mech *model.Mechanism, sort, query, field string) error {
+		Read(mech, nil, "", "name = ?", mechName).
+		Do(func(mech *model.Mechanism, page *model.Page, sort, query, field string) error {
 			mech.Name = mechName
 			return nil
 		}).
@@ -69,7 +69,7 @@ func (suite *MechanismSuite) TestDeleteMechanism() {
 	mechName := "test"
 	mech := new(model.Mechanism)
 	suite.base.dbMock.EXPECT().
-		Read(mech, "", "name = ?", mechName).
+		Read(mech, nil, "", "name = ?", mechName).
 		Return(nil)
 
 	suite.base.dbMock.EXPECT().
@@ -161,7 +161,7 @@ func (suite *MechanismSuite) TestGetMechanismFailure() {
 	mechName := "test"
 	mech := new(model.Mechanism)
 	suite.base.dbMock.EXPECT().
-		Read(mech, "", "name = ?", mechName).
+		Read(mech, nil, "", "name = ?", mechName).
 		Return(middleware.NewError(http.StatusNotFound, "Mechanism not found with name: "+mechName))
 
 	// Record not found
@@ -178,7 +178,7 @@ func (suite *MechanismSuite) TestDeleteMechanismFailure() {
 	mechName := "test"
 	mech := new(model.Mechanism)
 	suite.base.dbMock.EXPECT().
-		Read(mech, "", "name = ?", mechName).
+		Read(mech, nil, "", "name = ?", mechName).
 		Return(middleware.NewError(http.StatusNotFound, "Mechanism not found with name: "+mechName))
 
 	// Record not found
@@ -2,11 +2,16 @@ package tests
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
 	"github.com/golang/mock/gomock"
 
+	"github.com/FranciscoBarao/catalog/clients"
 	"github.com/FranciscoBarao/catalog/controllers"
 	"github.com/FranciscoBarao/catalog/middleware/logging"
 	"github.com/FranciscoBarao/catalog/repositories"
@@ -14,12 +19,17 @@ import (
 	"github.com/FranciscoBarao/catalog/services"
 )
 
-const oauthKey = "secret-key"
+const (
+	oauthKey     = "secret-key"
+	testUsername = "tester"
+)
 
 type Base struct {
 	router      *chi.Mux
 	oauthHeader string
 	dbMock      *repositories.MockDatabase
+	// ratingHandler answers the requests sent to the rating-service
+	ratingHandler http.HandlerFunc
 }
 
 // Prepares test environment
@@ -33,9 +43,27 @@ func NewBase(t *testing.T) *Base {
 	// Fetch Oauth Key
 	//oauthKey, _ := os.LookupEnv("OAUTH_KEY")
 
+	// Token of the test user, signed with the same key the routes use
+	token, err := oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(oauthKey))).CryptToken(&oauth.Token{
+		CreationDate: time.Now().UTC(),
+		ExpiresIn:    time.Hour,
+		Claims:       map[string]string{"username": testUsername},
+		TokenType:    oauth.BearerToken,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake rating-service, answering through the handler each test sets
+	base := &Base{}
+	ratingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base.ratingHandler(w, r)
+	}))
+	t.Cleanup(ratingServer.Close)
+
 	// Set Repositories & Controllers & Services
 	repositories := repositories.InitRepositories(mock)
-	services := services.InitServices(repositories)
+	services := services.InitServices(repositories, clients.NewRatingClient(ratingServer.URL, time.Second, 1))
 	controllers := controllers.InitControllers(services)
 
 	// Adds Routers
@@ -46,9 +74,8 @@ func NewBase(t *testing.T) *Base {
 	route.AddMechanismRouter(router, oauthKey, controllers.MechanismController)
 
 	log.Debug().Msg("setup complete")
-	return &Base{
-		router:      router,
-		oauthHeader: oauthKey,
-		dbMock:      mock,
-	}
+	base.router = router
+	base.oauthHeader = token
+	base.dbMock = mock
+	return base
 }
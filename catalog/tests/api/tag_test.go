@@ -48,8 +48,8 @@ func (suite *TagSuite) TestGet() {
 	tagName := "test"
 	tag := new(model.Tag)
 	suite.base.dbMock.EXPECT().
-		Read(tag, "", "name = ?", tagName).
-		Do(func(tag *model.Tag, sort, query, field string) error {
+		Read(tag, nil, "", "name = ?", tagName).
+		Do(func(tag *model.Tag, page *model.Page, sort, query, field string) error {
 			tag.Name = tagName
 			return nil
 		}).
@@ -65,11 +65,58 @@ func (suite *TagSuite) TestGet() {
 		End()
 }
 
+func (suite *TagSuite) TestGetAllFiltered() {
+	suite.base.dbMock.EXPECT().
+		Read(new([]model.Tag), model.NewPage(20, 0, ""), "", "(name LIKE ?) OR (name = ?)", "%co%", "Family").
+		Return(nil)
+
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/tag").
+		Query("filterBy", "name.co|name.eq.Family").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+}
+
+func (suite *TagSuite) TestGetAllPaginated() {
+	suite.base.dbMock.EXPECT().
+		Read(new([]model.Tag), model.NewPage(1, 0, "cursor"), "", "").
+		DoAndReturn(func(value interface{}, page *model.Page, sort, search string, identifiers ...interface{}) error {
+			*value.(*[]model.Tag) = []model.Tag{*model.NewTag("Family")}
+			page.SetTotal(2)
+			page.SetNextCursor("next")
+			return nil
+		})
+
+	apitest.New().
+		HandlerFunc(suite.base.router.ServeHTTP).
+		Get("/api/tag").
+		Query("limit", "1").
+		Query("cursor", "cursor").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		Body(`{"items": [{"name": "Family"}], "limit": 1, "next_cursor": "next", "total": 2}`).
+		End()
+
+	apitest.New(). // Offset and cursor together
+			HandlerFunc(suite.base.router.ServeHTTP).
+			Get("/api/tag").
+			Query("offset", "1").
+			Query("cursor", "cursor").
+			Header("Authorization", "Bearer "+suite.base.oauthHeader).
+			Expect(suite.T()).
+			Status(http.StatusUnprocessableEntity).
+			End()
+}
+
 func (suite *TagSuite) TestDelete() {
 	tagName := "test"
 	tag := new(model.Tag)
 	suite.base.dbMock.EXPECT().
-		Read(tag, "", "name = ?", tagName).
+		Read(tag, nil, "", "name = ?", tagName).
 		Return(nil)
 
 	suite.base.dbMock.EXPECT().
@@ -161,7 +208,7 @@ func (suite *TagSuite) TestGetFailure() {
 	tagName := "test"
 	tag := new(model.Tag)
 	suite.base.dbMock.EXPECT().
-		Read(tag, "", "name = ?", tagName).
+		Read(tag, nil, "", "name = ?", tagName).
 		Return(middleware.NewError(http.StatusNotFound, "Tag not found with name: "+tagName))
 
 	// Record not found
@@ -178,7 +225,7 @@ func (suite *TagSuite) TestDeleteFailure() {
 	tagName := "test"
 	tag := new(model.Tag)
 	suite.base.dbMock.EXPECT().
-		Read(tag, "", "name = ?", tagName).
+		Read(tag, nil, "", "name = ?", tagName).
 		Return(middleware.NewError(http.StatusNotFound, "Tag not found with name: "+tagName))
 
 	// Record not found
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/steinfletcher/apitest"
@@ -24,8 +25,8 @@ func (suite *UtilSuite) SetupSuite() {
 func (suite *UtilSuite) TestGetFilters() {
 	apitest.New(). // name.a -> Names that contain letter a
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			body, value, err := utils.GetFilters(model.Boardgame{}, "name.a")
-			if err != nil || body != "name LIKE ?" || value != "%a%" {
+			body, values, err := utils.GetFilters(model.Boardgame{}, "name.a")
+			if err != nil || body != "name LIKE ?" || !reflect.DeepEqual(values, []interface{}{"%a%"}) {
 				w.WriteHeader(http.StatusBadRequest)
 			}
 
@@ -39,8 +40,8 @@ func (suite *UtilSuite) TestGetFilters() {
 
 	apitest.New(). // playernumber.lt.5 -> Player Number lower than 5
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			body, value, err := utils.GetFilters(model.Boardgame{}, "playernumber.lt.5")
-			if err != nil || body != "playernumber < ?" || value != "5" {
+			body, values, err := utils.GetFilters(model.Boardgame{}, "playernumber.lt.5")
+			if err != nil || body != "player_number < ?" || !reflect.DeepEqual(values, []interface{}{5}) {
 				w.WriteHeader(http.StatusBadRequest)
 			}
 			w.WriteHeader(http.StatusOK)
@@ -53,8 +54,53 @@ func (suite *UtilSuite) TestGetFilters() {
 
 	apitest.New(). // No filter
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			body, value, err := utils.GetFilters(model.Boardgame{}, "")
-			if err != nil || body != "" || value != "" {
+			body, values, err := utils.GetFilters(model.Boardgame{}, "")
+			if err != nil || body != "" || values != nil {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+}
+
+func (suite *UtilSuite) TestGetCompoundFilters() {
+	apitest.New(). // AND -> Quoted publisher with spaces and a minimum Player Number
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, values, err := utils.GetFilters(model.Boardgame{}, `publisher.eq."Days of Wonder",player_number.ge.2`)
+			if err != nil || body != "publisher = ? AND player_number >= ?" || !reflect.DeepEqual(values, []interface{}{"Days of Wonder", 2}) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // OR -> AND groups are joined by OR
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, values, err := utils.GetFilters(model.Boardgame{}, `name."Dr. Eureka"|publisher.ne.a,playernumber.gt.4`)
+			if err != nil || body != "(name LIKE ?) OR (publisher <> ? AND player_number > ?)" || !reflect.DeepEqual(values, []interface{}{"%Dr. Eureka%", "a", 4}) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // in, between & isnull
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, values, err := utils.GetFilters(model.Boardgame{}, `playernumber.in.(2,4),name.in.(a,"b, c"),playernumber.between.(1,6),boardgameid.isnull.true`)
+			expected := []interface{}{[]interface{}{2, 4}, []interface{}{"a", "b, c"}, 1, 6}
+			if err != nil || body != "player_number IN ? AND name IN ? AND player_number BETWEEN ? AND ? AND boardgame_id IS NULL" || !reflect.DeepEqual(values, expected) {
 				w.WriteHeader(http.StatusBadRequest)
 			}
 			w.WriteHeader(http.StatusOK)
@@ -119,7 +165,7 @@ func (suite *UtilSuite) TestFiltersFailure() {
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, _, err := utils.GetFilters(model.Boardgame{}, "test.a")       // Unknown field
 			_, _, err2 := utils.GetFilters(model.Boardgame{}, "price.lt.a")  // Incorrect type
-			_, _, err3 := utils.GetFilters(model.Boardgame{}, "name.eq.a")   // Incorrect type
+			_, _, err3 := utils.GetFilters(model.Boardgame{}, "name.gt.a")   // Incorrect type
 			_, _, err4 := utils.GetFilters(model.Boardgame{}, "price.10")    // Incorrect type
 			_, _, err5 := utils.GetFilters(model.Boardgame{}, "name.test_a") // Incorrect type
 			if err != nil && err2 != nil && err3 != nil && err4 != nil && err5 != nil {
@@ -134,11 +180,189 @@ func (suite *UtilSuite) TestFiltersFailure() {
 		End()
 }
 
+func (suite *UtilSuite) TestCompoundFiltersFailure() {
+	apitest.New(). // Every clause must be valid -> name.a,test.a || name.a|
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := utils.GetFilters(model.Boardgame{}, "name.a,test.a")
+			_, _, err2 := utils.GetFilters(model.Boardgame{}, "name.a|")
+			if err != nil && err2 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	apitest.New(). // Values must be quoted correctly -> name."a || name.eq.a.b || name."a"b"
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := utils.GetFilters(model.Boardgame{}, `name."a`)
+			_, _, err2 := utils.GetFilters(model.Boardgame{}, `name.eq.a.b`)
+			_, _, err3 := utils.GetFilters(model.Boardgame{}, `name."a"b"`)
+			if err != nil && err2 != nil && err3 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	apitest.New(). // in & between require lists, between exactly two values -> in.2 || between.(1) || isnull.maybe
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := utils.GetFilters(model.Boardgame{}, "playernumber.in.2")
+			_, _, err2 := utils.GetFilters(model.Boardgame{}, "playernumber.between.(1)")
+			_, _, err3 := utils.GetFilters(model.Boardgame{}, "boardgameid.isnull.maybe")
+			_, _, err4 := utils.GetFilters(model.Boardgame{}, "name.between.(a,b)")
+			if err != nil && err2 != nil && err3 != nil && err4 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *UtilSuite) TestGetAssociationFilters() {
+	apitest.New(). // tags=Cooperative,Family -> Any of the tags
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, values, err := utils.GetAssociationFilters(model.Boardgame{}, map[string]string{"tags": "Cooperative, Family,Cooperative"}, "")
+			if err != nil || body != "id IN (SELECT boardgame_id FROM boardgame_tags WHERE tag_name IN ?)" || !reflect.DeepEqual(values, []interface{}{[]interface{}{"Cooperative", "Family"}}) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Malformed values -> tags=a,,b || categories=a_b
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := utils.GetAssociationFilters(model.Boardgame{}, map[string]string{"tags": "a,,b"}, "")
+			_, _, err2 := utils.GetAssociationFilters(model.Boardgame{}, map[string]string{"categories": "a_b"}, "all")
+			if err != nil && err2 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *UtilSuite) TestGetPage() {
+	apitest.New(). // No parameters -> Default limit
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, err := utils.GetPage("", "", "")
+			if err != nil || !reflect.DeepEqual(page, model.NewPage(20, 0, "")) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // limit=0 || limit=101 || limit=a || offset=-1
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := utils.GetPage("0", "", "")
+			_, err2 := utils.GetPage("101", "", "")
+			_, err3 := utils.GetPage("a", "", "")
+			_, err4 := utils.GetPage("", "-1", "")
+			if err != nil && err2 != nil && err3 != nil && err4 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *UtilSuite) TestGetSearchQuery() {
+	apitest.New(). // Words are prefix matched and operators dropped
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query, err := utils.GetSearchQuery("Catan: Seafarers & Ça|va")
+			if err != nil || query != "catan:* & seafarers:* & ça:* & va:*" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Empty || No words || Too many words
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := utils.GetSearchQuery("")
+			_, err2 := utils.GetSearchQuery(":* !")
+			_, err3 := utils.GetSearchQuery("a b c d e f g h i j k")
+			if err != nil && err2 != nil && err3 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+func (suite *UtilSuite) TestGetSuggestQuery() {
+	apitest.New(). // Whitespace collapsed and default limit
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, limit, err := utils.GetSuggestQuery("  Tiket  to ride ", "")
+			if err != nil || name != "Tiket to ride" || limit != 10 {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Too short || Limit too big || Limit not a number
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, err := utils.GetSuggestQuery(" a ", "")
+			_, _, err2 := utils.GetSuggestQuery("catan", "26")
+			_, _, err3 := utils.GetSuggestQuery("catan", "ten")
+			if err != nil && err2 != nil && err3 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
 func (suite *UtilSuite) TestGetSorts() {
 	apitest.New(). //
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			sort, err := utils.GetSort(model.Boardgame{}, "name.asc")
-			if err != nil || sort != "name asc" {
+			if err != nil || sort != "name asc, id asc" {
 				w.WriteHeader(http.StatusBadRequest)
 			}
 			w.WriteHeader(http.StatusOK)
@@ -152,7 +376,7 @@ func (suite *UtilSuite) TestGetSorts() {
 	apitest.New(). //
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			sort, err := utils.GetSort(model.Boardgame{}, "playernumber.desc")
-			if err != nil || sort != "playernumber desc" {
+			if err != nil || sort != "player_number desc, id asc" {
 				w.WriteHeader(http.StatusBadRequest)
 			}
 			w.WriteHeader(http.StatusOK)
@@ -178,6 +402,50 @@ func (suite *UtilSuite) TestGetSorts() {
 		End()
 }
 
+func (suite *UtilSuite) TestGetMultipleSorts() {
+	apitest.New(). // Several fields, already ending on the primary key
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sort, err := utils.GetSort(model.Boardgame{}, "publisher.asc,name.asc,id.desc")
+			if err != nil || sort != "publisher asc, name asc, id desc" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Fields of gorm.Model
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sort, err := utils.GetSort(model.Boardgame{}, "created_at.desc,updatedat.asc")
+			if err != nil || sort != "created_at desc, updated_at asc, id asc" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+
+	apitest.New(). // Primary key that is not an id
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sort, err := utils.GetSort(model.Tag{}, "name.desc")
+			if err != nil || sort != "name desc" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusOK).
+		End()
+}
+
 func (suite *UtilSuite) TestSortsFailure() {
 	apitest.New(). // Different number of allowed Fields (2) -> a.a.a || a
 			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -239,6 +507,23 @@ func (suite *UtilSuite) TestSortsFailure() {
 		Expect(suite.T()).
 		Status(http.StatusUnprocessableEntity).
 		End()
+
+	apitest.New(). // Fields can't be repeated, empty or too many -> name.asc,name.desc || name.asc, || a.asc,b.asc,c.asc,d.asc,e.asc,f.asc
+			HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := utils.GetSort(model.Boardgame{}, "name.asc,name.desc")
+			_, err2 := utils.GetSort(model.Boardgame{}, "name.asc,")
+			_, err3 := utils.GetSort(model.Boardgame{}, "name.asc,publisher.asc,playernumber.asc,created_at.asc,updated_at.asc,id.asc")
+			_, err4 := utils.GetSort(model.Boardgame{}, "deleted_at.asc") // Unsortable field
+			if err != nil && err2 != nil && err3 != nil && err4 != nil {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			}
+			w.WriteHeader(http.StatusOK)
+		}).
+		Get("").
+		Header("Authorization", "Bearer "+suite.base.oauthHeader).
+		Expect(suite.T()).
+		Status(http.StatusUnprocessableEntity).
+		End()
 }
 
 func TestUtilSuite(t *testing.T) {
@@ -71,18 +71,45 @@ func (suite *PostgresSuite) TestGet() {
 	suite.InsertEntry(insertBg)
 
 	var readBg []model.Boardgame
-	err := suite.postgres.Read(&readBg, "", "name LIKE ?", "name-0%")
+	err := suite.postgres.Read(&readBg, nil, "", "name LIKE ?", "name-0%")
 	suite.Assert().NoError(err)
 	suite.Assert().Len(readBg, 2)
 }
 
+func (suite *PostgresSuite) TestReadPage() {
+	for _, name := range []string{"page-03", "page-01", "page-02"} {
+		suite.InsertEntry(&model.Boardgame{Name: name, Publisher: "publisher", PlayerNumber: 1})
+	}
+
+	var first []model.Boardgame
+	page := model.NewPage(2, 0, "")
+	err := suite.postgres.Read(&first, page, "name asc", "name LIKE ?", "page-0%")
+	suite.Require().NoError(err)
+	suite.Assert().Len(first, 2)
+	suite.Assert().Equal("page-01", first[0].Name)
+	suite.Assert().Equal(int64(3), page.Total)
+	suite.Require().NotEmpty(page.NextCursor)
+
+	var second []model.Boardgame
+	next := model.NewPage(2, 0, page.NextCursor)
+	err = suite.postgres.Read(&second, next, "name asc", "name LIKE ?", "page-0%")
+	suite.Require().NoError(err)
+	suite.Assert().Len(second, 1)
+	suite.Assert().Equal("page-03", second[0].Name)
+	suite.Assert().Empty(next.NextCursor)
+
+	var other []model.Boardgame
+	err = suite.postgres.Read(&other, model.NewPage(2, 0, page.NextCursor), "name desc", "name LIKE ?", "page-0%")
+	suite.Assert().Error(err) // Cursor belongs to another order
+}
+
 func (suite *PostgresSuite) TestUpdate() {
 	name := "updateName"
 	insertBg := &model.Boardgame{Name: name, Publisher: "pub1", PlayerNumber: 1}
 	suite.InsertEntry(insertBg)
 
 	var bg model.Boardgame
-	err := suite.postgres.Read(&bg, "", "name = ?", name)
+	err := suite.postgres.Read(&bg, nil, "", "name = ?", name)
 	suite.Assert().NoError(err)
 	suite.Assert().Equal(insertBg.Publisher, bg.Publisher)
 
@@ -91,7 +118,7 @@ func (suite *PostgresSuite) TestUpdate() {
 	err = suite.postgres.Update(&bg)
 	suite.Assert().NoError(err)
 
-	err = suite.postgres.Read(&bg, "", "name = ?", name)
+	err = suite.postgres.Read(&bg, nil, "", "name = ?", name)
 	suite.Assert().NoError(err)
 	suite.Assert().Equal(newPublisher, bg.Publisher)
 }
@@ -102,7 +129,7 @@ func (suite *PostgresSuite) TestDelete() {
 	suite.InsertEntry(insertBg)
 
 	var readBg *model.Boardgame
-	err := suite.postgres.Read(&readBg, "", "name = ?", name)
+	err := suite.postgres.Read(&readBg, nil, "", "name = ?", name)
 	suite.Assert().NoError(err)
 
 	err = suite.postgres.Delete(readBg)
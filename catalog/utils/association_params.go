@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+
+	"github.com/FranciscoBarao/catalog/middleware"
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+)
+
+const (
+	matchAny         = "any" // Entries related to at least one of the values
+	matchAll         = "all" // Entries related to every one of the values
+	maxAssociations  = 20    // Maximum number of values accepted per association
+	associationSplit = ","   // Values of an association are separated by a comma
+)
+
+var schemaCache = &sync.Map{}
+
+// Examples of association filters that work:
+// tags=Cooperative				---> id IN (SELECT boardgame_id FROM boardgame_tags WHERE tag_name IN ?)
+// tags=Cooperative,Family&match=all	---> id IN (SELECT boardgame_id FROM boardgame_tags WHERE tag_name IN ? GROUP BY boardgame_id HAVING COUNT(DISTINCT tag_name) = ?)
+// GetAssociationFilters compiles the many2many associations (E.g tags -> Cooperative,Family) into parameterized subqueries over their join tables
+func GetAssociationFilters(model interface{}, associations map[string]string, match string) (string, []interface{}, error) {
+	log := logging.FromCtx(context.Background())
+
+	if match == "" {
+		match = matchAny
+	}
+	if match != matchAny && match != matchAll {
+		log.Error().Str("match", match).Msg("association match malformed")
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed match query parameter, should be any or all")
+	}
+
+	modelSchema, err := schema.Parse(model, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		log.Error().Err(err).Interface("model", model).Msg("failed to parse model schema")
+		return "", nil, err
+	}
+
+	var bodies []string
+	var values []interface{}
+	for _, relationship := range modelSchema.Relationships.Many2Many {
+		raw, ok := associations[strings.ToLower(relationship.Name)]
+		if !ok || raw == "" {
+			continue
+		}
+
+		names, err := parseAssociationValues(raw)
+		if err != nil {
+			return "", nil, err
+		}
+
+		body, value := compileAssociation(modelSchema, relationship, names, match)
+		bodies = append(bodies, body)
+		values = append(values, value...)
+	}
+
+	log.Debug().Interface("associations", associations).Str("match", match).Msg("filtering by associations")
+	return strings.Join(bodies, " AND "), values, nil
+}
+
+// CombineFilters joins two parameterized queries so that both must match
+func CombineFilters(body string, values []interface{}, otherBody string, otherValues []interface{}) (string, []interface{}) {
+	if body == "" {
+		return otherBody, otherValues
+	}
+	if otherBody == "" {
+		return body, values
+	}
+	return "(" + body + ") AND (" + otherBody + ")", append(append([]interface{}{}, values...), otherValues...)
+}
+
+// parseAssociationValues splits the comma separated names, removing duplicates and validating each one
+func parseAssociationValues(raw string) ([]interface{}, error) {
+	log := logging.FromCtx(context.Background())
+
+	seen := make(map[string]bool)
+	var names []interface{}
+	for _, name := range strings.Split(raw, associationSplit) {
+		name = strings.TrimSpace(name)
+		if name == "" || !isFilterableString(name) {
+			log.Error().Str("association", raw).Msg("association value malformed")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed association query parameter, values can't be empty or contain special characters")
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	if len(names) > maxAssociations {
+		log.Error().Str("association", raw).Int("max_associations", maxAssociations).Msg("too many association values")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed association query parameter, too many values")
+	}
+	return names, nil
+}
+
+// compileAssociation builds the subquery selecting the model ids related to any or all of the names
+func compileAssociation(modelSchema *schema.Schema, relationship *schema.Relationship, names []interface{}, match string) (string, []interface{}) {
+	var ownColumn, otherColumn string
+	for _, reference := range relationship.References {
+		if reference.OwnPrimaryKey {
+			ownColumn = reference.ForeignKey.DBName // E.g boardgame_id
+		} else {
+			otherColumn = reference.ForeignKey.DBName // E.g tag_name
+		}
+	}
+
+	body := modelSchema.PrioritizedPrimaryField.DBName + " IN (SELECT " + ownColumn + " FROM " + relationship.JoinTable.Table +
+		" WHERE " + otherColumn + " IN ?"
+	if match == matchAny {
+		return body + ")", []interface{}{names}
+	}
+	return body + " GROUP BY " + ownColumn + " HAVING COUNT(DISTINCT " + otherColumn + ") = ?)", []interface{}{names, len(names)}
+}
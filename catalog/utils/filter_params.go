@@ -7,109 +7,288 @@ import (
 	"strconv"
 	"strings"
 
+	"gorm.io/gorm/schema"
+
 	"github.com/FranciscoBarao/catalog/middleware"
 	"github.com/FranciscoBarao/catalog/middleware/logging"
 )
 
+const (
+	andSeparator  = ','  // Clauses separated by a comma must all match
+	orSeparator   = '|'  // Groups separated by a pipe are alternatives
+	maxClauses    = 20   // Maximum number of clauses accepted in one filterBy
+	quoteChar     = '"'  // Quoted values may contain dots, commas, pipes and parentheses
+	escapeChar    = '\\' // Escapes a quote or a backslash inside a quoted value
+	listOpenChar  = '('  // Opens the list of values of in/between
+	listCloseChar = ')'  // Closes the list of values of in/between
+)
+
+// filterClause is a single field.operator.value expression
+type filterClause struct {
+	field    string
+	operator string
+	values   []string
+}
+
 // Examples of filters that work:
-// name.a 		---> name LIKE ?    %a%
-// price.le.10  ---> price <= ?     10
-// name.eq.asd  ---> name == ?     asd
-// GetFilters gets the filters
-func GetFilters(model interface{}, filterBy string) (string, string, error) {
+// name.a 							---> name LIKE ?					%a%
+// playernumber.le.10  				---> player_number <= ?				10
+// publisher.eq."Days of Wonder"	---> publisher = ?					Days of Wonder
+// playernumber.in.(2,4)			---> player_number IN ?				[2 4]
+// playernumber.between.(2,4)		---> player_number BETWEEN ? AND ?	2 4
+// boardgameid.isnull.true			---> boardgame_id IS NULL
+// publisher.a,playernumber.ge.2	---> publisher LIKE ? AND player_number >= ?
+// name.a|name.b					---> (name LIKE ?) OR (name LIKE ?)
+// GetFilters validates filterBy against the model and compiles it into a parameterized query and its values
+func GetFilters(model interface{}, filterBy string) (string, []interface{}, error) {
 	if filterBy == "" {
-		return "", "", nil // No filter -> No error
+		return "", nil, nil // No filter -> No error
 	}
 
 	logging.FromCtx(context.Background()).Debug().Str("filter_by", filterBy).Msg("filtering")
-	if err := validateFilter(model, filterBy); err != nil { // Validates Filters
-		return "", "", err
+	groups, err := parseFilter(filterBy)
+	if err != nil {
+		return "", nil, err
 	}
 
-	filterBody, filterValue := getFilterBodyAndValue(filterBy) // After validating, constructs Body and Value to be used in GetAll
-	return filterBody, filterValue, nil
+	return compileFilter(model, groups)
 }
 
-// validateFilter validates if filter parameters are correct by length, emptiness and type
-func validateFilter(model interface{}, filterBy string) error {
+// parseFilter splits filterBy into OR groups of AND clauses, respecting quotes and value lists
+func parseFilter(filterBy string) ([][]filterClause, error) {
 	log := logging.FromCtx(context.Background())
 
-	var field, operator, value string
+	var groups [][]filterClause
+	count := 0
+	for _, rawGroup := range splitTopLevel(filterBy, orSeparator) {
+		var group []filterClause
+		for _, rawClause := range splitTopLevel(rawGroup, andSeparator) {
+			if count++; count > maxClauses {
+				log.Error().Str("filter_by", filterBy).Int("max_clauses", maxClauses).Msg("too many filter clauses")
+				return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, too many clauses")
+			}
 
-	splits := strings.Split(filterBy, ".")
-	switch size := len(splits); {
-	case size == 2:
-		field, value = splits[0], splits[1]
+			clause, err := parseClause(rawClause)
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, clause)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
 
-	case size == 3:
-		field, operator, value = splits[0], splits[1], splits[2]
+// parseClause parses field.value or field.operator.value into a filterClause
+func parseClause(rawClause string) (filterClause, error) {
+	log := logging.FromCtx(context.Background())
+
+	field, rest, found := strings.Cut(rawClause, ".")
+	if !found {
+		log.Error().Str("clause", rawClause).Msg("malformed query parameter, should be field.value or field.operator.value")
+		return filterClause{}, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, should be field.value or field.operator.value")
+	}
+	if field == "" || rest == "" {
+		log.Error().Str("clause", rawClause).Msg("filter malformed, empty parameters")
+		return filterClause{}, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, can't be empty")
+	}
+
+	// field.operator.value -> Values with dots must be quoted, so the operator is always the second segment
+	if operator, value, found := strings.Cut(rest, "."); found && rest[0] != quoteChar {
 		if err := validateOperator(operator); err != nil {
-			return err
+			return filterClause{}, err
+		}
+
+		values, err := parseValues(operator, value)
+		if err != nil {
+			return filterClause{}, err
 		}
+		return filterClause{field: field, operator: operator, values: values}, nil
+	}
 
+	// field.value -> String partial find
+	value, err := unquote(rest)
+	if err != nil {
+		return filterClause{}, err
+	}
+	if value == "" {
+		log.Error().Str("clause", rawClause).Msg("filter malformed, empty parameters")
+		return filterClause{}, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, can't be empty")
+	}
+	return filterClause{field: field, values: []string{value}}, nil
+}
+
+// parseValues extracts the values of a clause. in & between take a (a,b,..) list, all others a single value
+func parseValues(operator, value string) ([]string, error) {
+	log := logging.FromCtx(context.Background())
+
+	var rawValues []string
+	switch operator {
+	case "in", "between":
+		if len(value) < 2 || value[0] != listOpenChar || value[len(value)-1] != listCloseChar {
+			log.Error().Str("operator", operator).Str("value", value).Msg("filter malformed, expected a list of values")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, "+operator+" expects a list like (a,b)")
+		}
+		rawValues = splitTopLevel(value[1:len(value)-1], andSeparator)
 	default:
-		log.Error().Str("filter_by", filterBy).Msg("malformed query parameter, should be field.value or field.operator.value")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, should be field.value or field.operator.value")
+		rawValues = []string{value}
+	}
+
+	values := make([]string, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		value, err := unquote(rawValue)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			log.Error().Str("operator", operator).Msg("filter malformed, empty parameters")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, can't be empty")
+		}
+		values = append(values, value)
+	}
+
+	if operator == "between" && len(values) != 2 {
+		log.Error().Int("values", len(values)).Msg("filter malformed, between expects two values")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, between expects exactly two values")
+	}
+	return values, nil
+}
+
+// compileFilter validates every clause against the model and builds the parameterized query
+func compileFilter(model interface{}, groups [][]filterClause) (string, []interface{}, error) {
+	var values []interface{}
+	compiledGroups := make([]string, 0, len(groups))
+	for _, group := range groups {
+		compiledClauses := make([]string, 0, len(group))
+		for _, clause := range group {
+			body, clauseValues, err := compileClause(model, clause)
+			if err != nil {
+				return "", nil, err
+			}
+			compiledClauses = append(compiledClauses, body)
+			values = append(values, clauseValues...)
+		}
+		compiledGroups = append(compiledGroups, strings.Join(compiledClauses, " AND "))
+	}
+
+	if len(compiledGroups) == 1 {
+		return compiledGroups[0], values, nil
+	}
+	return "(" + strings.Join(compiledGroups, ") OR (") + ")", values, nil
+}
+
+// compileClause validates a clause against the model and converts it into its query body and typed values
+func compileClause(model interface{}, clause filterClause) (string, []interface{}, error) {
+	log := logging.FromCtx(context.Background())
+
+	column, kind, err := getFilterableField(model, clause.field)
+	if err != nil {
+		return "", nil, err
 	}
 
-	if field == "" || value == "" {
-		log.Error().Msg("filter malformed, empty parameters")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, can't be empty")
+	if clause.operator == "" { // Partial find is only possible on strings -> E.g price.10
+		if kind != reflect.String {
+			log.Error().Str("field_name", clause.field).Msg("filter malformed, expected string")
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Filter Malformed, field not a string")
+		}
+		if !isFilterableString(clause.values[0]) {
+			log.Error().Str("field_name", clause.field).Msg("filter malformed, value has forbidden characters")
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Incorrect field type")
+		}
+		return column + " LIKE ?", []interface{}{"%" + clause.values[0] + "%"}, nil
+	}
+
+	if kind == reflect.String && isOrderOperator(clause.operator) { // Strings can't be ordered -> E.g name.gt.asd
+		log.Error().Str("field_name", clause.field).Str("operator", clause.operator).Msg("filter malformed, expected non string")
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Filter Malformed, field can't be a string")
+	}
+
+	if clause.operator == "isnull" {
+		isNull, err := strconv.ParseBool(clause.values[0])
+		if err != nil {
+			log.Error().Str("value", clause.values[0]).Msg("filter malformed, isnull expects a boolean")
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Incorrect field type")
+		}
+		if isNull {
+			return column + " IS NULL", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
 	}
 
-	// Validate Field & Value
-	return validateFieldAndValue(model, field, value, operator)
+	values := make([]interface{}, 0, len(clause.values))
+	for _, value := range clause.values {
+		converted, err := convertValue(kind, value)
+		if err != nil {
+			return "", nil, err
+		}
+		values = append(values, converted)
+	}
+
+	switch clause.operator {
+	case "in":
+		return column + " IN ?", []interface{}{values}, nil
+	case "between":
+		return column + " BETWEEN ? AND ?", values, nil
+	default:
+		return column + " " + operatorToString(clause.operator) + " ?", values, nil
+	}
 }
 
-// validateFieldAndValue checks if the field exists in the struct and if the value is of the correct type
-func validateFieldAndValue(model interface{}, fieldName, value, operator string) error {
+// getFilterableField checks if the field exists in the struct and returns its column name and kind
+func getFilterableField(model interface{}, fieldName string) (string, reflect.Kind, error) {
 	log := logging.FromCtx(context.Background())
 
+	naming := schema.NamingStrategy{}
 	fields := reflect.VisibleFields(reflect.TypeOf(model)) // Get all fields of Struct
 	for _, field := range fields {
-		if strings.ToLower(field.Name) == fieldName { // If there is a Field with this name
-			if operator == "" && field.Type.String() != "string" { // If there are only 2 field params and its not a string -> error -> E.g price.10
-				log.Error().Str("field_name", fieldName).Msg("filter malformed, expected string")
-				return middleware.NewError(http.StatusUnprocessableEntity, "Filter Malformed, field not a string")
-			}
-			if operator != "" && field.Type.String() == "string" { // If there are 3 field params and its a string -> error -> E.g name.gt.asd
-				log.Error().Str("field_name", fieldName).Msg("filter malformed, expected non string")
-				return middleware.NewError(http.StatusUnprocessableEntity, "Filter Malformed, field can't be a string")
-			}
-			return isValidType(field.Type.String(), value) // Field exists and is of the correct type
+		column := naming.ColumnName("", field.Name)
+		if strings.ToLower(field.Name) != fieldName && column != fieldName { // Accept both playernumber and player_number
+			continue
+		}
+
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		switch kind := typ.Kind(); kind {
+		case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			return column, kind, nil
 		}
+
+		log.Error().Str("field_name", fieldName).Str("type", typ.String()).Msg("field is not filterable")
+		return "", reflect.Invalid, middleware.NewError(http.StatusUnprocessableEntity, "Field not filterable")
 	}
 	log.Error().Str("field_name", fieldName).Interface("model", model).Msg("no filterable field in struct")
-	return middleware.NewError(http.StatusUnprocessableEntity, "No filterable field with the provided name")
+	return "", reflect.Invalid, middleware.NewError(http.StatusUnprocessableEntity, "No filterable field with the provided name")
 }
 
-// isValidType receives a value and validates if it reflects the provided type.
-func isValidType(typ, value string) error {
-	switch typ {
-	case "string":
-		if isAlphanumeric(value) { // If String is alphanumeric
-			return nil
+// convertValue receives a value and converts it to the provided kind, failing if it does not reflect it
+func convertValue(kind reflect.Kind, value string) (interface{}, error) {
+	switch kind {
+	case reflect.String:
+		if isFilterableString(value) {
+			return value, nil
 		}
-	case "int":
-		if _, err := strconv.Atoi(value); err == nil { // If convertion to Integer was valid
-			return nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if converted, err := strconv.Atoi(value); err == nil { // If convertion to Integer was valid
+			return converted, nil
 		}
-	case "float64":
-		if _, err := strconv.ParseFloat(value, 64); err == nil { // If convertion to Float64 was valid
-			return nil
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if converted, err := strconv.ParseUint(value, 10, 64); err == nil { // If convertion to Unsigned Integer was valid
+			return converted, nil
 		}
-	case "float32":
-		if _, err := strconv.ParseFloat(value, 32); err == nil { // If convertion to Float32 was valid
-			return nil
+	case reflect.Float32, reflect.Float64:
+		if converted, err := strconv.ParseFloat(value, 64); err == nil { // If convertion to Float was valid
+			return converted, nil
 		}
 	}
-	logging.FromCtx(context.Background()).Error().Msg("field convertion faile due to mistype")
-	return middleware.NewError(http.StatusUnprocessableEntity, "Incorrect field type")
+	logging.FromCtx(context.Background()).Error().Str("value", value).Str("kind", kind.String()).Msg("field convertion failed due to mistype")
+	return nil, middleware.NewError(http.StatusUnprocessableEntity, "Incorrect field type")
 }
 
 // validateOperator validates the operator in the URL parameter
 func validateOperator(operator string) error {
-	var allowedOperators = []string{"lt", "le", "gt", "ge", "eq"}
+	var allowedOperators = []string{"lt", "le", "gt", "ge", "eq", "ne", "in", "between", "isnull"}
 	if !stringInSlice(operator, allowedOperators) {
 		logging.FromCtx(context.Background()).Error().Str("operator", operator).Msg("unknown operator")
 		return middleware.NewError(http.StatusUnprocessableEntity, "Operator not allowed")
@@ -117,25 +296,12 @@ func validateOperator(operator string) error {
 	return nil
 }
 
-// getFilterBodyAndValue gets FilterBody and Value for GetFilters
-func getFilterBodyAndValue(filterBy string) (string, string) {
-	splits := strings.Split(filterBy, ".")
-
-	var field, operator, value string
-	field = splits[0]
-
-	// Must be String partial find -> all others have 3 parameters
-	if len(splits) == 2 {
-		value = splits[1]
-		return field + " LIKE ?", "%" + value + "%"
-	} else {
-		operator = splits[1]
-		value = splits[2]
-		return field + " " + operatorToString(operator) + " ?", value
-	}
+// isOrderOperator checks if the operator compares by order, which is only allowed on numbers
+func isOrderOperator(operator string) bool {
+	return stringInSlice(operator, []string{"lt", "le", "gt", "ge", "between"})
 }
 
-// operatorToString converts operator language to string literal (eq -> ==)
+// operatorToString converts operator language to string literal (eq -> =)
 func operatorToString(operator string) string {
 	switch operator {
 	case "lt":
@@ -147,8 +313,71 @@ func operatorToString(operator string) string {
 	case "ge":
 		return ">="
 	case "eq":
-		return "=="
+		return "="
+	case "ne":
+		return "<>"
 	default:
 		return ""
 	}
 }
+
+// splitTopLevel splits value by separator, ignoring separators inside quotes or value lists
+func splitTopLevel(value string, separator rune) []string {
+	var splits []string
+	var current strings.Builder
+	inQuotes, escaped, depth := false, false, 0
+	for _, char := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && char == escapeChar:
+			escaped = true
+		case char == quoteChar:
+			inQuotes = !inQuotes
+		case !inQuotes && char == listOpenChar:
+			depth++
+		case !inQuotes && char == listCloseChar && depth > 0:
+			depth--
+		case !inQuotes && depth == 0 && char == separator:
+			splits = append(splits, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(char)
+	}
+	return append(splits, current.String())
+}
+
+// unquote removes the surrounding quotes of a value and its escapes. Unquoted values can't contain dots
+func unquote(value string) (string, error) {
+	log := logging.FromCtx(context.Background())
+
+	if len(value) == 0 || value[0] != quoteChar {
+		if strings.ContainsAny(value, `."()`) {
+			log.Error().Str("value", value).Msg("filter malformed, unquoted value with reserved characters")
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, values with dots or parentheses must be quoted")
+		}
+		return value, nil
+	}
+
+	if len(value) < 2 || value[len(value)-1] != quoteChar {
+		log.Error().Str("value", value).Msg("filter malformed, unterminated quote")
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, unterminated quote")
+	}
+
+	var unquoted strings.Builder
+	escaped := false
+	for _, char := range value[1 : len(value)-1] {
+		if !escaped && char == escapeChar {
+			escaped = true
+			continue
+		}
+		if !escaped && char == quoteChar {
+			log.Error().Str("value", value).Msg("filter malformed, unescaped quote")
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed filterBy query parameter, quotes inside values must be escaped")
+		}
+		escaped = false
+		unquoted.WriteRune(char)
+	}
+	return unquoted.String(), nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/FranciscoBarao/catalog/middleware"
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+	"github.com/FranciscoBarao/catalog/model"
+)
+
+const (
+	defaultLimit = 20  // Page size used when no limit is given
+	maxLimit     = 100 // Biggest page size that can be requested
+)
+
+// GetPage validates the limit, offset and cursor query parameters into the page to be read
+func GetPage(limit, offset, cursor string) (*model.Page, error) {
+	log := logging.FromCtx(context.Background())
+
+	size := defaultLimit
+	if limit != "" {
+		var err error
+		if size, err = strconv.Atoi(limit); err != nil || size < 1 || size > maxLimit {
+			log.Error().Str("limit", limit).Msg("page limit malformed")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed limit query parameter, should be between 1 and "+strconv.Itoa(maxLimit))
+		}
+	}
+
+	skip := 0
+	if offset != "" {
+		var err error
+		if skip, err = strconv.Atoi(offset); err != nil || skip < 0 {
+			log.Error().Str("offset", offset).Msg("page offset malformed")
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed offset query parameter, should be a positive number")
+		}
+	}
+
+	if skip > 0 && cursor != "" { // A cursor already points at where the page starts
+		log.Error().Str("offset", offset).Str("cursor", cursor).Msg("page offset and cursor used together")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed page query parameters, use either offset or cursor")
+	}
+
+	log.Debug().Int("limit", size).Int("offset", skip).Str("cursor", cursor).Msg("paginating")
+	return model.NewPage(size, skip, cursor), nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/FranciscoBarao/catalog/middleware"
+	"github.com/FranciscoBarao/catalog/middleware/logging"
+)
+
+const (
+	maxSearchLength = 100 // Maximum number of characters of a search
+	maxSearchTerms  = 10  // Maximum number of words of a search
+
+	minSuggestLength    = 2  // Minimum number of characters typed before suggesting
+	defaultSuggestLimit = 10 // Suggestions returned when no limit is given
+	maxSuggestLimit     = 25 // Most suggestions that can be requested
+)
+
+var searchTerm = regexp.MustCompile(`[\p{L}\p{N}]+`) // Words only, so no tsquery operator reaches the database
+
+// Examples of searches that work:
+// catan					---> catan:*
+// Catan: Seafarers			---> catan:* & seafarers:*
+// GetSearchQuery validates the search and compiles its words into a prefix matching tsquery
+func GetSearchQuery(search string) (string, error) {
+	log := logging.FromCtx(context.Background())
+
+	if utf8.RuneCountInString(search) > maxSearchLength {
+		log.Error().Str("search", search).Int("max_length", maxSearchLength).Msg("search too long")
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, too long")
+	}
+
+	terms := searchTerm.FindAllString(strings.ToLower(search), -1)
+	if len(terms) == 0 {
+		log.Error().Str("search", search).Msg("search without words")
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, should contain words")
+	}
+	if len(terms) > maxSearchTerms {
+		log.Error().Str("search", search).Int("max_terms", maxSearchTerms).Msg("search with too many words")
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, too many words")
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*" // Prefix matching
+	}
+
+	log.Debug().Str("search", search).Strs("terms", terms).Msg("searching")
+	return strings.Join(terms, " & "), nil
+}
+
+// GetSuggestQuery validates the typed name and the number of suggestions wanted
+func GetSuggestQuery(name, limit string) (string, int, error) {
+	log := logging.FromCtx(context.Background())
+
+	name = strings.Join(strings.Fields(name), " ") // Collapse whitespace
+	if length := utf8.RuneCountInString(name); length < minSuggestLength || length > maxSearchLength {
+		log.Error().Str("name", name).Msg("suggestion name malformed")
+		return "", 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, should have between "+strconv.Itoa(minSuggestLength)+" and "+strconv.Itoa(maxSearchLength)+" characters")
+	}
+
+	size := defaultSuggestLimit
+	if limit != "" {
+		var err error
+		if size, err = strconv.Atoi(limit); err != nil || size < 1 || size > maxSuggestLimit {
+			log.Error().Str("limit", limit).Msg("suggestion limit malformed")
+			return "", 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed limit query parameter, should be between 1 and "+strconv.Itoa(maxSuggestLimit))
+		}
+	}
+
+	log.Debug().Str("name", name).Int("limit", size).Msg("suggesting")
+	return name, size, nil
+}
@@ -5,12 +5,25 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
+
+	"gorm.io/gorm/schema"
 
 	"github.com/FranciscoBarao/catalog/middleware"
 	"github.com/FranciscoBarao/catalog/middleware/logging"
 )
 
-// GetSort constructs the whole sort
+const (
+	sortSeparator = ","    // Sorts separated by a comma are applied in order
+	maxSorts      = 5      // Maximum number of fields accepted in one sortBy
+	rankSort      = "rank" // Sorts by the rating-service ranking instead of a field
+)
+
+// Examples of sorts that work:
+// name.asc							---> name asc, id asc
+// playernumber.desc				---> player_number desc, id asc
+// publisher.asc,created_at.desc	---> publisher asc, created_at desc, id asc
+// GetSort constructs the whole sort, ending on the primary key so that entries with equal values keep a deterministic order
 func GetSort(model interface{}, sortBy string) (string, error) {
 	log := logging.FromCtx(context.Background())
 	if sortBy == "" {
@@ -18,61 +31,109 @@ func GetSort(model interface{}, sortBy string) (string, error) {
 	}
 
 	log.Debug().Str("sort_by", sortBy).Msg("sorting")
-	if err := validateSort(model, sortBy); err != nil {
+	sorts := strings.Split(sortBy, sortSeparator)
+	if len(sorts) > maxSorts {
+		log.Error().Str("sort_by", sortBy).Int("max_sorts", maxSorts).Msg("too many sort fields")
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, too many fields")
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for _, sort := range sorts {
+		column, order, err := validateSort(model, sort)
+		if err != nil {
+			return "", err
+		}
+
+		if seen[column] { // Validate if the field was already used
+			log.Error().Str("sort_by", sortBy).Str("column", column).Msg("sort malformed with repeated field")
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, fields can't be repeated")
+		}
+		seen[column] = true
+		columns = append(columns, column+" "+order)
+	}
+
+	primaryKey, err := getPrimaryKey(model)
+	if err != nil {
 		return "", err
 	}
+	if primaryKey != "" && !seen[primaryKey] { // Tie-breaker
+		columns = append(columns, primaryKey+" asc")
+	}
+
+	return strings.Join(columns, ", "), nil
+}
 
-	return constructSort(sortBy), nil
+// IsRankSort checks if the sort asks for the best ranked entries first, which no field holds
+func IsRankSort(sortBy string) bool {
+	return sortBy == rankSort || sortBy == rankSort+".desc"
 }
 
-// validateSort checks if the sort parameters are valid for use by length, emptiness, order and field existence
-func validateSort(model interface{}, sortBy string) error {
+// validateSort checks if one sort is valid for use by length, emptiness, order and field existence, returning its column and order
+func validateSort(model interface{}, sortBy string) (string, string, error) {
 	splits := strings.Split(sortBy, ".")
 
 	if len(splits) != 2 { // Validate if there are only 2 parameters
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, should be field.order")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, should be field.order")
 	}
 
 	field, order := splits[0], splits[1]
 	if field == "" || order == "" { // Validate if there are no empty parameters
 		logging.FromCtx(context.Background()).Error().Msg("sort malformed with empty parameters")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, can't be empty")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, can't be empty")
 	}
 
 	if order != "desc" && order != "asc" { // Validate if order is valid
 		logging.FromCtx(context.Background()).Error().Str("order", order).Msg("sort malformed with incorrect parameters")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, order should be asc or desc")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, order should be asc or desc")
 	}
 
-	return validateField(model, field) // Validate if field exists
+	column, err := validateField(model, field) // Validate if field exists
+	return column, order, err
 }
 
-// validateField checks if a field exists in the struct
-func validateField(model interface{}, fieldName string) error {
+// validateField checks if a field, including the ones of embedded structs like gorm.Model, exists in the struct and returns its column
+func validateField(model interface{}, fieldName string) (string, error) {
+	naming := schema.NamingStrategy{}
 	fields := reflect.VisibleFields(reflect.TypeOf(model)) // Get all fields of Struct
 	for _, field := range fields {
-		if strings.ToLower(field.Name) == fieldName { // If there is a Field with this name
-			return isTypeSortable(field.Type.String()) // Checks if field is sortable
+		column := naming.ColumnName("", field.Name)
+		if strings.ToLower(field.Name) == fieldName || column == fieldName { // Accept both createdat and created_at
+			return column, isTypeSortable(field.Type) // Checks if field is sortable
 		}
 	}
 	logging.FromCtx(context.Background()).Error().Interface("model", model).Str("field_name", fieldName).Msg("unknown field in struct")
-	return middleware.NewError(http.StatusUnprocessableEntity, "No field with this name")
+	return "", middleware.NewError(http.StatusUnprocessableEntity, "No field with this name")
 }
 
 // isTypeSortable verifies if the field is sortable (E.g We cant sort by Tags)
-func isTypeSortable(typ string) error {
-	switch typ {
-	case "string", "int", "float64", "float32":
+func isTypeSortable(typ reflect.Type) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
 		return nil
-	default:
-		logging.FromCtx(context.Background()).Error().Str("type", typ).Msg("field is not sortable")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
 	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	logging.FromCtx(context.Background()).Error().Str("type", typ.String()).Msg("field is not sortable")
+	return middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
 }
 
-// constructSort constructs the sort query
-func constructSort(sortBy string) string {
-	splits := strings.Split(sortBy, ".")
-	field, order := splits[0], splits[1]
-	return field + " " + order
+// getPrimaryKey returns the column of the model's primary key, if it has one
+func getPrimaryKey(model interface{}) (string, error) {
+	modelSchema, err := schema.Parse(model, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		logging.FromCtx(context.Background()).Error().Err(err).Interface("model", model).Msg("failed to parse model schema")
+		return "", err
+	}
+
+	if modelSchema.PrioritizedPrimaryField == nil {
+		return "", nil
+	}
+	return modelSchema.PrioritizedPrimaryField.DBName, nil
 }
@@ -29,7 +29,10 @@ func GetFieldFromURL(r *http.Request, field string) string {
 
 // GetUsernameFromToken extracts the username from context
 func GetUsernameFromToken(r *http.Request) (string, error) {
-	claims := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+	claims, ok := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+	if !ok {
+		return "", middleware.NewError(http.StatusUnauthorized, "Error - Token claims not present")
+	}
 	username, ok := claims["username"]
 	if !ok {
 		return "", middleware.NewError(http.StatusInternalServerError, "Error - Username not present")
@@ -37,6 +40,15 @@ func GetUsernameFromToken(r *http.Request) (string, error) {
 	return username, nil
 }
 
+// GetAccessToken extracts the bearer token the request was authorized with from context
+func GetAccessToken(r *http.Request) (string, error) {
+	token, ok := r.Context().Value(oauth.AccessTokenContext).(string)
+	if !ok || token == "" {
+		return "", middleware.NewError(http.StatusUnauthorized, "Error - Access token not present")
+	}
+	return token, nil
+}
+
 // stringInSlice checks if a specific string exists in a slice of strings
 func stringInSlice(value string, list []string) bool {
 	for _, element := range list {
@@ -47,7 +59,7 @@ func stringInSlice(value string, list []string) bool {
 	return false
 }
 
-// isAlphanumeric checks if a string is alphanumeric
-func isAlphanumeric(word string) bool {
-	return regexp.MustCompile(`^[a-zA-Z0-9]*$`).MatchString(word)
+// isFilterableString checks if a string only has letters, numbers, spaces and punctuation that is not a LIKE wildcard
+func isFilterableString(word string) bool {
+	return regexp.MustCompile(`^[\p{L}\p{N} .,:!?'&()+-]*$`).MatchString(word)
 }
@@ -0,0 +1,107 @@
+package clients
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+const boardgamePath = "/api/boardgame/"
+
+// cachedBoardgame is a boardgame the catalog found, kept until it expires -> Missing ones aren't cached, as they may be added any time
+type cachedBoardgame struct {
+	boardgame model.CatalogBoardgame
+	expiresAt time.Time
+}
+
+// CatalogClient reads the boardgames of the catalog service, caching them as they barely change
+type CatalogClient struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[uint]cachedBoardgame
+}
+
+// NewCatalogClient creates a client for the catalog at baseURL, giving up on each request after timeout and keeping lookups for ttl
+func NewCatalogClient(baseURL string, timeout, ttl time.Duration) *CatalogClient {
+	return &CatalogClient{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: timeout},
+		ttl:    ttl,
+		cache:  make(map[uint]cachedBoardgame),
+	}
+}
+
+// GetBoardgame fetches the boardgame or expansion with the id -> Ids unknown to the catalog fail with a 422
+func (cc *CatalogClient) GetBoardgame(id uint) (model.CatalogBoardgame, error) {
+
+	if cached, ok := cc.cached(id); ok {
+		return cached.boardgame, nil
+	}
+
+	res, err := cc.client.Get(cc.url + boardgamePath + strconv.FormatUint(uint64(id), 10))
+	if err != nil {
+		log.Println("Error - Failed to reach the catalog: " + err.Error())
+		return model.CatalogBoardgame{}, middleware.NewError(http.StatusServiceUnavailable, "Catalog unavailable")
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return model.CatalogBoardgame{}, notFound(id)
+	case res.StatusCode != http.StatusOK:
+		log.Println("Error - Catalog answered with status " + strconv.Itoa(res.StatusCode))
+		return model.CatalogBoardgame{}, middleware.NewError(http.StatusBadGateway, "Catalog failed")
+	}
+
+	var boardgame model.CatalogBoardgame
+	if err := json.NewDecoder(res.Body).Decode(&boardgame); err != nil {
+		log.Println("Error - Failed to decode catalog response: " + err.Error())
+		return model.CatalogBoardgame{}, middleware.NewError(http.StatusBadGateway, "Catalog answered with an invalid boardgame")
+	}
+
+	cc.store(id, cachedBoardgame{boardgame: boardgame})
+	return boardgame, nil
+}
+
+// cached returns the boardgame of the id while it hasn't expired
+func (cc *CatalogClient) cached(id uint) (cachedBoardgame, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cached, ok := cc.cache[id]
+	if !ok {
+		return cachedBoardgame{}, false
+	}
+	if time.Now().After(cached.expiresAt) {
+		delete(cc.cache, id)
+		return cachedBoardgame{}, false
+	}
+	return cached, true
+}
+
+// store keeps the boardgame of the id for the ttl of the client
+func (cc *CatalogClient) store(id uint, cached cachedBoardgame) {
+	if cc.ttl <= 0 { // Caching disabled
+		return
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cached.expiresAt = time.Now().Add(cc.ttl)
+	cc.cache[id] = cached
+}
+
+func notFound(id uint) error {
+	return middleware.NewError(http.StatusUnprocessableEntity, "Boardgame not found in catalog with id: "+strconv.FormatUint(uint64(id), 10))
+}
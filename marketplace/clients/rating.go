@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+const (
+	summaryBatchPath = "/api/rating/summary/batch"
+	MaxSummaryBatch  = 100 // Most objects the rating service summarizes at once
+)
+
+// RatingClient reads the summaries of the ratings of the rating service
+type RatingClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewRatingClient creates a client for the rating service at baseURL, giving up on each request after timeout
+func NewRatingClient(baseURL string, timeout time.Duration) *RatingClient {
+	return &RatingClient{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// GetSummaries fetches the summaries of the objects of the namespace, in the order of the ids -> At most MaxSummaryBatch at once
+func (rc *RatingClient) GetSummaries(namespace string, ids []string) ([]model.RatingSummary, error) {
+
+	query := url.Values{}
+	query.Set("reference_namespace", namespace)
+	query.Set("reference_ids", strings.Join(ids, ","))
+
+	res, err := rc.client.Get(rc.url + summaryBatchPath + "?" + query.Encode())
+	if err != nil {
+		log.Println("Error - Failed to reach the rating service: " + err.Error())
+		return nil, middleware.NewError(http.StatusServiceUnavailable, "Rating service unavailable")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		log.Println("Error - Rating service answered with status " + strconv.Itoa(res.StatusCode))
+		return nil, middleware.NewError(http.StatusBadGateway, "Rating service failed")
+	}
+
+	var summaries []model.RatingSummary
+	if err := json.NewDecoder(res.Body).Decode(&summaries); err != nil {
+		log.Println("Error - Failed to decode rating service response: " + err.Error())
+		return nil, middleware.NewError(http.StatusBadGateway, "Rating service answered with invalid summaries")
+	}
+	return summaries, nil
+}
@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace/middleware"
+)
+
+const userPath = "/api/user/"
+
+// UserClient looks users up in the user management service
+type UserClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewUserClient creates a client for the user management service at baseURL, giving up on each request after timeout
+func NewUserClient(baseURL string, timeout time.Duration) *UserClient {
+	return &UserClient{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Exists tells whether the user is registered, asking on behalf of the token's owner -> Only admins can look users up
+func (uc *UserClient) Exists(username, token string) (bool, error) {
+
+	req, err := http.NewRequest(http.MethodGet, uc.url+userPath+url.PathEscape(username), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := uc.client.Do(req)
+	if err != nil {
+		log.Println("Error - Failed to reach the user management service: " + err.Error())
+		return false, middleware.NewError(http.StatusServiceUnavailable, "User management service unavailable")
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	log.Println("Error - User management service answered with status " + strconv.Itoa(res.StatusCode))
+	return false, middleware.NewError(http.StatusBadGateway, "User management service failed")
+}
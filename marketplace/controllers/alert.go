@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type alertService interface {
+	GetAll(username string, after int64, page *model.Page) ([]model.Alert, error)
+	MarkRead(username string, lastId int64) error
+}
+
+// AlertController exposes the alerts inbox of the token's user
+type AlertController struct {
+	service alertService
+}
+
+func InitAlertController(alertService *services.AlertService) *AlertController {
+	return &AlertController{
+		service: alertService,
+	}
+}
+
+// Get Alerts godoc
+// @Summary 	Fetches the alerts of the user, the offers that matched their saved searches, oldest first -> Polled with the id of the last alert read
+// @Tags 		alert
+// @Produce 	json
+// @Param 		after query int  false  "Id of the last alert read, only later ones are fetched"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
+// @Router 		/alerts [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *AlertController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	after, err := utils.GetAfter(r.URL.Query().Get("after"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	alerts, err := controller.service.GetAll(user, after, page)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(alerts, page))
+}
+
+// Mark Alerts Read godoc
+// @Summary 	Marks the alerts of the user read, up to and including the given one
+// @Tags 		alert
+// @Param 		data body model.AlertReadInput true "The id of the last alert read"
+// @Success 	204
+// @Router 		/alerts/read [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *AlertController) MarkRead(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.AlertReadInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.MarkRead(user, input.LastId); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, input.LastId)
+}
@@ -6,12 +6,26 @@ import (
 
 // Controllers contains all the controllers
 type Controllers struct {
-	OfferController *OfferController
+	OfferController           *OfferController
+	PurchaseRequestController *PurchaseRequestController
+	ExchangeRateController    *ExchangeRateController
+	OfferImageController      *OfferImageController
+	MarketController          *MarketController
+	SavedSearchController     *SavedSearchController
+	AlertController           *AlertController
+	ModerationController      *ModerationController
 }
 
 // InitControllers returns a new Controllers
 func InitControllers(services *services.Services) *Controllers {
 	return &Controllers{
-		OfferController: InitOfferController(services.OfferService),
+		OfferController:           InitOfferController(services.OfferService),
+		PurchaseRequestController: InitPurchaseRequestController(services.PurchaseRequestService),
+		ExchangeRateController:    InitExchangeRateController(services.ExchangeRateService),
+		OfferImageController:      InitOfferImageController(services.OfferImageService),
+		MarketController:          InitMarketController(services.MarketService),
+		SavedSearchController:     InitSavedSearchController(services.SavedSearchService),
+		AlertController:           InitAlertController(services.AlertService),
+		ModerationController:      InitModerationController(services.ModerationService),
 	}
 }
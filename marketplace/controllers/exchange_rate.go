@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type exchangeRateService interface {
+	GetAll() ([]model.ExchangeRate, error)
+	Replace(input *model.ExchangeRatesInput) ([]model.ExchangeRate, error)
+}
+
+// ExchangeRateController exposes the exchange rates offers are converted with
+type ExchangeRateController struct {
+	service exchangeRateService
+}
+
+func InitExchangeRateController(exchangeRateService *services.ExchangeRateService) *ExchangeRateController {
+	return &ExchangeRateController{
+		service: exchangeRateService,
+	}
+}
+
+// Get Exchange Rates godoc
+// @Summary 	Fetches all exchange rates, relative to the base currency
+// @Tags 		exchange rate
+// @Produce 	json
+// @Success 	200 {array} model.ExchangeRate
+// @Router 		/exchange-rate [get]
+func (controller *ExchangeRateController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	rates, err := controller.service.GetAll()
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, rates)
+}
+
+// Replace Exchange Rates godoc
+// @Summary 	Replaces all exchange rates by ones relative to a base currency -> Admins only
+// @Tags 		exchange rate
+// @Produce 	json
+// @Param 		data body model.ExchangeRatesInput true "The base currency and the rates of the others"
+// @Success 	200 {array} model.ExchangeRate
+// @Router 		/exchange-rate [put]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ExchangeRateController) Replace(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.ExchangeRatesInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	rates, err := controller.service.Replace(&input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, rates)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type marketService interface {
+	GetStats(query *model.MarketQuery) (model.MarketStats, error)
+}
+
+// MarketController exposes the market value of the catalog boardgames
+type MarketController struct {
+	service marketService
+}
+
+func InitMarketController(marketService *services.MarketService) *MarketController {
+	return &MarketController{
+		service: marketService,
+	}
+}
+
+// Get Boardgame Market Stats godoc
+// @Summary 	Fetches the median asking and sold prices of a catalog boardgame, its active listings and its price trend
+// @Tags 		market
+// @Produce 	json
+// @Param 		id path int  true  "Catalog boardgame id"
+// @Param 		currency query string  false  "ISO-4217 currency amounts are converted into (default EUR)"
+// @Param 		period query string  false  "Only prices recorded within it, as days (E.g 30d) or a duration, at most 730d (default 90d)"
+// @Param 		bucket query string  false  "Buckets of the trend, day, week or month (default week)"
+// @Success 	200 {object} model.MarketStats
+// @Router 		/market/boardgame/{id}/stats [get]
+func (controller *MarketController) GetStats(w http.ResponseWriter, r *http.Request) {
+
+	query, err := utils.GetMarketQuery(utils.GetFieldFromURL(r, "id"), r.URL.Query())
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	stats, err := controller.service.GetStats(query)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type moderationService interface {
+	Report(offerUuid, reason, username string) (model.OfferReport, error)
+	GetReports(offerUuid string) ([]model.OfferReport, error)
+	GetQueue(minReports int, page *model.Page) ([]model.ModerationQueueItem, error)
+	GetActions(offerUuid string, page *model.Page) ([]model.ModerationAction, error)
+	Hide(offerUuid, reason, moderator string) (model.ModerationAction, error)
+	Restore(offerUuid, reason, moderator string) (model.ModerationAction, error)
+	Dismiss(offerUuid, reason, moderator string) (model.ModerationAction, error)
+	Transfer(offerUuid string, input *model.TransferInput, moderator, token string) (model.ModerationAction, error)
+	Delete(offerUuid, reason, moderator string) (model.ModerationAction, error)
+}
+
+// ModerationController exposes the reports of offers to the users and the moderation of offers to the moderators
+type ModerationController struct {
+	service moderationService
+}
+
+func InitModerationController(moderationService *services.ModerationService) *ModerationController {
+	return &ModerationController{
+		service: moderationService,
+	}
+}
+
+// Report Offer godoc
+// @Summary 	Reports an Offer of another user to the moderators, once while the report is open
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.ReportInput true "Why the Offer breaks the rules"
+// @Success 	200 {object} model.OfferReport
+// @Router 		/offer/{id}/report [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Report(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.ReportInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	report, err := controller.service.Report(utils.GetFieldFromURL(r, "id"), input.Reason, user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, report)
+}
+
+// Get Moderation Queue godoc
+// @Summary 	Fetches the offers with at least the given open reports, most reported first
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		min_reports query int  false  "Open reports an Offer needs at least (default 3)"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
+// @Router 		/moderation/queue [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) GetQueue(w http.ResponseWriter, r *http.Request) {
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	minReports, err := utils.GetMinReports(r.URL.Query().Get("min_reports"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	items, err := controller.service.GetQueue(minReports, page)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(items, page))
+}
+
+// Get Offer Reports godoc
+// @Summary 	Fetches the reports of an Offer, open ones first
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {array} model.OfferReport
+// @Router 		/moderation/offer/{id}/reports [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) GetReports(w http.ResponseWriter, r *http.Request) {
+
+	reports, err := controller.service.GetReports(utils.GetFieldFromURL(r, "id"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, reports)
+}
+
+// Get Moderation Audit godoc
+// @Summary 	Fetches the moderation actions taken, newest first
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		offer_uuid query string  false  "Only the actions taken on this Offer"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
+// @Router 		/moderation/audit [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) GetActions(w http.ResponseWriter, r *http.Request) {
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	offerUuid, err := utils.GetOfferUuid(r.URL.Query().Get("offer_uuid"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	actions, err := controller.service.GetActions(offerUuid, page)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(actions, page))
+}
+
+// Hide Offer godoc
+// @Summary 	Hides an Offer from every public read, resolving its open reports
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.ModerationInput true "The reason, kept in the audit"
+// @Success 	200 {object} model.ModerationAction
+// @Router 		/moderation/offer/{id}/hide [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Hide(w http.ResponseWriter, r *http.Request) {
+	controller.moderate(w, r, controller.service.Hide)
+}
+
+// Restore Offer godoc
+// @Summary 	Shows a hidden Offer again
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.ModerationInput true "The reason, kept in the audit"
+// @Success 	200 {object} model.ModerationAction
+// @Router 		/moderation/offer/{id}/restore [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Restore(w http.ResponseWriter, r *http.Request) {
+	controller.moderate(w, r, controller.service.Restore)
+}
+
+// Dismiss Offer Reports godoc
+// @Summary 	Resolves the open reports of an Offer, leaving it as it is
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.ModerationInput true "The reason, kept in the audit"
+// @Success 	200 {object} model.ModerationAction
+// @Router 		/moderation/offer/{id}/dismiss [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Dismiss(w http.ResponseWriter, r *http.Request) {
+	controller.moderate(w, r, controller.service.Dismiss)
+}
+
+// Force Delete Offer godoc
+// @Summary 	Deletes an Offer whoever owns it, along with its images and reports
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.ModerationInput true "The reason, kept in the audit"
+// @Success 	200 {object} model.ModerationAction
+// @Router 		/moderation/offer/{id}/delete [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Delete(w http.ResponseWriter, r *http.Request) {
+	controller.moderate(w, r, controller.service.Delete)
+}
+
+// Transfer Offer godoc
+// @Summary 	Gives an Offer to another user
+// @Tags 		moderation
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.TransferInput true "The new owner and the reason, kept in the audit"
+// @Success 	200 {object} model.ModerationAction
+// @Router 		/moderation/offer/{id}/transfer [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ModerationController) Transfer(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.TransferInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// The new owner is looked up in the user management service with the same token
+	token, err := utils.GetAccessToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	action, err := controller.service.Transfer(utils.GetFieldFromURL(r, "id"), &input, user, token)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, action)
+}
+
+// Method that takes the moderation action whose body is only the reason
+func (controller *ModerationController) moderate(w http.ResponseWriter, r *http.Request, action func(offerUuid, reason, moderator string) (model.ModerationAction, error)) {
+
+	// Deserialize input
+	var input model.ModerationInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	taken, err := action(utils.GetFieldFromURL(r, "id"), input.Reason, user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, taken)
+}
@@ -14,9 +14,11 @@ import (
 // Declaring the repository interface in the controller package allows us to easily swap out the actual implementation, enforcing loose coupling.
 type offerService interface {
 	Create(offer *model.Offer, user string) error
-	ReadAll() ([]model.Offer, error)
-	Get(uuid string) (model.Offer, error)
+	ReadAll(filter *model.OfferFilter, page *model.Page) ([]model.Offer, error)
+	Get(uuid string, viewer model.Viewer) (model.Offer, error)
 	Update(input *model.OfferUpdate, uuid, username string) (model.Offer, error)
+	Transition(uuid, username, state string) (model.Offer, error)
+	GetSale(uuid, username string) (model.Sale, error)
 	Delete(uuid, username string) error
 }
 
@@ -70,33 +72,60 @@ func (controller *OfferController) Create(w http.ResponseWriter, r *http.Request
 }
 
 // Get Offer godoc
-// @Summary 	Fetches all Offers
+// @Summary 	Fetches all Offers, only the active ones unless states are given
 // @Tags 		offer
 // @Produce 	json
-// @Success 	200 {object} model.Offer
+// @Param 		state query string  false  "Comma separated states to list, or all (default active) -> Only active, reserved and sold unless listing your own offers by username"
+// @Param 		currency query string  false  "ISO-4217 currency amounts are converted into and compared in"
+// @Param 		min_amount query int  false  "Lowest amount, in minor units"
+// @Param 		max_amount query int  false  "Highest amount, in minor units"
+// @Param 		type query string  false  "Boardgame or Expansion"
+// @Param 		username query string  false  "Seller of the offers"
+// @Param 		min_seller_rating query number  false  "Lowest average rating of the seller, from 0 to 10"
+// @Param 		catalog_ref query int  false  "Catalog boardgame of the offers"
+// @Param 		max_age query string  false  "Only offers added within it, as days (E.g 7d) or a duration (E.g 12h)"
+// @Param 		q query string  false  "Words the name must contain"
+// @Param 		sortBy query string  false  "Sorts by amount or added_at (E.g amount.asc,added_at.desc), newest first by default"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
 // @Router 		/offer [get]
 func (controller *OfferController) GetAll(w http.ResponseWriter, r *http.Request) {
 
-	offers, err := controller.service.ReadAll()
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	filter, err := utils.GetOfferFilter(r.URL.Query(), middleware.GetViewer(r).Username)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	offers, err := controller.service.ReadAll(filter, page)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	render.New().JSON(w, http.StatusOK, offers)
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(offers, page))
 }
 
 // Get Offer godoc
-// @Summary 	Fetches a Offer
+// @Summary 	Fetches a Offer -> Drafts, withdrawn, expired and hidden offers only for their seller and moderators
 // @Tags 		offer
 // @Produce 	json
 // @Success 	200 {object} model.Offer
 // @Router 		/offer/{id} [get]
+// @Param 		Authorization header string false "Insert your access token to see your own offers in any state" default(Bearer <Add access token here>)
 func (controller *OfferController) Get(w http.ResponseWriter, r *http.Request) {
 
 	uuid := utils.GetFieldFromURL(r, "id")
 
-	offer, err := controller.service.Get(uuid)
+	offer, err := controller.service.Get(uuid, middleware.GetViewer(r))
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
@@ -148,6 +177,113 @@ func (controller *OfferController) Update(w http.ResponseWriter, r *http.Request
 	render.New().JSON(w, http.StatusOK, offer)
 }
 
+// Publish Offer by uuid godoc
+// @Summary 	Lists a draft or expired Offer, making it active
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Offer
+// @Router 		/offer/{id}/publish [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) Publish(w http.ResponseWriter, r *http.Request) {
+	controller.transition(w, r, model.OfferActive)
+}
+
+// Reserve Offer by uuid godoc
+// @Summary 	Reserves an active Offer for a buyer
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Offer
+// @Router 		/offer/{id}/reserve [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) Reserve(w http.ResponseWriter, r *http.Request) {
+	controller.transition(w, r, model.OfferReserved)
+}
+
+// Release Offer by uuid godoc
+// @Summary 	Releases a reserved Offer, making it active again
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Offer
+// @Router 		/offer/{id}/release [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) Release(w http.ResponseWriter, r *http.Request) {
+	controller.transition(w, r, model.OfferActive)
+}
+
+// Sell Offer by uuid godoc
+// @Summary 	Marks an active or reserved Offer as sold
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Offer
+// @Router 		/offer/{id}/sell [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) Sell(w http.ResponseWriter, r *http.Request) {
+	controller.transition(w, r, model.OfferSold)
+}
+
+// Withdraw Offer by uuid godoc
+// @Summary 	Withdraws an Offer that isn't sold
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Offer
+// @Router 		/offer/{id}/withdraw [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) Withdraw(w http.ResponseWriter, r *http.Request) {
+	controller.transition(w, r, model.OfferWithdrawn)
+}
+
+// Get Offer Sale godoc
+// @Summary 	Fetches the proof a sold Offer was sold, and to whom -> Only its seller and buyer can see it
+// @Tags 		offer
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {object} model.Sale
+// @Router 		/offer/{id}/sale [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferController) GetSale(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	sale, err := controller.service.GetSale(utils.GetFieldFromURL(r, "id"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, sale)
+}
+
+// transition moves the Offer of the token's user into the state
+func (controller *OfferController) transition(w http.ResponseWriter, r *http.Request, state string) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	uuid := utils.GetFieldFromURL(r, "id")
+
+	offer, err := controller.service.Transition(uuid, user, state)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, offer)
+}
+
 // Delete Offer by uuid godoc
 // @Summary 	Deletes a specific Offer via Uuid
 // @Tags 		offer
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+const maxImageUpload = 10 << 20 // Biggest image file accepted, 10MB
+
+type offerImageService interface {
+	Upload(content io.ReadSeeker, offerUuid, username string) (model.OfferImage, error)
+	GetAll(offerUuid string, viewer model.Viewer) ([]model.OfferImage, error)
+	Open(offerUuid, imageUuid string, thumbnail bool, viewer model.Viewer) (io.ReadCloser, error)
+	Delete(offerUuid, imageUuid, username string) error
+}
+
+// OfferImageController handles the photos of offers
+type OfferImageController struct {
+	service offerImageService
+}
+
+func InitOfferImageController(offerImageService *services.OfferImageService) *OfferImageController {
+	return &OfferImageController{
+		service: offerImageService,
+	}
+}
+
+// Upload Offer Image godoc
+// @Summary 	Adds a photo to an Offer, as the image field of a multipart form -> Jpeg, png or gif up to 10MB
+// @Tags 		offer image
+// @Accept 		mpfd
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		image formData file true "The photo"
+// @Success 	200 {object} model.OfferImage
+// @Router 		/offer/{id}/images [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferImageController) Upload(w http.ResponseWriter, r *http.Request) {
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUpload+1<<20) // Room for the rest of the form
+	if err := r.ParseMultipartForm(maxImageUpload); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			middleware.ErrorHandler(w, middleware.NewError(http.StatusRequestEntityTooLarge, "Image should be at most 10MB"))
+			return
+		}
+		log.Println("Error - Multipart form malformed: " + err.Error())
+		middleware.ErrorHandler(w, middleware.NewError(http.StatusBadRequest, "Request should be a multipart form"))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		middleware.ErrorHandler(w, middleware.NewError(http.StatusBadRequest, "Form should have an image file"))
+		return
+	}
+	defer file.Close()
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	image, err := controller.service.Upload(file, utils.GetFieldFromURL(r, "id"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, image)
+}
+
+// Get Offer Images godoc
+// @Summary 	Fetches the photos of an Offer, with the urls of their files
+// @Tags 		offer image
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {array} model.OfferImage
+// @Router 		/offer/{id}/images [get]
+func (controller *OfferImageController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	images, err := controller.service.GetAll(utils.GetFieldFromURL(r, "id"), middleware.GetViewer(r))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, images)
+}
+
+// Get Offer Image godoc
+// @Summary 	Serves the jpeg of a photo
+// @Tags 		offer image
+// @Produce 	jpeg
+// @Param 		id path string true "The Offer id"
+// @Param 		imageId path string true "The Image id"
+// @Success 	200 {file} binary
+// @Router 		/offer/{id}/images/{imageId} [get]
+func (controller *OfferImageController) Get(w http.ResponseWriter, r *http.Request) {
+	controller.serve(w, r, false)
+}
+
+// Get Offer Image Thumbnail godoc
+// @Summary 	Serves the jpeg of the thumbnail of a photo
+// @Tags 		offer image
+// @Produce 	jpeg
+// @Param 		id path string true "The Offer id"
+// @Param 		imageId path string true "The Image id"
+// @Success 	200 {file} binary
+// @Router 		/offer/{id}/images/{imageId}/thumbnail [get]
+func (controller *OfferImageController) GetThumbnail(w http.ResponseWriter, r *http.Request) {
+	controller.serve(w, r, true)
+}
+
+// Delete Offer Image godoc
+// @Summary 	Removes a photo from an Offer
+// @Tags 		offer image
+// @Param 		id path string true "The Offer id"
+// @Param 		imageId path string true "The Image id"
+// @Success 	204
+// @Router 		/offer/{id}/images/{imageId} [delete]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *OfferImageController) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	imageUuid := utils.GetFieldFromURL(r, "imageId")
+	if err := controller.service.Delete(utils.GetFieldFromURL(r, "id"), imageUuid, user); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, imageUuid)
+}
+
+// serve writes the stored jpeg -> Files never change under the same uuid, so they can be cached for long
+func (controller *OfferImageController) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+
+	file, err := controller.service.Open(utils.GetFieldFromURL(r, "id"), utils.GetFieldFromURL(r, "imageId"), thumbnail, middleware.GetViewer(r))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if _, err := io.Copy(w, file); err != nil {
+		log.Println("Error - Failed to serve image: " + err.Error())
+	}
+}
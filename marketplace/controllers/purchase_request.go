@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type purchaseRequestService interface {
+	Create(input *model.PurchaseRequestInput, offerUuid, buyer string) (model.PurchaseRequest, error)
+	GetAll(offerUuid, username string) ([]model.PurchaseRequest, error)
+	Counter(input *model.CounterInput, offerUuid, requestUuid, username string) (model.PurchaseRequest, error)
+	Accept(offerUuid, requestUuid, username string) (model.PurchaseRequest, error)
+	Reject(offerUuid, requestUuid, username string) (model.PurchaseRequest, error)
+}
+
+// PurchaseRequestController handles the negotiation of offers between buyers and sellers
+type PurchaseRequestController struct {
+	service purchaseRequestService
+}
+
+func InitPurchaseRequestController(purchaseRequestService *services.PurchaseRequestService) *PurchaseRequestController {
+	return &PurchaseRequestController{
+		service: purchaseRequestService,
+	}
+}
+
+// Create Purchase Request godoc
+// @Summary 	Sends a purchase request on an active Offer, at the offer amount when none is given
+// @Tags 		purchase request
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		data body model.PurchaseRequestInput true "The amount asked, in minor units of the offer currency"
+// @Success 	200 {object} model.PurchaseRequest
+// @Router 		/offer/{id}/requests [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *PurchaseRequestController) Create(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.PurchaseRequestInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	request, err := controller.service.Create(&input, utils.GetFieldFromURL(r, "id"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, request)
+}
+
+// Get Purchase Requests godoc
+// @Summary 	Fetches the purchase requests of an Offer with their negotiation history -> Sellers see all of them and buyers only theirs
+// @Tags 		purchase request
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Success 	200 {array} model.PurchaseRequest
+// @Router 		/offer/{id}/requests [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *PurchaseRequestController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	requests, err := controller.service.GetAll(utils.GetFieldFromURL(r, "id"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, requests)
+}
+
+// Counter Purchase Request godoc
+// @Summary 	Answers a purchase request with a new amount -> Sellers counter pending requests and buyers countered ones
+// @Tags 		purchase request
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		requestId path string true "The Purchase Request id"
+// @Param 		data body model.CounterInput true "The counter amount, in minor units of the offer currency"
+// @Success 	200 {object} model.PurchaseRequest
+// @Router 		/offer/{id}/requests/{requestId}/counter [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *PurchaseRequestController) Counter(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.CounterInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	request, err := controller.service.Counter(&input, utils.GetFieldFromURL(r, "id"), utils.GetFieldFromURL(r, "requestId"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, request)
+}
+
+// Accept Purchase Request godoc
+// @Summary 	Accepts a pending purchase request, reserving the Offer and declining its other requests
+// @Tags 		purchase request
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		requestId path string true "The Purchase Request id"
+// @Success 	200 {object} model.PurchaseRequest
+// @Router 		/offer/{id}/requests/{requestId}/accept [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *PurchaseRequestController) Accept(w http.ResponseWriter, r *http.Request) {
+	controller.answer(w, r, controller.service.Accept)
+}
+
+// Reject Purchase Request godoc
+// @Summary 	Rejects a purchase request still under negotiation
+// @Tags 		purchase request
+// @Produce 	json
+// @Param 		id path string true "The Offer id"
+// @Param 		requestId path string true "The Purchase Request id"
+// @Success 	200 {object} model.PurchaseRequest
+// @Router 		/offer/{id}/requests/{requestId}/reject [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *PurchaseRequestController) Reject(w http.ResponseWriter, r *http.Request) {
+	controller.answer(w, r, controller.service.Reject)
+}
+
+// answer runs the seller's answer on the request of the url
+func (controller *PurchaseRequestController) answer(w http.ResponseWriter, r *http.Request, action func(offerUuid, requestUuid, username string) (model.PurchaseRequest, error)) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	request, err := action(utils.GetFieldFromURL(r, "id"), utils.GetFieldFromURL(r, "requestId"), user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, request)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/services"
+	"marketplace/utils"
+
+	"github.com/unrolled/render"
+)
+
+type savedSearchService interface {
+	Create(input *model.SavedSearchInput, filter *model.OfferFilter, username string) (model.SavedSearch, error)
+	GetAll(username string) ([]model.SavedSearch, error)
+	Delete(uuid, username string) error
+}
+
+// SavedSearchController exposes the saved searches of the token's user
+type SavedSearchController struct {
+	service savedSearchService
+}
+
+func InitSavedSearchController(savedSearchService *services.SavedSearchService) *SavedSearchController {
+	return &SavedSearchController{
+		service: savedSearchService,
+	}
+}
+
+// Create Saved Search godoc
+// @Summary 	Saves a search, alerting its user of the offers of others that match its filter at most at its max amount
+// @Tags 		saved search
+// @Produce 	json
+// @Param 		data body model.SavedSearchInput true "The name, filter (E.g q=catan&condition=new), max amount and currency"
+// @Success 	200 {object} model.SavedSearch
+// @Router 		/saved-searches [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *SavedSearchController) Create(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.SavedSearchInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	filter, err := utils.GetSavedSearchFilter(input.Filter)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	search, err := controller.service.Create(&input, filter, user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, search)
+}
+
+// Get Saved Searches godoc
+// @Summary 	Fetches the saved searches of the user
+// @Tags 		saved search
+// @Produce 	json
+// @Success 	200 {array} model.SavedSearch
+// @Router 		/saved-searches [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *SavedSearchController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	searches, err := controller.service.GetAll(user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, searches)
+}
+
+// Delete Saved Search godoc
+// @Summary 	Deletes a saved search of the user, keeping the alerts it got
+// @Tags 		saved search
+// @Param 		id path string true "The Saved Search id"
+// @Success 	204
+// @Router 		/saved-searches/{id} [delete]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *SavedSearchController) Delete(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	uuid := utils.GetFieldFromURL(r, "id")
+	if err := controller.service.Delete(uuid, user); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, uuid)
+}
@@ -99,7 +99,7 @@ func (instance *PostgresqlRepository) Get(query string, value interface{}, args
 
 	err := instance.db.Get(value, query, args...)
 	if err != nil {
-		if err != sql.ErrNoRows {
+		if err == sql.ErrNoRows {
 			log.Println("Error Record not found with query: " + query)
 			return middleware.NewError(http.StatusNotFound, "Error - Record not found")
 		}
@@ -120,3 +120,44 @@ func (instance *PostgresqlRepository) ExecuteQuery(query string, value ...interf
 	log.Println("Created database entry: " + fmt.Sprintf("%v", value))
 	return nil
 }
+
+// Method that executes an update, returning how many entries it changed so conditional updates can tell if they applied
+func (instance *PostgresqlRepository) ExecuteUpdate(query string, value ...interface{}) (int64, error) {
+
+	result, err := instance.db.Exec(query, value...)
+	if err != nil {
+		log.Println("Error while updating database entries: " + fmt.Sprintf("%v", query))
+		return 0, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	log.Println("Updated " + fmt.Sprintf("%d", rows) + " database entries: " + fmt.Sprintf("%v", value))
+	return rows, nil
+}
+
+// Method that runs fn inside a transaction, committing it when fn succeeds and rolling it back otherwise
+func (instance *PostgresqlRepository) Transaction(fn func(tx *sqlx.Tx) error) error {
+
+	tx, err := instance.db.Beginx()
+	if err != nil {
+		log.Println("Error starting a transaction: " + err.Error())
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Println("Error rolling back a transaction: " + rollbackErr.Error())
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error committing a transaction: " + err.Error())
+		return err
+	}
+	return nil
+}
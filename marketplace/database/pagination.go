@@ -0,0 +1,166 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+// Column of the listing order and its direction
+type keysetColumn struct {
+	name  string
+	field reflect.StructField
+	desc  bool
+}
+
+// Position right after the last entry of a page, tied to the order it was created with
+type cursor struct {
+	Order  string            `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Method that fetches one page of the query, ordered by columns ending in a unique one (E.g added_at desc, uuid asc) so that keyset cursors are stable
+func (instance *PostgresqlRepository) GetPage(query, order string, page *model.Page, value interface{}, args ...interface{}) error {
+
+	columns, err := getKeysetColumns(reflect.TypeOf(value).Elem().Elem(), order)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	if err := instance.db.Get(&total, `SELECT COUNT(*) FROM (`+query+`) AS listing`, args...); err != nil {
+		log.Println("Error counting database entries: " + query)
+		return err
+	}
+	page.SetTotal(total)
+
+	pageQuery := `SELECT * FROM (` + query + `) AS listing`
+	if page.GetCursor() != "" {
+		search, identifiers, err := decodeCursor(page.GetCursor(), order, columns, len(args)+1)
+		if err != nil {
+			return err
+		}
+		pageQuery += ` WHERE ` + search
+		args = append(args, identifiers...)
+	}
+	pageQuery += ` ORDER BY ` + order + ` LIMIT $` + strconv.Itoa(len(args)+1) + ` OFFSET $` + strconv.Itoa(len(args)+2)
+	args = append(args, page.GetLimit()+1, page.GetOffset())
+
+	if err := instance.GetAll(pageQuery, value, args...); err != nil {
+		return err
+	}
+
+	// The extra entry only tells there is a next page
+	entries := reflect.ValueOf(value).Elem()
+	if entries.Len() > page.GetLimit() {
+		next, err := encodeCursor(entries.Index(page.GetLimit()-1), order, columns)
+		if err != nil {
+			log.Println("Error encoding page cursor: " + err.Error())
+			return err
+		}
+		page.SetNextCursor(next)
+		entries.Set(entries.Slice(0, page.GetLimit()))
+	}
+
+	return nil
+}
+
+// Function that maps the order (E.g added_at desc, uuid asc) into the struct fields with those db tags
+func getKeysetColumns(typ reflect.Type, order string) ([]keysetColumn, error) {
+
+	var columns []keysetColumn
+	for _, part := range strings.Split(order, ",") {
+		splits := strings.Fields(part)
+		if len(splits) == 0 {
+			continue
+		}
+
+		field, ok := lookUpField(typ, splits[0])
+		if !ok {
+			log.Println("Error - Unknown order column for pagination: " + order)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
+		}
+		columns = append(columns, keysetColumn{name: splits[0], field: field, desc: len(splits) > 1 && strings.EqualFold(splits[1], "desc")})
+	}
+	return columns, nil
+}
+
+// Function that finds the struct field of a column by its db tag
+func lookUpField(typ reflect.Type, column string) (reflect.StructField, bool) {
+
+	for _, field := range reflect.VisibleFields(typ) {
+		if field.Tag.Get("db") == column {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Function that stores the order values of the entry into an opaque cursor
+func encodeCursor(entry reflect.Value, order string, columns []keysetColumn) (string, error) {
+
+	c := cursor{Order: order}
+	for _, column := range columns {
+		raw, err := json.Marshal(entry.FieldByIndex(column.field.Index).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, raw)
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Function that compiles the cursor into the condition selecting the entries after it, numbering placeholders from start
+// E.g added_at desc, uuid asc ---> (added_at < $1) OR (added_at = $2 AND uuid > $3)
+func decodeCursor(encoded, order string, columns []keysetColumn, start int) (string, []interface{}, error) {
+
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Order != order || len(c.Values) != len(columns) {
+		log.Println("Error - Cursor malformed or from another order: " + encoded)
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.field.Type)
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			log.Println("Error - Cursor value malformed: " + encoded)
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var groups []string
+	var identifiers []interface{}
+	for i, column := range columns {
+		var clauses []string
+		for j, previous := range columns[:i] {
+			clauses = append(clauses, previous.name+" = $"+strconv.Itoa(start+len(identifiers)+j))
+		}
+		operator := " > $"
+		if column.desc {
+			operator = " < $"
+		}
+		clauses = append(clauses, column.name+operator+strconv.Itoa(start+len(identifiers)+i))
+
+		groups = append(groups, "("+strings.Join(clauses, " AND ")+")")
+		identifiers = append(identifiers, values[:i+1]...)
+	}
+	return "(" + strings.Join(groups, " OR ") + ")", identifiers, nil
+}
@@ -7,11 +7,13 @@ require (
 	github.com/go-chi/chi/v5 v5.0.7
 	github.com/go-chi/oauth v0.0.0-20210913085627-d937e221b3ef
 	github.com/golang/gddo v0.0.0-20210115222349-20d68f94ee1f
+	github.com/steinfletcher/apitest v1.5.14
 	github.com/swaggo/http-swagger v1.3.0
 	github.com/unrolled/render v1.5.0
 )
 
 require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gofrs/uuid v4.0.0+incompatible // indirect
 	github.com/google/go-cmp v0.5.8 // indirect
 )
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"marketplace/clients"
 	"marketplace/controllers"
 	"marketplace/database"
 	"marketplace/repositories"
 	"marketplace/route"
 	"marketplace/services"
+	"marketplace/storage"
 
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -38,22 +42,95 @@ func main() {
 	// Fetch Env variables
 	oauthKey, oauthKeyPresent := os.LookupEnv("OAUTH_KEY")
 	port, portPresent := os.LookupEnv("PORT")
-	if !oauthKeyPresent || !portPresent {
+	catalogURL, catalogURLPresent := os.LookupEnv("CATALOG_URL")
+	ratingURL, ratingURLPresent := os.LookupEnv("RATING_SERVICE_URL")
+	userURL, userURLPresent := os.LookupEnv("USER_MANAGEMENT_URL")
+	if !oauthKeyPresent || !portPresent || !catalogURLPresent || !ratingURLPresent || !userURLPresent {
 		log.Println("Error occurred while fetching essential env variables")
 		return
 	}
 
+	// Admins are the comma separated usernames of ADMIN_USERNAMES
+	var admins []string
+	for _, admin := range strings.Split(os.Getenv("ADMIN_USERNAMES"), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" {
+			admins = append(admins, admin)
+		}
+	}
+
+	catalogTimeout, err := getDuration("CATALOG_TIMEOUT", 5*time.Second)
+	if err != nil {
+		log.Println("Error occurred while parsing CATALOG_TIMEOUT: " + err.Error())
+		return
+	}
+	catalogCacheTTL, err := getDuration("CATALOG_CACHE_TTL", 5*time.Minute)
+	if err != nil {
+		log.Println("Error occurred while parsing CATALOG_CACHE_TTL: " + err.Error())
+		return
+	}
+	ratingTimeout, err := getDuration("RATING_SERVICE_TIMEOUT", 5*time.Second)
+	if err != nil {
+		log.Println("Error occurred while parsing RATING_SERVICE_TIMEOUT: " + err.Error())
+		return
+	}
+	userTimeout, err := getDuration("USER_MANAGEMENT_TIMEOUT", 5*time.Second)
+	if err != nil {
+		log.Println("Error occurred while parsing USER_MANAGEMENT_TIMEOUT: " + err.Error())
+		return
+	}
+	reputationInterval, err := getDuration("REPUTATION_SYNC_INTERVAL", 5*time.Minute)
+	if err != nil || reputationInterval <= 0 {
+		log.Println("Error occurred while parsing REPUTATION_SYNC_INTERVAL")
+		return
+	}
+	offerTTL, err := getDuration("OFFER_TTL", 30*24*time.Hour)
+	if err != nil {
+		log.Println("Error occurred while parsing OFFER_TTL: " + err.Error())
+		return
+	}
+	sweepInterval, err := getDuration("OFFER_SWEEP_INTERVAL", time.Minute)
+	if err != nil || sweepInterval <= 0 {
+		log.Println("Error occurred while parsing OFFER_SWEEP_INTERVAL")
+		return
+	}
+
+	// Images are kept on the local disk
+	imageDir, imageDirPresent := os.LookupEnv("IMAGE_STORAGE_DIR")
+	if !imageDirPresent {
+		imageDir = "images"
+	}
+	imageStorage, err := storage.NewLocalStorage(imageDir)
+	if err != nil {
+		log.Println("Error occurred while preparing image storage")
+		return
+	}
+
 	// Initialize Repositories and controllers
 	repositories := repositories.InitRepositories(db)
-	services := services.InitServices(repositories)
+	catalogClient := clients.NewCatalogClient(catalogURL, catalogTimeout, catalogCacheTTL)
+	ratingClient := clients.NewRatingClient(ratingURL, ratingTimeout)
+	userClient := clients.NewUserClient(userURL, userTimeout)
+	services := services.InitServices(repositories, catalogClient, ratingClient, userClient, imageStorage, offerTTL)
 	controllers := controllers.InitControllers(services)
 
+	// Expires stale offers in the background
+	go services.OfferService.Sweep(sweepInterval)
+
+	// Copies the reputation of sellers from the rating service in the background
+	go services.ReputationService.Sync(reputationInterval)
+
 	// Creates routing
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
 
 	// Adds Routers
-	route.AddOfferRouter(router, oauthKey, controllers.OfferController)
+	route.AddOfferRouter(router, oauthKey, admins, controllers.OfferController)
+	route.AddPurchaseRequestRouter(router, oauthKey, controllers.PurchaseRequestController)
+	route.AddOfferImageRouter(router, oauthKey, admins, controllers.OfferImageController)
+	route.AddExchangeRateRouter(router, oauthKey, admins, controllers.ExchangeRateController)
+	route.AddMarketRouter(router, controllers.MarketController)
+	route.AddSavedSearchRouter(router, oauthKey, controllers.SavedSearchController, controllers.AlertController)
+	route.AddModerationRouter(router, oauthKey, admins, controllers.ModerationController)
 
 	// documentation for developers
 	router.Get("/swagger/*", httpSwagger.Handler())
@@ -65,3 +142,12 @@ func main() {
 	}
 	log.Println("Server is Running on localhost:" + port)
 }
+
+// Function that reads a duration env variable, such as 30s, falling back to the default when unset
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return fallback, nil
+	}
+	return time.ParseDuration(value)
+}
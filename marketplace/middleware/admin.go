@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/oauth"
+)
+
+// Roles of users, carried by the role claim of their token
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// AdminOnly lets through only the requests whose token belongs to an admin -> Must run after oauth.Authorize
+func AdminOnly(admins []string) func(http.Handler) http.Handler {
+	return RequireRole(admins, RoleAdmin)
+}
+
+// RequireRole lets through only the requests whose token has one of the roles -> Must run after oauth.Authorize
+// The admins are admins whatever their token says, so the first ones can be appointed
+func RequireRole(admins []string, roles ...string) func(http.Handler) http.Handler {
+	hasRole := roleChecker(admins, roles...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+			if !hasRole(claims) {
+				log.Println("Error - Endpoint of roles " + strings.Join(roles, ",") + " requested by " + claims["username"])
+				ErrorHandler(w, NewError(http.StatusForbidden, "Only "+strings.Join(roles, " or ")+" users can do this"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Function that builds the check of whether the claims of a token have one of the roles, the admins always being admins
+func roleChecker(admins []string, roles ...string) func(claims map[string]string) bool {
+	allowed := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		allowed[admin] = true
+	}
+
+	granted := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		granted[role] = true
+	}
+
+	return func(claims map[string]string) bool {
+		username, ok := claims["username"]
+		return ok && (granted[claims["role"]] || granted[RoleAdmin] && allowed[username])
+	}
+}
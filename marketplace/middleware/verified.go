@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/oauth"
+)
+
+// VerifiedOnly lets through only the requests whose token says its user verified their email -> Must run after oauth.Authorize
+func VerifiedOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+		if claims["email_verified"] != "true" {
+			log.Println("Error - Endpoint of verified users requested by " + claims["username"])
+			ErrorHandler(w, NewError(http.StatusForbidden, "Verify your email to do this"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"marketplace/model"
+
+	"github.com/go-chi/oauth"
+)
+
+type viewerKey struct{}
+
+// Identify tells public endpoints who the Viewer is, authorizing the token when one is sent and letting anonymous requests through
+func Identify(oauthKey string, admins []string) func(http.Handler) http.Handler {
+	authorize := oauth.Authorize(oauthKey, nil)
+	isModerator := roleChecker(admins, RoleModerator, RoleAdmin)
+
+	return func(next http.Handler) http.Handler {
+		identified := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+			viewer := model.Viewer{Username: claims["username"], Moderator: isModerator(claims)}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), viewerKey{}, viewer)))
+		})
+		authorized := authorize(identified)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				identified.ServeHTTP(w, r)
+				return
+			}
+			authorized.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetViewer returns who the Viewer of the request is -> Anonymous unless Identify ran before
+func GetViewer(r *http.Request) model.Viewer {
+	viewer, _ := r.Context().Value(viewerKey{}).(model.Viewer)
+	return viewer
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Alert tells a buyer an Offer matched one of their saved searches
+// Alerts are never changed once added and their ids only grow, so any consumer (E.g a notifications service) reads them after the last id it got
+type Alert struct {
+	Id       int64  `json:"id" db:"id"`
+	Username string `json:"username" db:"username"`
+
+	// The saved search, as it was when matched
+	SearchUuid string `json:"search_uuid" db:"search_uuid"`
+	SearchName string `json:"search_name" db:"search_name"`
+
+	// The offer, as it was when matched -> The converted amount is in the currency of the search
+	OfferUuid         string    `json:"offer_uuid" db:"offer_uuid"`
+	OfferName         string    `json:"offer_name" db:"offer_name"`
+	CatalogRef        uint      `json:"catalog_ref" db:"catalog_ref"`
+	Amount            int64     `json:"amount" db:"amount"`
+	Currency          string    `json:"currency" db:"currency"`
+	ConvertedAmount   int64     `json:"converted_amount" db:"converted_amount"`
+	ConvertedCurrency string    `json:"converted_currency" db:"converted_currency"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+
+	Read bool `json:"read" db:"read"` // Up to the last alert the user marked read
+}
+
+// AlertReadInput marks the alerts of the inbox read, up to the given one
+type AlertReadInput struct {
+	LastId int64 `json:"last_id" valid:"required"`
+}
+
+// Get Schema
+func GetAlertSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Alert (
+			id bigserial,
+			username text NOT NULL,
+			search_uuid uuid NOT NULL,
+			search_name text NOT NULL,
+			offer_uuid uuid NOT NULL,
+			offer_name text NOT NULL,
+			catalog_ref bigint NOT NULL,
+			amount bigint NOT NULL,
+			currency text NOT NULL,
+			converted_amount bigint NOT NULL,
+			converted_currency text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (id),
+			UNIQUE (search_uuid, offer_uuid)
+		);
+	CREATE INDEX IF NOT EXISTS alert_username_idx ON Alert (username, id);
+
+	CREATE TABLE IF NOT EXISTS Alert_Read (
+			username text NOT NULL,
+			last_id bigint NOT NULL,
+			PRIMARY KEY (username)
+		);`
+
+	return schema
+}
@@ -0,0 +1,27 @@
+package model
+
+// Types an Offer can have, following the kind of its catalog entry
+const (
+	OfferTypeBoardgame = "Boardgame"
+	OfferTypeExpansion = "Expansion"
+)
+
+// CatalogBoardgame is the part of a catalog Boardgame that offers rely on
+type CatalogBoardgame struct {
+	ID          uint   `json:"ID"`
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher"`
+	BoardgameID *uint  `json:"boardgame_id,omitempty"` // Set when it is an expansion of another Boardgame
+}
+
+func (bg *CatalogBoardgame) IsExpansion() bool {
+	return bg.BoardgameID != nil
+}
+
+// GetOfferType returns the type an Offer of this catalog entry has
+func (bg *CatalogBoardgame) GetOfferType() string {
+	if bg.IsExpansion() {
+		return OfferTypeExpansion
+	}
+	return OfferTypeBoardgame
+}
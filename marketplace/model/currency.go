@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Minor units of the supported ISO-4217 currencies -> Amounts are integers of them (E.g 1050 EUR is 10.50€ and 1050 JPY is 1050¥)
+var minorUnits = map[string]int{
+	"EUR": 2, "USD": 2, "GBP": 2, "CHF": 2, "SEK": 2, "NOK": 2, "DKK": 2, "PLN": 2, "CZK": 2, "HUF": 2,
+	"RON": 2, "CAD": 2, "AUD": 2, "NZD": 2, "BRL": 2, "MXN": 2, "CNY": 2, "INR": 2, "ZAR": 2, "SGD": 2,
+	"JPY": 0, "KRW": 0, "ISK": 0,
+	"KWD": 3, "BHD": 3, "JOD": 3, "OMR": 3, "TND": 3,
+}
+
+// GetMinorUnit returns the number of decimals of the currency, telling if it is supported
+func GetMinorUnit(currency string) (int, bool) {
+	unit, ok := minorUnits[currency]
+	return unit, ok
+}
+
+// ExchangeRate is how many units of the currency one unit of the base currency buys -> The base has a rate of 1
+type ExchangeRate struct {
+	Currency  string    `json:"currency" db:"currency"`
+	Rate      string    `json:"rate" db:"rate"` // Exact decimal, never a float
+	MinorUnit int       `json:"minor_unit" db:"minor_unit"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ExchangeRatesInput replaces the whole exchange-rate table -> Rates are decimals, written as strings or numbers
+type ExchangeRatesInput struct {
+	Base  string                 `json:"base" valid:"required, ISO4217"`
+	Rates map[string]json.Number `json:"rates" valid:"required"`
+}
+
+func NewExchangeRate(currency, rate string) ExchangeRate {
+	unit, _ := GetMinorUnit(currency)
+	return ExchangeRate{
+		Currency:  currency,
+		Rate:      rate,
+		MinorUnit: unit,
+	}
+}
+
+// Get Schema -> convert_amount rounds half away from zero using only integer division of exact decimals, so no precision is lost
+func GetExchangeRateSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Exchange_Rate (
+			currency text,
+			rate numeric NOT NULL CHECK (rate > 0),
+			minor_unit smallint NOT NULL,
+			updated_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (currency)
+		);
+
+	CREATE OR REPLACE FUNCTION convert_amount(amount bigint, source text, target text) RETURNS bigint AS $$
+		SELECT CASE WHEN source = target THEN amount ELSE (
+			SELECT div(2 * amount * t.rate * 10::numeric ^ t.minor_unit + s.rate * 10::numeric ^ s.minor_unit, 2 * s.rate * 10::numeric ^ s.minor_unit)::bigint
+			FROM exchange_rate s, exchange_rate t
+			WHERE s.currency = source AND t.currency = target
+		) END
+	$$ LANGUAGE SQL STABLE;`
+
+	return schema
+}
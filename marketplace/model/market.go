@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// Buckets the price trend of a boardgame can be grouped by
+const (
+	MarketBucketDay   = "day"
+	MarketBucketWeek  = "week"
+	MarketBucketMonth = "month"
+)
+
+// MarketBuckets lists every bucket of the price trend
+var MarketBuckets = []string{MarketBucketDay, MarketBucketWeek, MarketBucketMonth}
+
+// MarketQuery holds the validated parameters of the market stats of a boardgame
+type MarketQuery struct {
+	CatalogRef uint
+	Currency   string        // Amounts are converted into it
+	Period     time.Duration // Only prices recorded within it
+	Bucket     string        // Size of the buckets of the trend
+}
+
+// MarketStats is the market value of a catalog boardgame, in minor units of the currency
+type MarketStats struct {
+	CatalogRef     uint   `json:"catalog_ref" db:"-"`
+	Currency       string `json:"currency" db:"-"`
+	ActiveListings int64  `json:"active_listings" db:"active_listings"`
+	MedianAsking   *int64 `json:"median_asking_amount" db:"median_asking_amount"` // Of the active listings
+	MedianSold     *int64 `json:"median_sold_amount" db:"median_sold_amount"`     // Of the sales within the period
+	Sales          int64  `json:"sales" db:"sales"`
+
+	Trend []PriceTrendBucket `json:"trend" db:"-"`
+}
+
+// PriceTrendBucket holds the prices recorded within a bucket of the period, empty ones included
+type PriceTrendBucket struct {
+	Start        time.Time `json:"start" db:"start"`
+	MedianAsking *int64    `json:"median_asking_amount" db:"median_asking_amount"` // Of the listings and repricings
+	MedianSold   *int64    `json:"median_sold_amount" db:"median_sold_amount"`
+	Listings     int64     `json:"listings" db:"listings"`
+	Sales        int64     `json:"sales" db:"sales"`
+}
+
+// IsMarketBucket tells if the bucket is one the trend can be grouped by
+func IsMarketBucket(bucket string) bool {
+	for _, known := range MarketBuckets {
+		if known == bucket {
+			return true
+		}
+	}
+	return false
+}
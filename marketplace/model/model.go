@@ -9,8 +9,16 @@ type SchemaAgregator struct{}
 
 func (SchemaAgregator) GetCreateSchemas() string {
 	offerSchema := GetOfferSchema()
+	purchaseRequestSchema := GetPurchaseRequestSchema()
+	exchangeRateSchema := GetExchangeRateSchema()
+	offerImageSchema := GetOfferImageSchema()
+	priceEventSchema := GetPriceEventSchema()
+	savedSearchSchema := GetSavedSearchSchema()
+	alertSchema := GetAlertSchema()
+	sellerReputationSchema := GetSellerReputationSchema()
+	moderationSchema := GetModerationSchema()
 
-	schema := offerSchema
+	schema := offerSchema + purchaseRequestSchema + exchangeRateSchema + offerImageSchema + priceEventSchema + savedSearchSchema + alertSchema + sellerReputationSchema + moderationSchema
 
 	return schema
 }
@@ -18,6 +26,17 @@ func (SchemaAgregator) GetCreateSchemas() string {
 func (SchemaAgregator) GetDropSchemas() string {
 
 	schema := `
+		drop table moderation_action;
+		drop table offer_report;
+		drop table seller_reputation;
+		drop table alert;
+		drop table saved_search;
+		drop table price_event;
+		drop table offer_image;
+		drop function convert_amount;
+		drop table exchange_rate;
+		drop table negotiation_event;
+		drop table purchase_request;
 		drop table offer;
 		`
 
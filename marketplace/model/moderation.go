@@ -0,0 +1,112 @@
+package model
+
+import "time"
+
+// Actions moderators take on offers, each recorded in the audit
+const (
+	ModerationHide     = "hide"     // Leaves the Offer out of every public read
+	ModerationRestore  = "restore"  // Shows a hidden Offer again
+	ModerationDismiss  = "dismiss"  // Resolves the open reports of the Offer, leaving it as it is
+	ModerationTransfer = "transfer" // Gives the Offer to another user
+	ModerationDelete   = "delete"   // Deletes the Offer whoever owns it
+)
+
+// ModerationAction is an entry of the audit -> Kept once its Offer is deleted
+type ModerationAction struct {
+	Id        int64     `json:"id" db:"id"`
+	OfferUuid string    `json:"offer_uuid" db:"offer_uuid"`
+	OfferName string    `json:"offer_name" db:"offer_name"`
+	Owner     string    `json:"owner" db:"owner"` // Seller of the Offer when the action was taken
+	Moderator string    `json:"moderator" db:"moderator"`
+	Action    string    `json:"action" db:"action"`
+	Reason    string    `json:"reason" db:"reason"`
+	NewOwner  *string   `json:"new_owner,omitempty" db:"new_owner"` // Of transfers
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ModerationInput is the body of every moderation action
+type ModerationInput struct {
+	Reason string `json:"reason" valid:"required, paragraph, maxstringlength(500)"`
+}
+
+// TransferInput is the body of an ownership transfer
+type TransferInput struct {
+	Username string `json:"username" valid:"required, text, maxstringlength(50)"` // Checked as a username by the service, answering 422
+	Reason   string `json:"reason" valid:"required, paragraph, maxstringlength(500)"`
+}
+
+// OfferReport is a user flagging an Offer for the moderators, open until a moderation action resolves it
+type OfferReport struct {
+	Uuid       string     `json:"uuid" db:"uuid"`
+	OfferUuid  string     `json:"offer_uuid" db:"offer_uuid"`
+	Reporter   string     `json:"reporter" db:"reporter"`
+	Reason     string     `json:"reason" db:"reason"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// ReportInput is the body of a new OfferReport
+type ReportInput struct {
+	Reason string `json:"reason" valid:"required, paragraph, maxstringlength(500)"`
+}
+
+// ModerationQueueItem is a reported Offer waiting for the moderators
+type ModerationQueueItem struct {
+	OfferUuid      string    `json:"offer_uuid" db:"offer_uuid"`
+	Name           string    `json:"name" db:"name"`
+	Username       string    `json:"username" db:"username"`
+	State          string    `json:"state" db:"state"`
+	Hidden         bool      `json:"hidden" db:"hidden"`
+	Reports        int64     `json:"reports" db:"reports"` // Open ones
+	LastReportedAt time.Time `json:"last_reported_at" db:"last_reported_at"`
+}
+
+func NewModerationAction(offerUuid, moderator, action, reason string) *ModerationAction {
+	return &ModerationAction{
+		OfferUuid: offerUuid,
+		Moderator: moderator,
+		Action:    action,
+		Reason:    reason,
+	}
+}
+
+func NewOfferReport(offerUuid, reporter, reason string) *OfferReport {
+	return &OfferReport{
+		OfferUuid: offerUuid,
+		Reporter:  reporter,
+		Reason:    reason,
+	}
+}
+
+// Get Schema
+func GetModerationSchema() string {
+	var schema = `
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS hidden boolean NOT NULL DEFAULT false;
+
+	CREATE TABLE IF NOT EXISTS Offer_Report (
+			uuid uuid DEFAULT gen_random_uuid (),
+			offer_uuid uuid NOT NULL REFERENCES Offer (uuid) ON DELETE CASCADE,
+			reporter text NOT NULL,
+			reason text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			resolved_at timestamptz,
+			PRIMARY KEY (uuid)
+		);
+	CREATE UNIQUE INDEX IF NOT EXISTS offer_report_open_idx ON Offer_Report (offer_uuid, reporter) WHERE resolved_at IS NULL;
+
+	CREATE TABLE IF NOT EXISTS Moderation_Action (
+			id bigserial,
+			offer_uuid uuid NOT NULL,
+			offer_name text NOT NULL,
+			owner text NOT NULL,
+			moderator text NOT NULL,
+			action text NOT NULL,
+			reason text NOT NULL,
+			new_owner text,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (id)
+		);
+	CREATE INDEX IF NOT EXISTS moderation_action_offer_uuid_idx ON Moderation_Action (offer_uuid);`
+
+	return schema
+}
@@ -7,36 +7,69 @@ type Offer struct {
 	Uuid    string    `json:"uuid,omitempty" db:"uuid" valid:"-"`
 	AddedAt time.Time `json:"-" db:"added_at" valid:"-"`
 
-	// User information
-	Username string `json:"username,omitempty" db:"username"`
-
-	// Catalog information
-	Type string `json:"type" db:"type" valid:"required, alphanum, maxstringlength(100)"`
-
-	// Offer information
-	Name  string  `json:"name" db:"name" valid:"required, alphanum, maxstringlength(100)"`
-	Price float64 `json:"price" db:"price" valid:"required, float, range(0|1000)"`
+	// Lifecycle -> Offers are created active unless asked as draft, and expire once active for too long
+	State     string     `json:"state" db:"state" valid:"in(draft|active)"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at" valid:"-"`
+
+	// Sale -> The buyer is the one of the purchase request the Offer was reserved for
+	Buyer  *string    `json:"-" db:"buyer" valid:"-"`
+	SoldAt *time.Time `json:"sold_at,omitempty" db:"sold_at" valid:"-"`
+
+	// Moderation -> Hidden offers are left out of every public read
+	Hidden bool `json:"hidden,omitempty" db:"hidden" valid:"-"`
+
+	// User information -> The seller, with their reputation as last synced from the rating service
+	Username          string   `json:"username,omitempty" db:"username"`
+	SellerRating      *float64 `json:"seller_rating" db:"seller_rating" valid:"-"`
+	SellerRatingCount int64    `json:"seller_rating_count" db:"seller_rating_count" valid:"-"`
+
+	// Catalog information -> The type follows the catalog entry when not given
+	CatalogRef uint   `json:"catalog_ref" db:"catalog_ref" valid:"required"`
+	Type       string `json:"type" db:"type" valid:"alphanum, maxstringlength(100)"`
+
+	// Offer information -> The amount is in minor units of the currency (E.g cents)
+	Name     string `json:"name" db:"name" valid:"required, text, maxstringlength(100)"`
+	Amount   int64  `json:"amount" db:"amount" valid:"required, range(1|100000000)"`
+	Currency string `json:"currency" db:"currency" valid:"required, ISO4217"`
+	OfferDetails
+
+	// Conversion into the currency a listing asked for
+	ConvertedAmount   *int64 `json:"converted_amount,omitempty" db:"converted_amount" valid:"-"`
+	ConvertedCurrency string `json:"converted_currency,omitempty" db:"-" valid:"-"`
 }
 
 type OfferUpdate struct {
-	Name  string  `json:"name" valid:"required, alphanum, maxstringlength(100)"`
-	Price float64 `json:"price" valid:"required, float, range(0|1000)"`
+	CatalogRef uint   `json:"catalog_ref" valid:"required"`
+	Name       string `json:"name" valid:"required, text, maxstringlength(100)"`
+	Amount     int64  `json:"amount" valid:"required, range(1|100000000)"`
+	Currency   string `json:"currency" valid:"required, ISO4217"`
+	OfferDetails
 }
 
 // Update
 func (off *Offer) UpdateOffer(offer *OfferUpdate) {
+	off.CatalogRef = offer.GetCatalogRef()
 	off.Name = offer.GetName()
-	off.Price = offer.GetPrice()
-
+	off.Amount = offer.GetAmount()
+	off.Currency = offer.GetCurrency()
+	off.OfferDetails = offer.OfferDetails
 }
 
 // Getters for Offer Update
+func (off *OfferUpdate) GetCatalogRef() uint {
+	return off.CatalogRef
+}
+
 func (off *OfferUpdate) GetName() string {
 	return off.Name
 }
 
-func (off *OfferUpdate) GetPrice() float64 {
-	return off.Price
+func (off *OfferUpdate) GetAmount() int64 {
+	return off.Amount
+}
+
+func (off *OfferUpdate) GetCurrency() string {
+	return off.Currency
 }
 
 // Getters for Offer
@@ -44,12 +77,20 @@ func (off *Offer) GetName() string {
 	return off.Name
 }
 
+func (off *Offer) GetCatalogRef() uint {
+	return off.CatalogRef
+}
+
 func (off *Offer) GetType() string {
 	return off.Type
 }
 
-func (off *Offer) GetPrice() float64 {
-	return off.Price
+func (off *Offer) GetAmount() int64 {
+	return off.Amount
+}
+
+func (off *Offer) GetCurrency() string {
+	return off.Currency
 }
 
 func (off *Offer) GetId() string {
@@ -60,6 +101,24 @@ func (off *Offer) GetUsername() string {
 	return off.Username
 }
 
+func (off *Offer) GetState() string {
+	return off.State
+}
+
+func (off *Offer) GetExpiresAt() *time.Time {
+	return off.ExpiresAt
+}
+
+// IsEditable tells if the Offer can still be updated, which is only before it is reserved or closed
+func (off *Offer) IsEditable() bool {
+	return off.State == OfferDraft || off.State == OfferActive
+}
+
+// IsPublic tells if anyone can see the Offer, which is only when it is listed and not hidden
+func (off *Offer) IsPublic() bool {
+	return !off.Hidden && IsPublicState(off.State)
+}
+
 // Set
 func (off *Offer) SetId(uuid string) {
 	off.Uuid = uuid
@@ -69,18 +128,55 @@ func (off *Offer) SetUsername(username string) {
 	off.Username = username
 }
 
+func (off *Offer) SetType(offerType string) {
+	off.Type = offerType
+}
+
+func (off *Offer) SetState(state string) {
+	off.State = state
+}
+
+func (off *Offer) SetExpiresAt(expiresAt *time.Time) {
+	off.ExpiresAt = expiresAt
+}
+
+func (off *Offer) SetConvertedCurrency(currency string) {
+	off.ConvertedCurrency = currency
+}
+
 // Get Schema
 func GetOfferSchema() string {
 	var schema = `
 	CREATE TABLE IF NOT EXISTS Offer (
 			uuid uuid DEFAULT gen_random_uuid (),
 			username text NOT NULL,
+			catalog_ref bigint NOT NULL DEFAULT 0,
 			type text,
 			name text,
-			price float,
+			amount bigint NOT NULL,
+			currency text NOT NULL,
+			state text NOT NULL DEFAULT 'active',
+			expires_at timestamptz,
+			buyer text,
+			sold_at timestamptz,
 			added_at timestamp DEFAULT now(),
 			PRIMARY KEY (uuid)
-		);`
+		);
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS catalog_ref bigint NOT NULL DEFAULT 0;
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS state text NOT NULL DEFAULT 'active';
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS buyer text;
+	ALTER TABLE Offer ADD COLUMN IF NOT EXISTS sold_at timestamptz;
+	CREATE INDEX IF NOT EXISTS offer_state_expires_at_idx ON Offer (state, expires_at);
+	` + getOfferDetailsSchema() + `
+
+	DO $$ BEGIN -- Prices used to be floats of euros
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'offer' AND column_name = 'price') THEN
+			ALTER TABLE Offer ADD COLUMN IF NOT EXISTS amount bigint, ADD COLUMN IF NOT EXISTS currency text;
+			UPDATE Offer SET amount = ROUND(price::numeric * 100), currency = 'EUR';
+			ALTER TABLE Offer ALTER COLUMN amount SET NOT NULL, ALTER COLUMN currency SET NOT NULL, DROP COLUMN price;
+		END IF;
+	END $$;`
 
 	return schema
 }
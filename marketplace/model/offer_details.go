@@ -0,0 +1,73 @@
+package model
+
+// Conditions a used boardgame can be graded with, from best to worst
+const (
+	ConditionNew     = "new"
+	ConditionLikeNew = "like-new"
+	ConditionGood    = "good"
+	ConditionWorn    = "worn"
+	defaultCondition = ConditionGood // Given to offers listed before conditions existed
+)
+
+// OfferConditions lists every condition an Offer can have
+var OfferConditions = []string{ConditionNew, ConditionLikeNew, ConditionGood, ConditionWorn}
+
+// OfferDetails describes what exactly is being sold and how it reaches the buyer
+type OfferDetails struct {
+	// Condition and completeness
+	Condition         string `json:"condition" db:"condition" valid:"required, in(new|like-new|good|worn)"`
+	Sleeved           bool   `json:"sleeved" db:"sleeved" valid:"-"`
+	MissingComponents bool   `json:"missing_components" db:"missing_components" valid:"-"`
+	Description       string `json:"description" db:"description" valid:"paragraph, maxstringlength(2000)"`
+
+	// Delivery -> At least one of them, and pickups need a location
+	Shipping bool   `json:"shipping" db:"shipping" valid:"-"`
+	Pickup   bool   `json:"pickup" db:"pickup" valid:"-"`
+	Location string `json:"location" db:"location" valid:"text, maxstringlength(100)"`
+}
+
+// IsCondition tells if the condition is one an Offer can be graded with
+func IsCondition(condition string) bool {
+	for _, known := range OfferConditions {
+		if known == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// Getters
+func (details *OfferDetails) GetCondition() string {
+	return details.Condition
+}
+
+func (details *OfferDetails) GetDescription() string {
+	return details.Description
+}
+
+func (details *OfferDetails) GetLocation() string {
+	return details.Location
+}
+
+func (details *OfferDetails) HasDelivery() bool {
+	return details.Shipping || details.Pickup
+}
+
+func (details *OfferDetails) NeedsLocation() bool {
+	return details.Pickup && details.Location == ""
+}
+
+// Get Schema -> Offers listed before details existed are graded good and shipped
+func getOfferDetailsSchema() string {
+	var schema = `
+	ALTER TABLE Offer
+		ADD COLUMN IF NOT EXISTS condition text NOT NULL DEFAULT '` + defaultCondition + `',
+		ADD COLUMN IF NOT EXISTS sleeved boolean NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS missing_components boolean NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS shipping boolean NOT NULL DEFAULT true,
+		ADD COLUMN IF NOT EXISTS pickup boolean NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS location text NOT NULL DEFAULT '';`
+
+	return schema
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// OfferFilter holds the validated conditions of an Offer listing -> Unset ones don't filter
+type OfferFilter struct {
+	States          []string
+	Currency        string // Amounts are converted into it, when given
+	MinAmount       *int64 // Minor units of the currency, only set along with it
+	MaxAmount       *int64
+	Conditions      []string
+	Type            string
+	Username        string
+	MinSellerRating *float64 // Only sellers rated at least this on average
+	CatalogRef      *uint
+	MaxAge          time.Duration // Only offers added within it
+	Search          []string      // Words the name must all contain
+	Order           string        // Order of the listing, ending in a unique column
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// Sizes an OfferImage is stored in, as the longest side in pixels
+const (
+	ImageSide     = 2048
+	ThumbnailSide = 320
+)
+
+// OfferImage is a photo of an Offer, stored with its thumbnail in the image storage
+type OfferImage struct {
+	Uuid      string    `json:"uuid" db:"uuid"`
+	OfferUuid string    `json:"-" db:"offer_uuid"`
+	Width     int       `json:"width" db:"width"`
+	Height    int       `json:"height" db:"height"`
+	Size      int64     `json:"size" db:"size"` // Bytes of the full image
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	URL          string `json:"url" db:"-"`
+	ThumbnailURL string `json:"thumbnail_url" db:"-"`
+}
+
+func NewOfferImage(offerUuid string, width, height int, size int64) *OfferImage {
+	return &OfferImage{
+		OfferUuid: offerUuid,
+		Width:     width,
+		Height:    height,
+		Size:      size,
+	}
+}
+
+func (img *OfferImage) GetId() string {
+	return img.Uuid
+}
+
+func (img *OfferImage) GetOfferId() string {
+	return img.OfferUuid
+}
+
+// Storage keys of the image and its thumbnail
+func (img *OfferImage) GetKey() string {
+	return img.Uuid + ".jpg"
+}
+
+func (img *OfferImage) GetThumbnailKey() string {
+	return img.Uuid + "_thumbnail.jpg"
+}
+
+// SetURLs sets where the image and its thumbnail are served
+func (img *OfferImage) SetURLs() {
+	img.URL = "/api/offer/" + img.OfferUuid + "/images/" + img.Uuid
+	img.ThumbnailURL = img.URL + "/thumbnail"
+}
+
+// Get Schema
+func GetOfferImageSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Offer_Image (
+			uuid uuid DEFAULT gen_random_uuid (),
+			offer_uuid uuid NOT NULL REFERENCES Offer (uuid) ON DELETE CASCADE,
+			width integer NOT NULL,
+			height integer NOT NULL,
+			size bigint NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (uuid)
+		);
+	CREATE INDEX IF NOT EXISTS offer_image_offer_idx ON Offer_Image (offer_uuid, created_at);`
+
+	return schema
+}
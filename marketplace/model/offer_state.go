@@ -0,0 +1,55 @@
+package model
+
+// States of the lifecycle of an Offer
+const (
+	OfferDraft     = "draft"
+	OfferActive    = "active"
+	OfferReserved  = "reserved"
+	OfferSold      = "sold"
+	OfferWithdrawn = "withdrawn"
+	OfferExpired   = "expired"
+)
+
+// OfferStates lists every state an Offer can be in
+var OfferStates = []string{OfferDraft, OfferActive, OfferReserved, OfferSold, OfferWithdrawn, OfferExpired}
+
+// PublicOfferStates lists the states anyone can see an Offer in -> Only its seller sees the others
+var PublicOfferStates = []string{OfferActive, OfferReserved, OfferSold}
+
+// Legal transitions from each state -> Sold and withdrawn offers are final
+var offerTransitions = map[string][]string{
+	OfferDraft:    {OfferActive, OfferWithdrawn},
+	OfferActive:   {OfferReserved, OfferSold, OfferWithdrawn, OfferExpired},
+	OfferReserved: {OfferActive, OfferSold, OfferWithdrawn},
+	OfferExpired:  {OfferActive, OfferWithdrawn},
+}
+
+// CanTransition tells if an Offer can go from one state into the other
+func CanTransition(from, to string) bool {
+	for _, state := range offerTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOfferState tells if the state is one of the lifecycle
+func IsOfferState(state string) bool {
+	for _, known := range OfferStates {
+		if known == state {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublicState tells if anyone can see offers in the state
+func IsPublicState(state string) bool {
+	for _, public := range PublicOfferStates {
+		if public == state {
+			return true
+		}
+	}
+	return false
+}
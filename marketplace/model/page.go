@@ -0,0 +1,53 @@
+package model
+
+// Page holds the requested window of a listing and, once read, where it stands in the whole listing
+type Page struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset,omitempty"`
+	Cursor     string `json:"-"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// Paginated is the response envelope of every listing
+type Paginated struct {
+	Items interface{} `json:"items"`
+	*Page
+}
+
+func NewPage(limit, offset int, cursor string) *Page {
+	return &Page{
+		Limit:  limit,
+		Offset: offset,
+		Cursor: cursor,
+	}
+}
+
+func NewPaginated(items interface{}, page *Page) *Paginated {
+	return &Paginated{
+		Items: items,
+		Page:  page,
+	}
+}
+
+// Getters
+func (page Page) GetLimit() int {
+	return page.Limit
+}
+
+func (page Page) GetOffset() int {
+	return page.Offset
+}
+
+func (page Page) GetCursor() string {
+	return page.Cursor
+}
+
+// Setters
+func (page *Page) SetNextCursor(cursor string) {
+	page.NextCursor = cursor
+}
+
+func (page *Page) SetTotal(total int64) {
+	page.Total = total
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Events of the price history of the boardgames
+const (
+	PriceListed  = "listed"  // Asked for as the Offer got listed
+	PriceChanged = "changed" // Asked for once the seller repriced the listed Offer
+	PriceSold    = "sold"    // Paid for the Offer, the accepted amount when it was negotiated
+)
+
+// AskingPriceEvents are the events of asking prices, as opposed to sold ones
+var AskingPriceEvents = []string{PriceListed, PriceChanged}
+
+// PriceEvent is a price an Offer was asked or sold for -> Kept once the Offer is deleted
+type PriceEvent struct {
+	Id         int64     `json:"-" db:"id"`
+	OfferUuid  string    `json:"offer_uuid" db:"offer_uuid"`
+	CatalogRef uint      `json:"catalog_ref" db:"catalog_ref"`
+	Event      string    `json:"event" db:"event"`
+	Amount     int64     `json:"amount" db:"amount"`
+	Currency   string    `json:"currency" db:"currency"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Get Schema
+func GetPriceEventSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Price_Event (
+			id bigserial,
+			offer_uuid uuid NOT NULL,
+			catalog_ref bigint NOT NULL,
+			event text NOT NULL,
+			amount bigint NOT NULL,
+			currency text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (id)
+		);
+	CREATE INDEX IF NOT EXISTS price_event_catalog_ref_idx ON Price_Event (catalog_ref, event, created_at);
+
+	-- Offers listed before the history was kept count as listed when they were added
+	INSERT INTO Price_Event (offer_uuid, catalog_ref, event, amount, currency, created_at)
+		SELECT uuid, catalog_ref, 'listed', amount, currency, added_at FROM Offer
+		WHERE state <> 'draft' AND NOT EXISTS (SELECT 1 FROM Price_Event);`
+
+	return schema
+}
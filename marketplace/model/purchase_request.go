@@ -0,0 +1,167 @@
+package model
+
+import "time"
+
+// Statuses of a PurchaseRequest -> Pending awaits the seller and countered awaits the buyer
+const (
+	RequestPending   = "pending"
+	RequestCountered = "countered"
+	RequestAccepted  = "accepted"
+	RequestRejected  = "rejected"
+	RequestDeclined  = "declined" // Another request of the offer was accepted
+	RequestReleased  = "released" // The offer was released, or withdrawn, after accepting it
+)
+
+// OpenRequestStatuses are the statuses of requests still under negotiation
+var OpenRequestStatuses = []string{RequestPending, RequestCountered}
+
+// Actions that make up the negotiation history
+const (
+	ActionRequest = "request"
+	ActionCounter = "counter"
+	ActionAccept  = "accept"
+	ActionReject  = "reject"
+	ActionDecline = "decline"
+	ActionRelease = "release"
+)
+
+// PurchaseRequest is a buyer's intent to buy an Offer at a price, negotiated with the seller
+type PurchaseRequest struct {
+	Uuid      string    `json:"uuid" db:"uuid"`
+	OfferUuid string    `json:"offer_uuid" db:"offer_uuid"`
+	Buyer     string    `json:"buyer" db:"buyer"`
+	Status    string    `json:"status" db:"status"`
+	Amount    int64     `json:"amount" db:"amount"`     // Amount currently on the table, in minor units of the currency
+	Currency  string    `json:"currency" db:"currency"` // Currency of the offer
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	History []NegotiationEvent `json:"history" db:"-"`
+}
+
+// NegotiationEvent is one step of the negotiation of a PurchaseRequest
+type NegotiationEvent struct {
+	Id          int64     `json:"-" db:"id"`
+	RequestUuid string    `json:"-" db:"request_uuid"`
+	Actor       string    `json:"actor" db:"actor"`
+	Action      string    `json:"action" db:"action"`
+	Amount      int64     `json:"amount" db:"amount"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// PurchaseRequestInput is the body of a new request, in minor units of the offer currency -> The offer amount is asked when none is given
+type PurchaseRequestInput struct {
+	Amount int64 `json:"amount" valid:"range(0|100000000)"`
+}
+
+// CounterInput is the body of a counter-price, in minor units of the offer currency
+type CounterInput struct {
+	Amount int64 `json:"amount" valid:"required, range(1|100000000)"`
+}
+
+func NewPurchaseRequest(offerUuid, buyer string, amount int64, currency string) *PurchaseRequest {
+	return &PurchaseRequest{
+		OfferUuid: offerUuid,
+		Buyer:     buyer,
+		Status:    RequestPending,
+		Amount:    amount,
+		Currency:  currency,
+	}
+}
+
+func NewNegotiationEvent(actor, action string, amount int64) NegotiationEvent {
+	return NegotiationEvent{
+		Actor:  actor,
+		Action: action,
+		Amount: amount,
+	}
+}
+
+// Getters
+func (input *PurchaseRequestInput) GetAmount() int64 {
+	return input.Amount
+}
+
+func (input *CounterInput) GetAmount() int64 {
+	return input.Amount
+}
+
+func (req *PurchaseRequest) GetId() string {
+	return req.Uuid
+}
+
+func (req *PurchaseRequest) GetOfferId() string {
+	return req.OfferUuid
+}
+
+func (req *PurchaseRequest) GetBuyer() string {
+	return req.Buyer
+}
+
+func (req *PurchaseRequest) GetStatus() string {
+	return req.Status
+}
+
+func (req *PurchaseRequest) GetAmount() int64 {
+	return req.Amount
+}
+
+func (req *PurchaseRequest) GetCurrency() string {
+	return req.Currency
+}
+
+// Set
+func (req *PurchaseRequest) SetStatus(status string) {
+	req.Status = status
+}
+
+func (req *PurchaseRequest) SetAmount(amount int64) {
+	req.Amount = amount
+}
+
+func (req *PurchaseRequest) SetCurrency(currency string) {
+	req.Currency = currency
+}
+
+// Get Schema -> Buyers keep a single open request per offer, whose whole negotiation is kept as events
+func GetPurchaseRequestSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Purchase_Request (
+			uuid uuid DEFAULT gen_random_uuid (),
+			offer_uuid uuid NOT NULL REFERENCES Offer (uuid) ON DELETE CASCADE,
+			buyer text NOT NULL,
+			status text NOT NULL,
+			amount bigint NOT NULL,
+			currency text NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			updated_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (uuid)
+		);
+	CREATE UNIQUE INDEX IF NOT EXISTS purchase_request_open_idx ON Purchase_Request (offer_uuid, buyer) WHERE status IN ('pending', 'countered');
+
+	CREATE TABLE IF NOT EXISTS Negotiation_Event (
+			id bigserial,
+			request_uuid uuid NOT NULL REFERENCES Purchase_Request (uuid) ON DELETE CASCADE,
+			actor text NOT NULL,
+			action text NOT NULL,
+			amount bigint NOT NULL,
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (id)
+		);
+	CREATE INDEX IF NOT EXISTS negotiation_event_request_idx ON Negotiation_Event (request_uuid, id);
+
+	DO $$ BEGIN -- Prices used to be floats of euros
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'purchase_request' AND column_name = 'price') THEN
+			ALTER TABLE Purchase_Request ADD COLUMN IF NOT EXISTS amount bigint, ADD COLUMN IF NOT EXISTS currency text;
+			UPDATE Purchase_Request SET amount = ROUND(price::numeric * 100), currency = 'EUR';
+			ALTER TABLE Purchase_Request ALTER COLUMN amount SET NOT NULL, ALTER COLUMN currency SET NOT NULL, DROP COLUMN price;
+		END IF;
+		IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'negotiation_event' AND column_name = 'price') THEN
+			ALTER TABLE Negotiation_Event ADD COLUMN IF NOT EXISTS amount bigint;
+			UPDATE Negotiation_Event SET amount = ROUND(price::numeric * 100);
+			ALTER TABLE Negotiation_Event ALTER COLUMN amount SET NOT NULL, DROP COLUMN price;
+		END IF;
+	END $$;`
+
+	return schema
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Namespace sellers are rated under in the rating service, their username being the reference id
+const UserNamespace = "user"
+
+// SellerReputation is the rating of a seller by their buyers, as last synced from the rating service
+type SellerReputation struct {
+	Username  string    `db:"username"`
+	Count     int64     `db:"count"`
+	Mean      *float64  `db:"mean"` // Unset without ratings
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// RatingSummary is the aggregation of the ratings of an object by the rating service
+type RatingSummary struct {
+	ReferenceNamespace string  `json:"reference_namespace"`
+	ReferenceId        string  `json:"reference_id"`
+	Count              int64   `json:"count"`
+	Mean               float64 `json:"mean"`
+}
+
+// NewSellerReputation keeps the summary of the ratings of a seller
+func NewSellerReputation(summary RatingSummary) SellerReputation {
+	reputation := SellerReputation{
+		Username: summary.ReferenceId,
+		Count:    summary.Count,
+	}
+	if summary.Count > 0 {
+		mean := summary.Mean
+		reputation.Mean = &mean
+	}
+	return reputation
+}
+
+// Get Schema
+func GetSellerReputationSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Seller_Reputation (
+			username text,
+			count bigint NOT NULL DEFAULT 0,
+			mean float8,
+			updated_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (username)
+		);`
+
+	return schema
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Sale proves an Offer was sold, and to whom -> Only shown to its seller and buyer
+type Sale struct {
+	OfferUuid  string    `json:"offer_uuid"`
+	CatalogRef uint      `json:"catalog_ref"`
+	Seller     string    `json:"seller"`
+	Buyer      string    `json:"buyer,omitempty"` // Unset when it was sold without a purchase request
+	SoldAt     time.Time `json:"sold_at"`
+}
+
+// NewSale builds the Sale of a sold Offer
+func NewSale(offer *Offer) Sale {
+	sale := Sale{
+		OfferUuid:  offer.GetId(),
+		CatalogRef: offer.GetCatalogRef(),
+		Seller:     offer.GetUsername(),
+	}
+	if offer.Buyer != nil {
+		sale.Buyer = *offer.Buyer
+	}
+	if offer.SoldAt != nil {
+		sale.SoldAt = *offer.SoldAt
+	}
+	return sale
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SavedSearch is what a buyer wants, the offers matching its filter at most at its max amount, which are sent to their alerts
+type SavedSearch struct {
+	Uuid      string    `json:"uuid" db:"uuid"`
+	Username  string    `json:"username" db:"username"`
+	Name      string    `json:"name" db:"name"`
+	Filter    string    `json:"filter" db:"filter"`         // Listing query parameters (E.g q=catan&condition=new,like-new)
+	MaxAmount int64     `json:"max_amount" db:"max_amount"` // In minor units of the currency, offers of others are converted into it
+	Currency  string    `json:"currency" db:"currency"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// The filter, compiled so offers are matched against it -> Unset ones don't filter
+	CatalogRef *uint          `json:"-" db:"catalog_ref"`
+	Type       string         `json:"-" db:"type"`
+	Conditions pq.StringArray `json:"-" db:"conditions"`
+	Words      pq.StringArray `json:"-" db:"words"` // Words the name must all contain
+}
+
+// SavedSearchInput is the body of a new SavedSearch
+type SavedSearchInput struct {
+	Name      string `json:"name" valid:"required, text, maxstringlength(100)"`
+	Filter    string `json:"filter" valid:"required, maxstringlength(500)"`
+	MaxAmount int64  `json:"max_amount" valid:"required, range(1|100000000)"`
+	Currency  string `json:"currency" valid:"required, ISO4217"`
+}
+
+// NewSavedSearch compiles the input, whose filter was validated into the OfferFilter, into the SavedSearch of the user
+func NewSavedSearch(username string, input *SavedSearchInput, filter *OfferFilter) *SavedSearch {
+	return &SavedSearch{
+		Username:   username,
+		Name:       input.Name,
+		Filter:     input.Filter,
+		MaxAmount:  input.MaxAmount,
+		Currency:   input.Currency,
+		CatalogRef: filter.CatalogRef,
+		Type:       filter.Type,
+		Conditions: append(pq.StringArray{}, filter.Conditions...), // Never nil, which would be stored as NULL
+		Words:      append(pq.StringArray{}, filter.Search...),
+	}
+}
+
+func (search *SavedSearch) GetId() string {
+	return search.Uuid
+}
+
+func (search *SavedSearch) GetUsername() string {
+	return search.Username
+}
+
+// Get Schema
+func GetSavedSearchSchema() string {
+	var schema = `
+	CREATE TABLE IF NOT EXISTS Saved_Search (
+			uuid uuid DEFAULT gen_random_uuid (),
+			username text NOT NULL,
+			name text NOT NULL,
+			filter text NOT NULL,
+			max_amount bigint NOT NULL,
+			currency text NOT NULL,
+			catalog_ref bigint,
+			type text NOT NULL DEFAULT '',
+			conditions text[] NOT NULL DEFAULT '{}',
+			words text[] NOT NULL DEFAULT '{}',
+			created_at timestamptz NOT NULL DEFAULT now(),
+			PRIMARY KEY (uuid)
+		);
+	CREATE INDEX IF NOT EXISTS saved_search_username_idx ON Saved_Search (username);`
+
+	return schema
+}
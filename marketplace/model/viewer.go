@@ -0,0 +1,12 @@
+package model
+
+// Viewer is who reads offers, anonymous when they send no token
+type Viewer struct {
+	Username  string
+	Moderator bool // Moderators and admins see every Offer
+}
+
+// CanSee tells if the Viewer may see the Offer -> Drafts, withdrawn, expired and hidden offers only to their seller and moderators
+func (viewer Viewer) CanSee(offer *Offer) bool {
+	return offer.IsPublic() || viewer.Moderator || viewer.Username != "" && viewer.Username == offer.GetUsername()
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"net/http"
+	"strconv"
+
+	"marketplace/database"
+	"marketplace/middleware"
+	"marketplace/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Namespace of the locks, one per user, that make the alerts of each inbox commit in the order of their ids
+const alertLock = 8018
+
+// Saved searches of others the listed Offer matches
+const alertMatch = `
+	FROM offer
+	JOIN saved_search AS search ON search.username <> offer.username
+	CROSS JOIN LATERAL (SELECT convert_amount(offer.amount, offer.currency, search.currency) AS amount) AS converted
+	WHERE offer.uuid = $1 AND offer.state = $2 AND NOT offer.hidden
+		AND (search.catalog_ref IS NULL OR search.catalog_ref = offer.catalog_ref)
+		AND (search.type = '' OR search.type = offer.type)
+		AND (cardinality(search.conditions) = 0 OR offer.condition = ANY(search.conditions))
+		AND NOT EXISTS (SELECT 1 FROM unnest(search.words) AS word WHERE position(lower(word) IN lower(offer.name)) = 0)
+		AND converted.amount <= search.max_amount`
+
+type AlertRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewAlertRepository(instance *database.PostgresqlRepository) *AlertRepository {
+	return &AlertRepository{
+		db: instance,
+	}
+}
+
+// Fetches a page of the alerts of the user added after the given id, oldest first
+func (repo *AlertRepository) GetAll(username string, after int64, page *model.Page) ([]model.Alert, error) {
+
+	alerts := []model.Alert{}
+	query := `
+	SELECT alert.*, alert.id <= coalesce(marker.last_id, 0) AS read
+	FROM alert LEFT JOIN alert_read AS marker ON marker.username = alert.username
+	WHERE alert.username=$1 AND alert.id > $2`
+	return alerts, repo.db.GetPage(query, "id asc", page, &alerts, username, after)
+}
+
+// Marks the alerts of the user read up to the given one, which must be theirs -> Alerts already marked read stay read
+func (repo *AlertRepository) MarkRead(username string, lastId int64) error {
+
+	query := `
+	INSERT INTO alert_read (username, last_id)
+	SELECT username, id FROM alert WHERE id=$1 AND username=$2
+	ON CONFLICT (username) DO UPDATE SET last_id = greatest(alert_read.last_id, excluded.last_id)`
+	rows, err := repo.db.ExecuteUpdate(query, lastId, username)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return middleware.NewError(http.StatusNotFound, "Alert not found with id: "+strconv.FormatInt(lastId, 10))
+	}
+	return nil
+}
+
+// Function that adds an alert for every saved search of others the listed Offer matches, once per search and offer
+// The inboxes getting alerts stay locked until the transaction ends, so an alert never shows up behind one that was already read
+// Each user is locked on their own, in username order, so offer writes only wait on each other when alerting the same users
+func addAlerts(tx *sqlx.Tx, offerUuid string) error {
+
+	lock := `SELECT pg_advisory_xact_lock($3::int, hashtext(username))
+	FROM (SELECT DISTINCT search.username ` + alertMatch + ` ORDER BY search.username) AS matched`
+	if _, err := tx.Exec(lock, offerUuid, model.OfferActive, alertLock); err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO alert (username, search_uuid, search_name, offer_uuid, offer_name, catalog_ref, amount, currency, converted_amount, converted_currency)
+	SELECT search.username, search.uuid, search.name, offer.uuid, offer.name, offer.catalog_ref, offer.amount, offer.currency, converted.amount, search.currency` + alertMatch + `
+	ON CONFLICT (search_uuid, offer_uuid) DO NOTHING`
+	_, err := tx.Exec(query, offerUuid, model.OfferActive)
+	return err
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"marketplace/database"
+	"marketplace/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ExchangeRateRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewExchangeRateRepository(instance *database.PostgresqlRepository) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db: instance,
+	}
+}
+
+func (repo *ExchangeRateRepository) GetAll() ([]model.ExchangeRate, error) {
+
+	rates := []model.ExchangeRate{}
+	query := `SELECT * FROM exchange_rate ORDER BY currency asc`
+	return rates, repo.db.GetAll(query, &rates)
+}
+
+func (repo *ExchangeRateRepository) Get(currency string) (model.ExchangeRate, error) {
+
+	var rate model.ExchangeRate
+	query := `SELECT * FROM exchange_rate WHERE currency=$1`
+	return rate, repo.db.Get(query, &rate, currency)
+}
+
+// Replaces the whole table at once, so conversions never mix rates of different bases
+func (repo *ExchangeRateRepository) Replace(rates []model.ExchangeRate) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM exchange_rate`); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO exchange_rate (currency, rate, minor_unit) VALUES ($1, $2, $3)`
+		for _, rate := range rates {
+			if _, err := tx.Exec(query, rate.Currency, rate.Rate, rate.MinorUnit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"marketplace/database"
+	"marketplace/model"
+
+	"github.com/lib/pq"
+)
+
+// Median of the amounts converted into the currency, which leaves out the ones without a rate
+const convertedMedian = `ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY convert_amount(amount, currency, $2)))::bigint`
+
+// MarketRepository reads the market value of the boardgames off the offers and their price history
+type MarketRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewMarketRepository(instance *database.PostgresqlRepository) *MarketRepository {
+	return &MarketRepository{
+		db: instance,
+	}
+}
+
+// Fetches the stats of the boardgame, with the trend of its prices bucketed over the period
+func (repo *MarketRepository) GetStats(query *model.MarketQuery) (model.MarketStats, error) {
+
+	var stats model.MarketStats
+	period := query.Period.Seconds()
+
+	summary := `
+	SELECT
+		(SELECT count(*) FROM offer WHERE catalog_ref=$1 AND state=$3 AND NOT hidden) AS active_listings,
+		(SELECT ` + convertedMedian + ` FROM offer WHERE catalog_ref=$1 AND state=$3 AND NOT hidden) AS median_asking_amount,
+		(SELECT ` + convertedMedian + ` FROM price_event
+			WHERE catalog_ref=$1 AND event=$4 AND created_at >= now() - make_interval(secs => $5)) AS median_sold_amount,
+		(SELECT count(*) FROM price_event
+			WHERE catalog_ref=$1 AND event=$4 AND created_at >= now() - make_interval(secs => $5)) AS sales`
+	if err := repo.db.Get(summary, &stats, query.CatalogRef, query.Currency, model.OfferActive, model.PriceSold, period); err != nil {
+		return stats, err
+	}
+
+	// Every bucket of the period is listed, so gaps in the trend show
+	trend := `
+	SELECT buckets.start,
+		ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY prices.converted) FILTER (WHERE prices.event = ANY($4)))::bigint AS median_asking_amount,
+		ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY prices.converted) FILTER (WHERE prices.event = $5))::bigint AS median_sold_amount,
+		count(prices.converted) FILTER (WHERE prices.event = ANY($4)) AS listings,
+		count(prices.converted) FILTER (WHERE prices.event = $5) AS sales
+	FROM generate_series(date_trunc($3, now() - make_interval(secs => $6)), date_trunc($3, now()), ('1 ' || $3)::interval) AS buckets(start)
+	LEFT JOIN (
+		SELECT event, created_at, convert_amount(amount, currency, $2) AS converted FROM price_event
+		WHERE catalog_ref=$1 AND created_at >= now() - make_interval(secs => $6)
+	) AS prices ON date_trunc($3, prices.created_at) = buckets.start
+	GROUP BY buckets.start
+	ORDER BY buckets.start asc`
+	if err := repo.db.GetAll(trend, &stats.Trend, query.CatalogRef, query.Currency, query.Bucket, pq.Array(model.AskingPriceEvents), model.PriceSold, period); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
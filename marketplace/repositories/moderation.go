@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"marketplace/database"
+	"marketplace/middleware"
+	"marketplace/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ModerationRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewModerationRepository(instance *database.PostgresqlRepository) *ModerationRepository {
+	return &ModerationRepository{
+		db: instance,
+	}
+}
+
+// Creates the report, as long as the reporter has no other open one on the Offer
+func (repo *ModerationRepository) CreateReport(report *model.OfferReport) error {
+
+	query := `INSERT INTO offer_report (offer_uuid, reporter, reason) VALUES ($1, $2, $3) RETURNING *`
+	if err := repo.db.Get(query, report, report.OfferUuid, report.Reporter, report.Reason); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return middleware.NewError(http.StatusConflict, "There already is an open report of yours on this offer")
+		}
+		return err
+	}
+	return nil
+}
+
+// Fetches the reports of the Offer, open ones first and then newest first
+func (repo *ModerationRepository) GetReports(offerUuid string) ([]model.OfferReport, error) {
+
+	reports := []model.OfferReport{}
+	query := `SELECT * FROM offer_report WHERE offer_uuid=$1 ORDER BY resolved_at IS NOT NULL, created_at desc`
+	return reports, repo.db.GetAll(query, &reports, offerUuid)
+}
+
+// Fetches a page of the offers with at least the given open reports, most reported first
+func (repo *ModerationRepository) GetQueue(minReports int, page *model.Page) ([]model.ModerationQueueItem, error) {
+
+	items := []model.ModerationQueueItem{}
+	query := `
+	SELECT offer.uuid AS offer_uuid, offer.name, offer.username, offer.state, offer.hidden,
+		count(*) AS reports, max(report.created_at) AS last_reported_at
+	FROM offer_report AS report JOIN offer ON offer.uuid = report.offer_uuid
+	WHERE report.resolved_at IS NULL
+	GROUP BY offer.uuid
+	HAVING count(*) >= $1`
+	return items, repo.db.GetPage(query, "reports desc, offer_uuid asc", page, &items, minReports)
+}
+
+// Fetches a page of the audit, of the Offer when one is given, newest first
+func (repo *ModerationRepository) GetActions(offerUuid string, page *model.Page) ([]model.ModerationAction, error) {
+
+	actions := []model.ModerationAction{}
+	if offerUuid != "" {
+		query := `SELECT * FROM moderation_action WHERE offer_uuid=$1`
+		return actions, repo.db.GetPage(query, "id desc", page, &actions, offerUuid)
+	}
+
+	query := `SELECT * FROM moderation_action`
+	return actions, repo.db.GetPage(query, "id desc", page, &actions)
+}
+
+// Applies the moderation action to its Offer and records it in the audit, all at once
+func (repo *ModerationRepository) Moderate(action *model.ModerationAction) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		var offer model.Offer
+		if err := tx.Get(&offer, `SELECT * FROM offer WHERE uuid=$1 FOR UPDATE`, action.OfferUuid); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return middleware.NewError(http.StatusNotFound, "Offer not found with id: "+action.OfferUuid)
+			}
+			return err
+		}
+		action.OfferName, action.Owner = offer.GetName(), offer.GetUsername()
+
+		if err := applyModeration(tx, offer, action); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO moderation_action (offer_uuid, offer_name, owner, moderator, action, reason, new_owner)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+		return tx.Get(action, query, action.OfferUuid, action.OfferName, action.Owner, action.Moderator, action.Action, action.Reason, action.NewOwner)
+	})
+}
+
+// Function that changes the locked Offer as the action says, failing with a conflict when it makes no difference
+func applyModeration(tx *sqlx.Tx, offer model.Offer, action *model.ModerationAction) error {
+
+	switch action.Action {
+	case model.ModerationHide:
+		if offer.Hidden {
+			return middleware.NewError(http.StatusConflict, "Offer is already hidden")
+		}
+		if _, err := tx.Exec(`UPDATE offer SET hidden=true WHERE uuid=$1`, offer.GetId()); err != nil {
+			return err
+		}
+		_, err := resolveReports(tx, offer.GetId())
+		return err
+
+	case model.ModerationRestore:
+		if !offer.Hidden {
+			return middleware.NewError(http.StatusConflict, "Offer is not hidden")
+		}
+		_, err := tx.Exec(`UPDATE offer SET hidden=false WHERE uuid=$1`, offer.GetId())
+		return err
+
+	case model.ModerationDismiss:
+		resolved, err := resolveReports(tx, offer.GetId())
+		if err == nil && resolved == 0 {
+			return middleware.NewError(http.StatusConflict, "Offer has no open reports")
+		}
+		return err
+
+	case model.ModerationTransfer:
+		if action.NewOwner == nil || *action.NewOwner == offer.GetUsername() {
+			return middleware.NewError(http.StatusUnprocessableEntity, "Offer already belongs to this user")
+		}
+		_, err := tx.Exec(`UPDATE offer SET username=$1 WHERE uuid=$2`, *action.NewOwner, offer.GetId())
+		return err
+
+	case model.ModerationDelete:
+		_, err := tx.Exec(`DELETE FROM offer WHERE uuid=$1`, offer.GetId())
+		return err
+	}
+
+	return middleware.NewError(http.StatusUnprocessableEntity, "Unknown moderation action: "+action.Action)
+}
+
+// Function that resolves the open reports of the Offer, returning how many there were
+func resolveReports(tx *sqlx.Tx, offerUuid string) (int64, error) {
+
+	result, err := tx.Exec(`UPDATE offer_report SET resolved_at=now() WHERE offer_uuid=$1 AND resolved_at IS NULL`, offerUuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
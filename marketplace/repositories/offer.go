@@ -1,57 +1,248 @@
 package repositories
 
 import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"marketplace/database"
+	"marketplace/middleware"
 	"marketplace/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type OfferRepository struct {
 	db *database.PostgresqlRepository
 }
 
+// Offers with the reputation of their seller, sellers never synced counting as unrated
+const offerWithReputation = `(SELECT offer.*, reputation.mean AS seller_rating, COALESCE(reputation.count, 0) AS seller_rating_count
+	FROM offer LEFT JOIN seller_reputation AS reputation ON reputation.username = offer.username) AS offer`
+
+// Escapes the wildcards of LIKE, so searched words are matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// offerConditions gathers the conditions of a listing, numbering the placeholders of their arguments in order
+type offerConditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// placeholder adds the argument, returning its placeholder
+func (oc *offerConditions) placeholder(arg interface{}) string {
+	oc.args = append(oc.args, arg)
+	return "$" + strconv.Itoa(len(oc.args))
+}
+
+// add appends the condition, replacing its ? by the placeholder of the argument
+func (oc *offerConditions) add(condition string, arg interface{}) {
+	oc.clauses = append(oc.clauses, strings.Replace(condition, "?", oc.placeholder(arg), 1))
+}
+
 func NewOfferRepository(instance *database.PostgresqlRepository) *OfferRepository {
 	return &OfferRepository{
 		db: instance,
 	}
 }
 
+// Creates the Offer, recording its asking price and alerting the searches it matches when it is listed right away
 func (repo *OfferRepository) Create(offer *model.Offer) error {
 
-	query := `INSERT INTO offer (username, type, name, price) VALUES ($1, $2, $3, $4) RETURNING uuid`
-	uuid, err := repo.db.Create(query, offer.GetUsername(), offer.GetType(), offer.GetName(), offer.GetPrice())
-	if err != nil {
-		return err
-	}
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		var uuid string
+		query := `INSERT INTO offer (username, catalog_ref, type, name, amount, currency, state, expires_at,
+			condition, sleeved, missing_components, description, shipping, pickup, location)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING uuid`
+		if err := tx.Get(&uuid, query, offer.GetUsername(), offer.GetCatalogRef(), offer.GetType(), offer.GetName(), offer.GetAmount(), offer.GetCurrency(), offer.GetState(), offer.GetExpiresAt(),
+			offer.Condition, offer.Sleeved, offer.MissingComponents, offer.Description, offer.Shipping, offer.Pickup, offer.Location); err != nil {
+			return err
+		}
+
+		offer.SetId(uuid)
 
-	offer.SetId(uuid)
+		// Its seller may already be rated
+		reputation := `SELECT reputation.mean AS seller_rating, COALESCE(reputation.count, 0) AS seller_rating_count
+			FROM (SELECT $1::text AS username) AS seller LEFT JOIN seller_reputation AS reputation ON reputation.username = seller.username`
+		if err := tx.Get(offer, reputation, offer.GetUsername()); err != nil {
+			return err
+		}
 
-	return nil
+		if offer.GetState() != model.OfferActive {
+			return nil
+		}
+		if err := addPriceEvent(tx, *offer, model.PriceListed); err != nil {
+			return err
+		}
+		return addAlerts(tx, offer.GetId())
+	})
 }
 
-func (repo *OfferRepository) ReadAll() ([]model.Offer, error) {
+// Fetches a page of the offers matching the filter, in its order -> With a currency, only offers that can be converted into it
+func (repo *OfferRepository) ReadAll(filter *model.OfferFilter, page *model.Page) ([]model.Offer, error) {
+
+	var conditions offerConditions
+	source := offerWithReputation
+	if filter.Currency != "" { // Amount ranges only come with a currency, so they always compare converted amounts
+		source = "(SELECT offer.*, convert_amount(amount, currency, " + conditions.placeholder(filter.Currency) + ") AS converted_amount FROM " + offerWithReputation + ") AS offer"
+		conditions.clauses = append(conditions.clauses, "converted_amount IS NOT NULL")
+		if filter.MinAmount != nil {
+			conditions.add("converted_amount >= ?", *filter.MinAmount)
+		}
+		if filter.MaxAmount != nil {
+			conditions.add("converted_amount <= ?", *filter.MaxAmount)
+		}
+	}
+
+	conditions.clauses = append(conditions.clauses, "NOT hidden")
+	conditions.add("state = ANY(?)", pq.Array(filter.States))
+	if len(filter.Conditions) > 0 {
+		conditions.add("condition = ANY(?)", pq.Array(filter.Conditions))
+	}
+	if filter.Type != "" {
+		conditions.add("type = ?", filter.Type)
+	}
+	if filter.Username != "" {
+		conditions.add("username = ?", filter.Username)
+	}
+	if filter.MinSellerRating != nil {
+		conditions.add("seller_rating >= ?", *filter.MinSellerRating)
+	}
+	if filter.CatalogRef != nil {
+		conditions.add("catalog_ref = ?", *filter.CatalogRef)
+	}
+	if filter.MaxAge > 0 {
+		conditions.add("added_at >= now() - make_interval(secs => ?)", filter.MaxAge.Seconds())
+	}
+	for _, word := range filter.Search {
+		conditions.add(`name ILIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(word)+"%")
+	}
 
 	var offers []model.Offer
-	query := "SELECT * FROM offer"
-	return offers, repo.db.GetAll(query, &offers)
+	query := "SELECT * FROM " + source + " WHERE " + strings.Join(conditions.clauses, " AND ")
+	return offers, repo.db.GetPage(query, filter.Order, page, &offers, conditions.args...)
 }
 
+// Fetches the Offer, only when it belongs to the user if one is given, and only when it isn't hidden otherwise
 func (repo *OfferRepository) Get(uuid, username string) (model.Offer, error) {
 
 	var offer model.Offer
-	query := `SELECT * FROM offer WHERE uuid=$1`
+	query := `SELECT * FROM ` + offerWithReputation + ` WHERE uuid=$1`
 
-	if username != "" { // Add filter for username check
+	if username != "" { // Add filter for username check -> Owners still reach their hidden offers
 		query += ` AND username=$2`
 		return offer, repo.db.Get(query, &offer, uuid, username)
 	}
 
+	query += ` AND NOT hidden`
 	return offer, repo.db.Get(query, &offer, uuid)
 }
 
+// Fetches the Offer when the viewer can see it -> Public ones to anyone, the others only to their seller and moderators
+func (repo *OfferRepository) GetVisible(uuid string, viewer model.Viewer) (model.Offer, error) {
+
+	var offer model.Offer
+	query := `SELECT * FROM ` + offerWithReputation + ` WHERE uuid=$1 AND ((NOT hidden AND state = ANY($2)) OR username=$3 OR $4)`
+	return offer, repo.db.Get(query, &offer, uuid, pq.Array(model.PublicOfferStates), viewer.Username, viewer.Moderator)
+}
+
+// Updates the Offer, recording its new asking price when a listed one gets repriced and alerting the searches it now matches
+// Its seller and state are checked again under the lock, as it may have been reserved, sold or transferred since it was read
 func (repo *OfferRepository) Update(offer model.Offer) error {
 
-	query := `UPDATE offer SET name=$1, price=$2 WHERE uuid=$3`
-	return repo.db.ExecuteQuery(query, offer.GetName(), offer.GetPrice(), offer.GetId())
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		var previous model.Offer
+		if err := tx.Get(&previous, `SELECT * FROM offer WHERE uuid=$1 AND username=$2 FOR UPDATE`, offer.GetId(), offer.GetUsername()); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return middleware.NewError(http.StatusNotFound, "Offer not found with id: "+offer.GetId())
+			}
+			return err
+		}
+		if !previous.IsEditable() {
+			return middleware.NewError(http.StatusConflict, "Offer can't be updated once "+previous.GetState())
+		}
+
+		query := `UPDATE offer SET catalog_ref=$1, type=$2, name=$3, amount=$4, currency=$5,
+			condition=$6, sleeved=$7, missing_components=$8, description=$9, shipping=$10, pickup=$11, location=$12 WHERE uuid=$13`
+		if _, err := tx.Exec(query, offer.GetCatalogRef(), offer.GetType(), offer.GetName(), offer.GetAmount(), offer.GetCurrency(),
+			offer.Condition, offer.Sleeved, offer.MissingComponents, offer.Description, offer.Shipping, offer.Pickup, offer.Location, offer.GetId()); err != nil {
+			return err
+		}
+
+		if previous.GetState() != model.OfferActive {
+			return nil
+		}
+
+		repriced := previous.GetAmount() != offer.GetAmount() || previous.GetCurrency() != offer.GetCurrency() || previous.GetCatalogRef() != offer.GetCatalogRef()
+		if repriced {
+			if err := addPriceEvent(tx, offer, model.PriceChanged); err != nil {
+				return err
+			}
+		}
+		return addAlerts(tx, offer.GetId())
+	})
+}
+
+// Moves the Offer into its new state, as long as it is still in the previous one, recording when it gets listed or sold and alerting the searches a listed one matches
+func (repo *OfferRepository) SetState(offer model.Offer, previous string) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		query := `UPDATE offer SET state=$1, expires_at=$2 WHERE uuid=$3 AND state=$4`
+		result, err := tx.Exec(query, offer.GetState(), offer.GetExpiresAt(), offer.GetId(), previous)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil {
+			return err
+		} else if rows == 0 { // Changed meanwhile by another request
+			return middleware.NewError(http.StatusConflict, "Offer is no longer "+previous)
+		}
+
+		// Only active offers are negotiated, so the open requests are declined once it leaves
+		if previous == model.OfferActive && offer.GetState() != model.OfferActive {
+			if err := declineOpen(tx, []string{offer.GetId()}); err != nil {
+				return err
+			}
+		}
+
+		// Releasing the reservation closes the accepted request, so it is never taken for the sale of a later one
+		if previous == model.OfferReserved && offer.GetState() != model.OfferSold {
+			if err := releaseAccepted(tx, offer); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case offer.GetState() == model.OfferSold:
+			return addSale(tx, offer, previous == model.OfferReserved)
+		case offer.GetState() == model.OfferActive && previous != model.OfferReserved: // Released offers were listed all along
+			if err := addPriceEvent(tx, offer, model.PriceListed); err != nil {
+				return err
+			}
+			return addAlerts(tx, offer.GetId())
+		}
+		return nil
+	})
+}
+
+// Expires the active Offers past their expiry date, declining their open requests, returning how many were expired
+func (repo *OfferRepository) Expire() (int64, error) {
+
+	var expired []string
+	err := repo.db.Transaction(func(tx *sqlx.Tx) error {
+		query := `UPDATE offer SET state=$1 WHERE state=$2 AND expires_at <= now() RETURNING uuid`
+		if err := tx.Select(&expired, query, model.OfferExpired, model.OfferActive); err != nil {
+			return err
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+		return declineOpen(tx, expired)
+	})
+	return int64(len(expired)), err
 }
 
 func (repo *OfferRepository) Delete(uuid string) error {
@@ -59,3 +250,59 @@ func (repo *OfferRepository) Delete(uuid string) error {
 	query := `DELETE FROM offer WHERE uuid=$1`
 	return repo.db.ExecuteQuery(query, uuid)
 }
+
+// Function that records the amount the Offer is asked or sold for in the price history
+func addPriceEvent(tx *sqlx.Tx, offer model.Offer, event string) error {
+
+	query := `INSERT INTO price_event (offer_uuid, catalog_ref, event, amount, currency) VALUES ($1, $2, $3, $4, $5)`
+	_, err := tx.Exec(query, offer.GetId(), offer.GetCatalogRef(), event, offer.GetAmount(), offer.GetCurrency())
+	return err
+}
+
+// Function that declines the requests still under negotiation of the Offers, their seller recording it at the amount they were at
+func declineOpen(tx *sqlx.Tx, offerUuids []string) error {
+
+	query := `
+	WITH declined AS (
+		UPDATE purchase_request SET status=$1, updated_at=now()
+		FROM offer WHERE offer.uuid = purchase_request.offer_uuid AND purchase_request.offer_uuid = ANY($2) AND purchase_request.status = ANY($3)
+		RETURNING purchase_request.uuid, purchase_request.amount, offer.username
+	)
+	INSERT INTO negotiation_event (request_uuid, actor, action, amount) SELECT uuid, username, $4, amount FROM declined`
+	_, err := tx.Exec(query, model.RequestDeclined, pq.Array(offerUuids), pq.Array(model.OpenRequestStatuses), model.ActionDecline)
+	return err
+}
+
+// Function that moves the accepted request of the Offer into released, the seller recording it at its amount
+func releaseAccepted(tx *sqlx.Tx, offer model.Offer) error {
+
+	query := `
+	WITH released AS (
+		UPDATE purchase_request SET status=$1, updated_at=now()
+		WHERE offer_uuid=$2 AND status=$3
+		RETURNING uuid, amount
+	)
+	INSERT INTO negotiation_event (request_uuid, actor, action, amount) SELECT uuid, $4, $5, amount FROM released`
+	_, err := tx.Exec(query, model.RequestReleased, offer.GetId(), model.RequestAccepted, offer.GetUsername(), model.ActionRelease)
+	return err
+}
+
+// Function that records when the Offer was sold and, in the price history, at what amount
+// Reserved offers went to the buyer of the request accepted for the reservation, at its amount when there is one -> Released ones no longer count
+func addSale(tx *sqlx.Tx, offer model.Offer, reserved bool) error {
+
+	query := `
+	WITH accepted AS (
+		SELECT buyer, amount, currency FROM purchase_request
+		WHERE offer_uuid = $1 AND status = $2 AND $3::boolean
+		ORDER BY updated_at desc LIMIT 1
+	), sold AS (
+		UPDATE offer SET buyer = (SELECT buyer FROM accepted), sold_at = now() WHERE uuid = $1
+		RETURNING uuid, catalog_ref, amount, currency
+	)
+	INSERT INTO price_event (offer_uuid, catalog_ref, event, amount, currency)
+	SELECT sold.uuid, sold.catalog_ref, $4, COALESCE(accepted.amount, sold.amount), COALESCE(accepted.currency, sold.currency)
+	FROM sold LEFT JOIN accepted ON true`
+	_, err := tx.Exec(query, offer.GetId(), model.RequestAccepted, reserved, model.PriceSold)
+	return err
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"marketplace/database"
+	"marketplace/model"
+)
+
+type OfferImageRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewOfferImageRepository(instance *database.PostgresqlRepository) *OfferImageRepository {
+	return &OfferImageRepository{
+		db: instance,
+	}
+}
+
+func (repo *OfferImageRepository) Create(image *model.OfferImage) error {
+
+	query := `INSERT INTO offer_image (offer_uuid, width, height, size) VALUES ($1, $2, $3, $4) RETURNING *`
+	return repo.db.Get(query, image, image.GetOfferId(), image.Width, image.Height, image.Size)
+}
+
+// Fetches the images of the Offer, oldest first
+func (repo *OfferImageRepository) GetAll(offerUuid string) ([]model.OfferImage, error) {
+
+	images := []model.OfferImage{}
+	query := `SELECT * FROM offer_image WHERE offer_uuid=$1 ORDER BY created_at asc, uuid asc`
+	return images, repo.db.GetAll(query, &images, offerUuid)
+}
+
+func (repo *OfferImageRepository) Get(uuid, offerUuid string) (model.OfferImage, error) {
+
+	var image model.OfferImage
+	query := `SELECT * FROM offer_image WHERE uuid=$1 AND offer_uuid=$2`
+	return image, repo.db.Get(query, &image, uuid, offerUuid)
+}
+
+func (repo *OfferImageRepository) Count(offerUuid string) (int, error) {
+
+	var count int
+	query := `SELECT COUNT(*) FROM offer_image WHERE offer_uuid=$1`
+	return count, repo.db.Get(query, &count, offerUuid)
+}
+
+func (repo *OfferImageRepository) Delete(uuid string) error {
+
+	query := `DELETE FROM offer_image WHERE uuid=$1`
+	return repo.db.ExecuteQuery(query, uuid)
+}
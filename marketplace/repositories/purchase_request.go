@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"errors"
+	"net/http"
+
+	"marketplace/database"
+	"marketplace/middleware"
+	"marketplace/model"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505" // Postgres error code of a unique constraint violation
+
+type PurchaseRequestRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewPurchaseRequestRepository(instance *database.PostgresqlRepository) *PurchaseRequestRepository {
+	return &PurchaseRequestRepository{
+		db: instance,
+	}
+}
+
+// Creates the PurchaseRequest together with the event that opens its negotiation, as long as its Offer is active
+// The currency, and the amount when none was asked, are the ones of the Offer under the lock, as the seller may have just changed them
+func (repo *PurchaseRequestRepository) Create(request *model.PurchaseRequest, event model.NegotiationEvent) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		offer, err := lockActiveOffer(tx, request.GetOfferId())
+		if err != nil {
+			return err
+		}
+		if request.GetAmount() == 0 {
+			request.SetAmount(offer.GetAmount())
+			event.Amount = offer.GetAmount()
+		}
+		request.SetCurrency(offer.GetCurrency())
+
+		query := `INSERT INTO purchase_request (offer_uuid, buyer, status, amount, currency) VALUES ($1, $2, $3, $4, $5) RETURNING *`
+		if err := tx.Get(request, query, request.GetOfferId(), request.GetBuyer(), request.GetStatus(), request.GetAmount(), request.GetCurrency()); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return middleware.NewError(http.StatusConflict, "There already is an open request of yours on this offer")
+			}
+			return err
+		}
+
+		if err := addEvent(tx, request.GetId(), &event); err != nil {
+			return err
+		}
+		request.History = []model.NegotiationEvent{event}
+		return nil
+	})
+}
+
+// Fetches the requests of the Offer, only the buyer's when one is given, each with its negotiation history
+func (repo *PurchaseRequestRepository) GetAll(offerUuid, buyer string) ([]model.PurchaseRequest, error) {
+
+	requests := []model.PurchaseRequest{}
+	query := `SELECT * FROM purchase_request WHERE offer_uuid=$1`
+	args := []interface{}{offerUuid}
+	if buyer != "" {
+		query += ` AND buyer=$2`
+		args = append(args, buyer)
+	}
+	query += ` ORDER BY created_at asc, uuid asc`
+
+	if err := repo.db.GetAll(query, &requests, args...); err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return requests, nil
+	}
+
+	ids := make([]string, len(requests))
+	for i, request := range requests {
+		ids[i] = request.GetId()
+	}
+
+	var events []model.NegotiationEvent
+	if err := repo.db.GetAll(`SELECT * FROM negotiation_event WHERE request_uuid = ANY($1) ORDER BY id asc`, &events, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	histories := make(map[string][]model.NegotiationEvent, len(requests))
+	for _, event := range events {
+		histories[event.RequestUuid] = append(histories[event.RequestUuid], event)
+	}
+	for i := range requests {
+		requests[i].History = histories[requests[i].GetId()]
+	}
+	return requests, nil
+}
+
+func (repo *PurchaseRequestRepository) Get(uuid, offerUuid string) (model.PurchaseRequest, error) {
+
+	var request model.PurchaseRequest
+	query := `SELECT * FROM purchase_request WHERE uuid=$1 AND offer_uuid=$2`
+	return request, repo.db.Get(query, &request, uuid, offerUuid)
+}
+
+// Moves the request into its new status and amount, as long as it is still in the previous status and its Offer active, recording the event
+func (repo *PurchaseRequestRepository) Negotiate(request model.PurchaseRequest, previous string, event model.NegotiationEvent) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		if _, err := lockActiveOffer(tx, request.GetOfferId()); err != nil {
+			return err
+		}
+		if err := setStatus(tx, request, previous); err != nil {
+			return err
+		}
+		return addEvent(tx, request.GetId(), &event)
+	})
+}
+
+// Accepts the request, reserving its Offer and declining every other open request of it, all at once
+func (repo *PurchaseRequestRepository) Accept(request model.PurchaseRequest, previous string, event model.NegotiationEvent) error {
+
+	return repo.db.Transaction(func(tx *sqlx.Tx) error {
+		result, err := tx.Exec(`UPDATE offer SET state=$1 WHERE uuid=$2 AND state=$3`, model.OfferReserved, request.GetOfferId(), model.OfferActive)
+		if err != nil {
+			return err
+		}
+		if rows, err := result.RowsAffected(); err != nil {
+			return err
+		} else if rows == 0 {
+			return middleware.NewError(http.StatusConflict, "Offer is no longer "+model.OfferActive)
+		}
+
+		if err := setStatus(tx, request, previous); err != nil {
+			return err
+		}
+		if err := addEvent(tx, request.GetId(), &event); err != nil {
+			return err
+		}
+
+		// The seller declines the others, at the amount they were at
+		query := `
+		WITH declined AS (
+			UPDATE purchase_request SET status=$1, updated_at=now()
+			WHERE offer_uuid=$2 AND uuid<>$3 AND status = ANY($4)
+			RETURNING uuid, amount
+		)
+		INSERT INTO negotiation_event (request_uuid, actor, action, amount) SELECT uuid, $5, $6, amount FROM declined`
+		_, err = tx.Exec(query, model.RequestDeclined, request.GetOfferId(), request.GetId(), pq.Array(model.OpenRequestStatuses), event.Actor, model.ActionDecline)
+		return err
+	})
+}
+
+// Function that checks the Offer is active, keeping it so until the transaction ends -> Leaving active waits for the negotiation, then declines it
+func lockActiveOffer(tx *sqlx.Tx, offerUuid string) (model.Offer, error) {
+
+	var offer model.Offer
+	if err := tx.Get(&offer, `SELECT * FROM offer WHERE uuid=$1 FOR SHARE`, offerUuid); err != nil {
+		return model.Offer{}, err
+	}
+	if offer.GetState() != model.OfferActive {
+		return model.Offer{}, middleware.NewError(http.StatusConflict, "Offer is no longer "+model.OfferActive+", it is "+offer.GetState())
+	}
+	return offer, nil
+}
+
+// Function that updates the status and amount of the request, failing with a conflict when it changed meanwhile
+func setStatus(tx *sqlx.Tx, request model.PurchaseRequest, previous string) error {
+
+	query := `UPDATE purchase_request SET status=$1, amount=$2, updated_at=now() WHERE uuid=$3 AND status=$4`
+	result, err := tx.Exec(query, request.GetStatus(), request.GetAmount(), request.GetId(), previous)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return middleware.NewError(http.StatusConflict, "Request is no longer "+previous)
+	}
+	return nil
+}
+
+// Function that records a step of the negotiation of the request, filling the event with the stored one
+func addEvent(tx *sqlx.Tx, requestUuid string, event *model.NegotiationEvent) error {
+
+	query := `INSERT INTO negotiation_event (request_uuid, actor, action, amount) VALUES ($1, $2, $3, $4) RETURNING *`
+	return tx.Get(event, query, requestUuid, event.Actor, event.Action, event.Amount)
+}
@@ -6,14 +6,38 @@ import (
 
 // Repositories contains all the repo structs
 type Repositories struct {
-	OfferRepository *OfferRepository
+	OfferRepository           *OfferRepository
+	PurchaseRequestRepository *PurchaseRequestRepository
+	ExchangeRateRepository    *ExchangeRateRepository
+	OfferImageRepository      *OfferImageRepository
+	MarketRepository          *MarketRepository
+	SavedSearchRepository     *SavedSearchRepository
+	AlertRepository           *AlertRepository
+	ReputationRepository      *ReputationRepository
+	ModerationRepository      *ModerationRepository
 }
 
 // InitRepositories should be called in main.go
 func InitRepositories(db *database.PostgresqlRepository) *Repositories {
 	offerRepository := NewOfferRepository(db)
+	purchaseRequestRepository := NewPurchaseRequestRepository(db)
+	exchangeRateRepository := NewExchangeRateRepository(db)
+	offerImageRepository := NewOfferImageRepository(db)
+	marketRepository := NewMarketRepository(db)
+	savedSearchRepository := NewSavedSearchRepository(db)
+	alertRepository := NewAlertRepository(db)
+	reputationRepository := NewReputationRepository(db)
+	moderationRepository := NewModerationRepository(db)
 
 	return &Repositories{
-		OfferRepository: offerRepository,
+		OfferRepository:           offerRepository,
+		PurchaseRequestRepository: purchaseRequestRepository,
+		ExchangeRateRepository:    exchangeRateRepository,
+		OfferImageRepository:      offerImageRepository,
+		MarketRepository:          marketRepository,
+		SavedSearchRepository:     savedSearchRepository,
+		AlertRepository:           alertRepository,
+		ReputationRepository:      reputationRepository,
+		ModerationRepository:      moderationRepository,
 	}
 }
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"marketplace/database"
+	"marketplace/model"
+
+	"github.com/lib/pq"
+)
+
+type ReputationRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewReputationRepository(instance *database.PostgresqlRepository) *ReputationRepository {
+	return &ReputationRepository{
+		db: instance,
+	}
+}
+
+// Fetches the username of every seller, in order
+func (repo *ReputationRepository) GetSellers() ([]string, error) {
+
+	sellers := []string{}
+	query := `SELECT DISTINCT username FROM offer ORDER BY username asc`
+	return sellers, repo.db.GetAll(query, &sellers)
+}
+
+// Stores the reputations, replacing the ones of the same sellers
+func (repo *ReputationRepository) Replace(reputations []model.SellerReputation) error {
+
+	usernames := make([]string, len(reputations))
+	counts := make([]int64, len(reputations))
+	means := make([]*float64, len(reputations))
+	for i, reputation := range reputations {
+		usernames[i], counts[i], means[i] = reputation.Username, reputation.Count, reputation.Mean
+	}
+
+	query := `INSERT INTO seller_reputation (username, count, mean)
+		SELECT * FROM unnest($1::text[], $2::bigint[], $3::float8[])
+		ON CONFLICT (username) DO UPDATE SET count = excluded.count, mean = excluded.mean, updated_at = now()`
+	return repo.db.ExecuteQuery(query, pq.Array(usernames), pq.Array(counts), pq.Array(means))
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"net/http"
+
+	"marketplace/database"
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+type SavedSearchRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewSavedSearchRepository(instance *database.PostgresqlRepository) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		db: instance,
+	}
+}
+
+func (repo *SavedSearchRepository) Create(search *model.SavedSearch) error {
+
+	query := `INSERT INTO saved_search (username, name, filter, max_amount, currency, catalog_ref, type, conditions, words)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *`
+	return repo.db.Get(query, search, search.GetUsername(), search.Name, search.Filter, search.MaxAmount, search.Currency,
+		search.CatalogRef, search.Type, search.Conditions, search.Words)
+}
+
+// Fetches the saved searches of the user, oldest first
+func (repo *SavedSearchRepository) GetAll(username string) ([]model.SavedSearch, error) {
+
+	searches := []model.SavedSearch{}
+	query := `SELECT * FROM saved_search WHERE username=$1 ORDER BY created_at asc, uuid asc`
+	return searches, repo.db.GetAll(query, &searches, username)
+}
+
+func (repo *SavedSearchRepository) Count(username string) (int, error) {
+
+	var count int
+	query := `SELECT COUNT(*) FROM saved_search WHERE username=$1`
+	return count, repo.db.Get(query, &count, username)
+}
+
+// Deletes the saved search of the user, its alerts are kept
+func (repo *SavedSearchRepository) Delete(uuid, username string) error {
+
+	query := `DELETE FROM saved_search WHERE uuid=$1 AND username=$2`
+	rows, err := repo.db.ExecuteUpdate(query, uuid, username)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return middleware.NewError(http.StatusNotFound, "Error - Record not found")
+	}
+	return nil
+}
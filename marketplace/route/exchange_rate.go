@@ -0,0 +1,29 @@
+package route
+
+import (
+	"marketplace/controllers"
+	"marketplace/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddExchangeRateRouter(router chi.Router, oauthKey string, admins []string, controller *controllers.ExchangeRateController) {
+	// Admin layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.AdminOnly(admins))
+
+			r.Put("/api/exchange-rate", controller.Replace)
+		},
+	)
+
+	// Public layer
+	router.Group(
+		func(r chi.Router) {
+			r.Get("/api/exchange-rate", controller.GetAll)
+		},
+	)
+}
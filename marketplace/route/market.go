@@ -0,0 +1,16 @@
+package route
+
+import (
+	"marketplace/controllers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func AddMarketRouter(router chi.Router, controller *controllers.MarketController) {
+	// Public layer
+	router.Group(
+		func(r chi.Router) {
+			r.Get("/api/market/boardgame/{id}/stats", controller.GetStats)
+		},
+	)
+}
@@ -0,0 +1,50 @@
+package route
+
+import (
+	"marketplace/controllers"
+	"marketplace/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddModerationRouter(router chi.Router, oauthKey string, admins []string, controller *controllers.ModerationController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Post("/api/offer/{id}/report", controller.Report)
+		},
+	)
+
+	// Moderator layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.RequireRole(admins, middleware.RoleModerator, middleware.RoleAdmin))
+
+			r.Get("/api/moderation/queue", controller.GetQueue)
+			r.Get("/api/moderation/audit", controller.GetActions)
+			r.Get("/api/moderation/offer/{id}/reports", controller.GetReports)
+
+			r.Post("/api/moderation/offer/{id}/hide", controller.Hide)
+			r.Post("/api/moderation/offer/{id}/restore", controller.Restore)
+			r.Post("/api/moderation/offer/{id}/dismiss", controller.Dismiss)
+		},
+	)
+
+	// Admin layer -> Taking offers away from their owners is left to admins
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.RequireRole(admins, middleware.RoleAdmin))
+
+			r.Post("/api/moderation/offer/{id}/transfer", controller.Transfer)
+			r.Post("/api/moderation/offer/{id}/delete", controller.Delete)
+		},
+	)
+}
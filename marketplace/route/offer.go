@@ -2,27 +2,48 @@ package route
 
 import (
 	"marketplace/controllers"
+	"marketplace/middleware"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/oauth"
 )
 
-func AddOfferRouter(router chi.Router, oauthKey string, controller *controllers.OfferController) {
+func AddOfferRouter(router chi.Router, oauthKey string, admins []string, controller *controllers.OfferController) {
 	// Protected layer
 	router.Group(
 		func(r chi.Router) {
 			// Use the Bearer Authentication middleware
 			r.Use(oauth.Authorize(oauthKey, nil))
 
-			r.Post("/api/offer", controller.Create)
 			r.Patch("/api/offer/{id}", controller.Update)
 			r.Delete("/api/offer/{id}", controller.Delete)
+
+			// Lifecycle
+			r.Post("/api/offer/{id}/reserve", controller.Reserve)
+			r.Post("/api/offer/{id}/release", controller.Release)
+			r.Post("/api/offer/{id}/sell", controller.Sell)
+			r.Post("/api/offer/{id}/withdraw", controller.Withdraw)
+			r.Get("/api/offer/{id}/sale", controller.GetSale)
+		},
+	)
+
+	// Verified layer -> Only users who verified their email list offers
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.VerifiedOnly)
+
+			r.Post("/api/offer", controller.Create)
+			r.Post("/api/offer/{id}/publish", controller.Publish)
 		},
 	)
 
-	// Public layer
+	// Public layer -> Tokens are optional, letting sellers and moderators see offers others can't
 	router.Group(
 		func(r chi.Router) {
+			r.Use(middleware.Identify(oauthKey, admins))
+
 			r.Get("/api/offer", controller.GetAll)
 			r.Get("/api/offer/{id}", controller.Get)
 		},
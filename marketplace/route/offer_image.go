@@ -0,0 +1,33 @@
+package route
+
+import (
+	"marketplace/controllers"
+	"marketplace/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddOfferImageRouter(router chi.Router, oauthKey string, admins []string, controller *controllers.OfferImageController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Post("/api/offer/{id}/images", controller.Upload)
+			r.Delete("/api/offer/{id}/images/{imageId}", controller.Delete)
+		},
+	)
+
+	// Public layer -> Photos of offers others can't see are only served to their seller and moderators
+	router.Group(
+		func(r chi.Router) {
+			r.Use(middleware.Identify(oauthKey, admins))
+
+			r.Get("/api/offer/{id}/images", controller.GetAll)
+			r.Get("/api/offer/{id}/images/{imageId}", controller.Get)
+			r.Get("/api/offer/{id}/images/{imageId}/thumbnail", controller.GetThumbnail)
+		},
+	)
+}
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"marketplace/controllers"
+	"marketplace/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddPurchaseRequestRouter(router chi.Router, oauthKey string, controller *controllers.PurchaseRequestController) {
+	// Protected layer -> Buyers and sellers only see their own negotiations
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Get("/api/offer/{id}/requests", controller.GetAll)
+			r.Post("/api/offer/{id}/requests/{requestId}/counter", controller.Counter)
+			r.Post("/api/offer/{id}/requests/{requestId}/accept", controller.Accept)
+			r.Post("/api/offer/{id}/requests/{requestId}/reject", controller.Reject)
+		},
+	)
+
+	// Verified layer -> Only users who verified their email make purchase requests
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.VerifiedOnly)
+
+			r.Post("/api/offer/{id}/requests", controller.Create)
+		},
+	)
+}
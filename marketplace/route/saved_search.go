@@ -0,0 +1,26 @@
+package route
+
+import (
+	"marketplace/controllers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddSavedSearchRouter(router chi.Router, oauthKey string, searchController *controllers.SavedSearchController, alertController *controllers.AlertController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Post("/api/saved-searches", searchController.Create)
+			r.Get("/api/saved-searches", searchController.GetAll)
+			r.Delete("/api/saved-searches/{id}", searchController.Delete)
+
+			// Inbox of the offers that matched them
+			r.Get("/api/alerts", alertController.GetAll)
+			r.Post("/api/alerts/read", alertController.MarkRead)
+		},
+	)
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"marketplace/model"
+	"marketplace/repositories"
+)
+
+type alertRepository interface {
+	GetAll(username string, after int64, page *model.Page) ([]model.Alert, error)
+	MarkRead(username string, lastId int64) error
+}
+
+// AlertService is the inbox of the offers that matched the saved searches of the users
+type AlertService struct {
+	repo alertRepository
+}
+
+func InitAlertService(alertRepository *repositories.AlertRepository) *AlertService {
+	return &AlertService{
+		repo: alertRepository,
+	}
+}
+
+// Method that fetches the alerts of the user added after the given id, which is how the inbox is polled
+func (svc *AlertService) GetAll(username string, after int64, page *model.Page) ([]model.Alert, error) {
+
+	return svc.repo.GetAll(username, after, page)
+}
+
+// Method that marks the alerts of the user read up to the given one
+func (svc *AlertService) MarkRead(username string, lastId int64) error {
+	return svc.repo.MarkRead(username, lastId)
+}
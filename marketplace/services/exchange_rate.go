@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/repositories"
+)
+
+const baseRate = "1" // The base currency buys exactly one of itself
+
+// Positive decimals only, without exponents, so they reach the database exactly as written
+var decimalRate = regexp.MustCompile(`^[0-9]{1,12}(\.[0-9]{1,12})?$`)
+
+type exchangeRateRepository interface {
+	GetAll() ([]model.ExchangeRate, error)
+	Get(currency string) (model.ExchangeRate, error)
+	Replace(rates []model.ExchangeRate) error
+}
+
+// ExchangeRateService keeps the exchange-rate table offers are converted with
+type ExchangeRateService struct {
+	repo exchangeRateRepository
+}
+
+func InitExchangeRateService(exchangeRateRepository *repositories.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{
+		repo: exchangeRateRepository,
+	}
+}
+
+func (svc *ExchangeRateService) GetAll() ([]model.ExchangeRate, error) {
+
+	return svc.repo.GetAll()
+}
+
+// Method that replaces every rate by the ones of the input, which are relative to its base currency
+func (svc *ExchangeRateService) Replace(input *model.ExchangeRatesInput) ([]model.ExchangeRate, error) {
+
+	if _, ok := model.GetMinorUnit(input.Base); !ok {
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Currency not supported: "+input.Base)
+	}
+
+	rates := []model.ExchangeRate{model.NewExchangeRate(input.Base, baseRate)}
+	for currency, value := range input.Rates {
+		rate := value.String()
+		if currency == input.Base {
+			continue // Always 1
+		}
+
+		if _, ok := model.GetMinorUnit(currency); !ok {
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Currency not supported: "+currency)
+		}
+		if !decimalRate.MatchString(rate) || isZero(rate) {
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed rate of "+currency+", should be a positive decimal (E.g 1.0834)")
+		}
+		rates = append(rates, model.NewExchangeRate(currency, rate))
+	}
+
+	if err := svc.repo.Replace(rates); err != nil {
+		return nil, err
+	}
+	return svc.repo.GetAll()
+}
+
+// Function that checks there is a rate of the currency, which converting amounts into it needs
+func checkRate(rates exchangeRateRepository, currency string) error {
+
+	if _, err := rates.Get(currency); err != nil {
+		var mr *middleware.MalformedRequest
+		if errors.As(err, &mr) && mr.GetStatus() == http.StatusNotFound {
+			return middleware.NewError(http.StatusUnprocessableEntity, "No exchange rate for currency: "+currency)
+		}
+		return err
+	}
+	return nil
+}
+
+// Function that tells if the decimal is zero, however many zeros it is written with
+func isZero(decimal string) bool {
+	for _, digit := range decimal {
+		if digit != '0' && digit != '.' {
+			return false
+		}
+	}
+	return true
+}
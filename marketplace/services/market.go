@@ -0,0 +1,43 @@
+package services
+
+import (
+	"marketplace/model"
+	"marketplace/repositories"
+)
+
+type marketRepository interface {
+	GetStats(query *model.MarketQuery) (model.MarketStats, error)
+}
+
+// MarketService estimates the market value of the catalog boardgames, so sellers can price sensibly
+type MarketService struct {
+	repo  marketRepository
+	rates exchangeRateRepository
+}
+
+func InitMarketService(marketRepository *repositories.MarketRepository, exchangeRateRepository *repositories.ExchangeRateRepository) *MarketService {
+	return &MarketService{
+		repo:  marketRepository,
+		rates: exchangeRateRepository,
+	}
+}
+
+// Method that fetches the stats of the boardgame, with every amount converted into the currency of the query
+func (svc *MarketService) GetStats(query *model.MarketQuery) (model.MarketStats, error) {
+
+	if err := checkRate(svc.rates, query.Currency); err != nil {
+		return model.MarketStats{}, err
+	}
+
+	stats, err := svc.repo.GetStats(query)
+	if err != nil {
+		return model.MarketStats{}, err
+	}
+
+	stats.CatalogRef = query.CatalogRef
+	stats.Currency = query.Currency
+	if stats.Trend == nil {
+		stats.Trend = []model.PriceTrendBucket{}
+	}
+	return stats, nil
+}
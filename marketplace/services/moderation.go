@@ -0,0 +1,127 @@
+package services
+
+import (
+	"net/http"
+
+	"marketplace/clients"
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/repositories"
+	"marketplace/utils"
+)
+
+type moderationRepository interface {
+	CreateReport(report *model.OfferReport) error
+	GetReports(offerUuid string) ([]model.OfferReport, error)
+	GetQueue(minReports int, page *model.Page) ([]model.ModerationQueueItem, error)
+	GetActions(offerUuid string, page *model.Page) ([]model.ModerationAction, error)
+	Moderate(action *model.ModerationAction) error
+}
+
+type userClient interface {
+	Exists(username, token string) (bool, error)
+}
+
+// ModerationService takes the reports of the users and the actions of the moderators on offers, auditing every action
+type ModerationService struct {
+	repo   moderationRepository
+	offers offerRepository
+	images offerImages
+	users  userClient
+}
+
+func InitModerationService(moderationRepository *repositories.ModerationRepository, offerRepository *repositories.OfferRepository, offerImageService *OfferImageService, userClient *clients.UserClient) *ModerationService {
+	return &ModerationService{
+		repo:   moderationRepository,
+		offers: offerRepository,
+		images: offerImageService,
+		users:  userClient,
+	}
+}
+
+// Method that reports the Offer, which must be one others can see
+func (svc *ModerationService) Report(offerUuid, reason, username string) (model.OfferReport, error) {
+
+	offer, err := svc.offers.Get(offerUuid, "")
+	if err != nil {
+		return model.OfferReport{}, err
+	}
+	if offer.GetUsername() == username {
+		return model.OfferReport{}, middleware.NewError(http.StatusUnprocessableEntity, "Users cannot report their own offers")
+	}
+
+	report := model.NewOfferReport(offer.GetId(), username, reason)
+	if err := svc.repo.CreateReport(report); err != nil {
+		return model.OfferReport{}, err
+	}
+	return *report, nil
+}
+
+func (svc *ModerationService) GetReports(offerUuid string) ([]model.OfferReport, error) {
+
+	return svc.repo.GetReports(offerUuid)
+}
+
+func (svc *ModerationService) GetQueue(minReports int, page *model.Page) ([]model.ModerationQueueItem, error) {
+
+	return svc.repo.GetQueue(minReports, page)
+}
+
+func (svc *ModerationService) GetActions(offerUuid string, page *model.Page) ([]model.ModerationAction, error) {
+
+	return svc.repo.GetActions(offerUuid, page)
+}
+
+func (svc *ModerationService) Hide(offerUuid, reason, moderator string) (model.ModerationAction, error) {
+
+	return svc.moderate(model.NewModerationAction(offerUuid, moderator, model.ModerationHide, reason))
+}
+
+func (svc *ModerationService) Restore(offerUuid, reason, moderator string) (model.ModerationAction, error) {
+
+	return svc.moderate(model.NewModerationAction(offerUuid, moderator, model.ModerationRestore, reason))
+}
+
+func (svc *ModerationService) Dismiss(offerUuid, reason, moderator string) (model.ModerationAction, error) {
+
+	return svc.moderate(model.NewModerationAction(offerUuid, moderator, model.ModerationDismiss, reason))
+}
+
+// Method that gives the Offer to another user, along with its purchase requests -> The user is looked up with the token of the moderator
+func (svc *ModerationService) Transfer(offerUuid string, input *model.TransferInput, moderator, token string) (model.ModerationAction, error) {
+
+	if !utils.IsUsername(input.Username) {
+		return model.ModerationAction{}, middleware.NewError(http.StatusUnprocessableEntity, "Malformed username, should be 3 to 30 letters, digits, dots, dashes or underscores")
+	}
+	exists, err := svc.users.Exists(input.Username, token)
+	if err != nil {
+		return model.ModerationAction{}, err
+	}
+	if !exists {
+		return model.ModerationAction{}, middleware.NewError(http.StatusUnprocessableEntity, "User not found with username: "+input.Username)
+	}
+
+	action := model.NewModerationAction(offerUuid, moderator, model.ModerationTransfer, input.Reason)
+	action.NewOwner = &input.Username
+	return svc.moderate(action)
+}
+
+// Method that deletes the Offer whoever owns it, keeping its audit
+func (svc *ModerationService) Delete(offerUuid, reason, moderator string) (model.ModerationAction, error) {
+
+	// Its images go away with it, their files once the moderation is committed so a failed one keeps them
+	var action model.ModerationAction
+	err := svc.images.DeleteWithOffer(offerUuid, func() (err error) {
+		action, err = svc.moderate(model.NewModerationAction(offerUuid, moderator, model.ModerationDelete, reason))
+		return err
+	})
+	return action, err
+}
+
+func (svc *ModerationService) moderate(action *model.ModerationAction) (model.ModerationAction, error) {
+
+	if err := svc.repo.Moderate(action); err != nil {
+		return model.ModerationAction{}, err
+	}
+	return *action, nil
+}
@@ -1,27 +1,54 @@
 package services
 
 import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace/clients"
+	"marketplace/middleware"
 	"marketplace/model"
 	"marketplace/repositories"
 )
 
 type offerRepository interface {
 	Create(offer *model.Offer) error
-	ReadAll() ([]model.Offer, error)
+	ReadAll(filter *model.OfferFilter, page *model.Page) ([]model.Offer, error)
 	Update(offer model.Offer) error
 	Get(id, username string) (model.Offer, error)
+	GetVisible(id string, viewer model.Viewer) (model.Offer, error)
+	SetState(offer model.Offer, previous string) error
+	Expire() (int64, error)
 	Delete(id string) error
 }
 
+type offerImages interface {
+	DeleteWithOffer(offerUuid string, deleteOffer func() error) error
+}
+
+type catalogClient interface {
+	GetBoardgame(id uint) (model.CatalogBoardgame, error)
+}
+
 // Controller contains the service, which contains database-related logic, as an injectable dependency, allowing us to decouple business logic from db logic.
 type OfferService struct {
-	repo offerRepository
+	repo    offerRepository
+	rates   exchangeRateRepository
+	images  offerImages
+	catalog catalogClient
+	ttl     time.Duration // How long an Offer stays active before expiring
 }
 
 // InitController initializes the boargame and the associations controller.
-func InitOfferService(offerRepository *repositories.OfferRepository) *OfferService {
+func InitOfferService(offerRepository *repositories.OfferRepository, exchangeRateRepository *repositories.ExchangeRateRepository, offerImageService *OfferImageService, catalogClient *clients.CatalogClient, ttl time.Duration) *OfferService {
 	return &OfferService{
-		repo: offerRepository,
+		repo:    offerRepository,
+		rates:   exchangeRateRepository,
+		images:  offerImageService,
+		catalog: catalogClient,
+		ttl:     ttl,
 	}
 }
 
@@ -29,17 +56,51 @@ func (svc *OfferService) Create(offer *model.Offer, user string) error {
 
 	offer.SetUsername(user)
 
+	if err := checkCurrency(offer.GetCurrency()); err != nil {
+		return err
+	}
+	if err := checkDetails(&offer.OfferDetails); err != nil {
+		return err
+	}
+
+	if offer.GetState() == "" {
+		offer.SetState(model.OfferActive)
+	}
+	if offer.GetState() == model.OfferActive {
+		svc.setExpiry(offer)
+	}
+
+	if err := svc.checkCatalog(offer); err != nil {
+		return err
+	}
+
 	return svc.repo.Create(offer)
 }
 
-func (svc *OfferService) ReadAll() ([]model.Offer, error) {
+func (svc *OfferService) ReadAll(filter *model.OfferFilter, page *model.Page) ([]model.Offer, error) {
 
-	return svc.repo.ReadAll()
+	if filter.Currency == "" {
+		return svc.repo.ReadAll(filter, page)
+	}
+
+	if err := checkRate(svc.rates, filter.Currency); err != nil {
+		return nil, err
+	}
+
+	offers, err := svc.repo.ReadAll(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	for i := range offers {
+		offers[i].SetConvertedCurrency(filter.Currency)
+	}
+	return offers, nil
 }
 
-func (svc *OfferService) Get(uuid string) (model.Offer, error) {
+// Method that fetches the Offer, as long as the viewer can see it
+func (svc *OfferService) Get(uuid string, viewer model.Viewer) (model.Offer, error) {
 
-	return svc.repo.Get(uuid, "")
+	return svc.repo.GetVisible(uuid, viewer)
 }
 
 func (svc *OfferService) Update(input *model.OfferUpdate, uuid, username string) (model.Offer, error) {
@@ -50,11 +111,86 @@ func (svc *OfferService) Update(input *model.OfferUpdate, uuid, username string)
 		return model.Offer{}, err
 	}
 
+	if !offer.IsEditable() {
+		return model.Offer{}, middleware.NewError(http.StatusConflict, "Offer can't be updated once "+offer.GetState())
+	}
+
+	if err := checkCurrency(input.GetCurrency()); err != nil {
+		return model.Offer{}, err
+	}
+	if err := checkDetails(&input.OfferDetails); err != nil {
+		return model.Offer{}, err
+	}
+
 	offer.UpdateOffer(input)
+	offer.SetType("") // The type follows the new catalog entry
+
+	if err := svc.checkCatalog(&offer); err != nil {
+		return model.Offer{}, err
+	}
 
 	return offer, svc.repo.Update(offer)
 }
 
+// Method that moves the Offer of the user into a new state, as long as its lifecycle allows it
+func (svc *OfferService) Transition(uuid, username, state string) (model.Offer, error) {
+
+	offer, err := svc.repo.Get(uuid, username)
+	if err != nil {
+		return model.Offer{}, err
+	}
+
+	previous := offer.GetState()
+	if !model.CanTransition(previous, state) {
+		return model.Offer{}, middleware.NewError(http.StatusConflict, "Offer can't go from "+previous+" to "+state)
+	}
+
+	offer.SetState(state)
+	if state == model.OfferActive { // Every time it is listed again, it gets the full time to sell
+		svc.setExpiry(&offer)
+	}
+
+	return offer, svc.repo.SetState(offer, previous)
+}
+
+// Method that fetches the proof the Offer was sold, which only its seller and buyer can see (E.g for the buyer to rate the seller)
+func (svc *OfferService) GetSale(uuid, username string) (model.Sale, error) {
+
+	offer, err := svc.repo.Get(uuid, "")
+	if err != nil {
+		return model.Sale{}, err
+	}
+
+	if offer.GetState() != model.OfferSold {
+		return model.Sale{}, middleware.NewError(http.StatusNotFound, "Offer has not been sold")
+	}
+
+	sale := model.NewSale(&offer)
+	if username != sale.Seller && username != sale.Buyer {
+		log.Println("Error - User " + username + " is neither the seller nor the buyer of offer " + uuid)
+		return model.Sale{}, middleware.NewError(http.StatusForbidden, "Only the seller and the buyer can see the sale")
+	}
+	return sale, nil
+}
+
+// Method that expires stale offers every interval, until the process stops -> Should run on its own goroutine
+func (svc *OfferService) Sweep(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := svc.repo.Expire()
+		if err != nil {
+			log.Println("Error - Failed to expire stale offers: " + err.Error())
+			continue
+		}
+		if expired > 0 {
+			log.Println("Expired stale offers: " + strconv.FormatInt(expired, 10))
+		}
+	}
+}
+
 func (svc *OfferService) Delete(id, username string) error {
 
 	// Get Offer by id
@@ -63,5 +199,53 @@ func (svc *OfferService) Delete(id, username string) error {
 		return err
 	}
 
-	return svc.repo.Delete(offer.GetId())
+	// Its images go away with it, their files once the Offer is gone so a failed delete keeps them
+	return svc.images.DeleteWithOffer(offer.GetId(), func() error {
+		return svc.repo.Delete(offer.GetId())
+	})
+}
+
+// Method that sets when the Offer expires, counting from now
+func (svc *OfferService) setExpiry(offer *model.Offer) {
+
+	expiresAt := time.Now().Add(svc.ttl)
+	offer.SetExpiresAt(&expiresAt)
+}
+
+// Function that checks the currency has known minor units, so its amounts can be converted
+func checkCurrency(currency string) error {
+
+	if _, ok := model.GetMinorUnit(currency); !ok {
+		return middleware.NewError(http.StatusUnprocessableEntity, "Currency not supported: "+currency)
+	}
+	return nil
+}
+
+// Function that checks the Offer reaches the buyer somehow, and that pickups say where
+func checkDetails(details *model.OfferDetails) error {
+
+	if !details.HasDelivery() {
+		return middleware.NewError(http.StatusUnprocessableEntity, "Offer should be shipped, picked up or both")
+	}
+	if details.NeedsLocation() {
+		return middleware.NewError(http.StatusUnprocessableEntity, "Offers that can be picked up need a location")
+	}
+	return nil
+}
+
+// Method that checks the catalog entry of the Offer exists and matches its type, setting the type when missing
+func (svc *OfferService) checkCatalog(offer *model.Offer) error {
+
+	boardgame, err := svc.catalog.GetBoardgame(offer.GetCatalogRef())
+	if err != nil {
+		return err
+	}
+
+	offerType := boardgame.GetOfferType()
+	if offer.GetType() != "" && !strings.EqualFold(offer.GetType(), offerType) {
+		return middleware.NewError(http.StatusUnprocessableEntity, "Offer type does not match the catalog, which has a "+offerType)
+	}
+
+	offer.SetType(offerType)
+	return nil
 }
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/repositories"
+	"marketplace/storage"
+	"marketplace/utils"
+)
+
+const maxOfferImages = 8 // Most photos an Offer can have
+
+type offerImageRepository interface {
+	Create(image *model.OfferImage) error
+	GetAll(offerUuid string) ([]model.OfferImage, error)
+	Get(uuid, offerUuid string) (model.OfferImage, error)
+	Count(offerUuid string) (int, error)
+	Delete(uuid string) error
+}
+
+// OfferImageService keeps the photos of offers, with their thumbnails, in the image storage
+type OfferImageService struct {
+	repo    offerImageRepository
+	offers  offerRepository
+	storage storage.Storage
+}
+
+func InitOfferImageService(offerImageRepository *repositories.OfferImageRepository, offerRepository *repositories.OfferRepository, imageStorage storage.Storage) *OfferImageService {
+	return &OfferImageService{
+		repo:    offerImageRepository,
+		offers:  offerRepository,
+		storage: imageStorage,
+	}
+}
+
+// Method that adds a photo to the Offer of the user, storing it re-encoded as jpeg next to its thumbnail
+func (svc *OfferImageService) Upload(content io.ReadSeeker, offerUuid, username string) (model.OfferImage, error) {
+
+	offer, err := svc.offers.Get(offerUuid, username)
+	if err != nil {
+		return model.OfferImage{}, err
+	}
+
+	count, err := svc.repo.Count(offer.GetId())
+	if err != nil {
+		return model.OfferImage{}, err
+	}
+	if count >= maxOfferImages {
+		return model.OfferImage{}, middleware.NewError(http.StatusConflict, "Offers can have at most "+strconv.Itoa(maxOfferImages)+" images")
+	}
+
+	decoded, err := utils.DecodeImage(content)
+	if err != nil {
+		return model.OfferImage{}, err
+	}
+
+	full := utils.FitImage(decoded, model.ImageSide)
+	fullBytes, err := utils.EncodeJPEG(full)
+	if err != nil {
+		return model.OfferImage{}, err
+	}
+	thumbnailBytes, err := utils.EncodeJPEG(utils.FitImage(full, model.ThumbnailSide))
+	if err != nil {
+		return model.OfferImage{}, err
+	}
+
+	image := model.NewOfferImage(offer.GetId(), full.Bounds().Dx(), full.Bounds().Dy(), int64(len(fullBytes)))
+	if err := svc.repo.Create(image); err != nil {
+		return model.OfferImage{}, err
+	}
+
+	// Files are saved once the image has its uuid, and the entry is removed when they can't be
+	if err := svc.save(image, fullBytes, thumbnailBytes); err != nil {
+		log.Println("Error - Failed to store image: " + err.Error())
+		if err := svc.repo.Delete(image.GetId()); err != nil {
+			log.Println("Error - Failed to remove image entry: " + err.Error())
+		}
+		return model.OfferImage{}, middleware.NewError(http.StatusInternalServerError, "Image could not be stored")
+	}
+
+	image.SetURLs()
+	return *image, nil
+}
+
+func (svc *OfferImageService) GetAll(offerUuid string, viewer model.Viewer) ([]model.OfferImage, error) {
+
+	offer, err := svc.offers.GetVisible(offerUuid, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := svc.repo.GetAll(offer.GetId())
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		images[i].SetURLs()
+	}
+	return images, nil
+}
+
+// Method that opens the stored jpeg of the image, or of its thumbnail, as long as the viewer can see its Offer
+func (svc *OfferImageService) Open(offerUuid, imageUuid string, thumbnail bool, viewer model.Viewer) (io.ReadCloser, error) {
+
+	offer, err := svc.offers.GetVisible(offerUuid, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := svc.repo.Get(imageUuid, offer.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	key := image.GetKey()
+	if thumbnail {
+		key = image.GetThumbnailKey()
+	}
+
+	file, err := svc.storage.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, middleware.NewError(http.StatusNotFound, "Image file not found")
+	}
+	return file, err
+}
+
+// Method that removes a photo from the Offer of the user
+func (svc *OfferImageService) Delete(offerUuid, imageUuid, username string) error {
+
+	offer, err := svc.offers.Get(offerUuid, username)
+	if err != nil {
+		return err
+	}
+
+	image, err := svc.repo.Get(imageUuid, offer.GetId())
+	if err != nil {
+		return err
+	}
+
+	if err := svc.repo.Delete(image.GetId()); err != nil {
+		return err
+	}
+	svc.remove(image)
+	return nil
+}
+
+// Method that runs the deletion of the Offer, removing the files of its images only once it succeeded -> Their entries go away with the Offer
+func (svc *OfferImageService) DeleteWithOffer(offerUuid string, deleteOffer func() error) error {
+
+	images, err := svc.repo.GetAll(offerUuid)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteOffer(); err != nil {
+		return err
+	}
+	for _, image := range images {
+		svc.remove(image)
+	}
+	return nil
+}
+
+func (svc *OfferImageService) save(image *model.OfferImage, full, thumbnail []byte) error {
+
+	if err := svc.storage.Save(image.GetKey(), bytes.NewReader(full)); err != nil {
+		return err
+	}
+	if err := svc.storage.Save(image.GetThumbnailKey(), bytes.NewReader(thumbnail)); err != nil {
+		svc.remove(*image)
+		return err
+	}
+	return nil
+}
+
+// Method that deletes the files of the image, logging failures as they only leave orphan files behind
+func (svc *OfferImageService) remove(image model.OfferImage) {
+
+	for _, key := range []string{image.GetKey(), image.GetThumbnailKey()} {
+		if err := svc.storage.Delete(key); err != nil {
+			log.Println("Error - Failed to delete image file " + key + ": " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"net/http"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/repositories"
+)
+
+type purchaseRequestRepository interface {
+	Create(request *model.PurchaseRequest, event model.NegotiationEvent) error
+	GetAll(offerUuid, buyer string) ([]model.PurchaseRequest, error)
+	Get(uuid, offerUuid string) (model.PurchaseRequest, error)
+	Negotiate(request model.PurchaseRequest, previous string, event model.NegotiationEvent) error
+	Accept(request model.PurchaseRequest, previous string, event model.NegotiationEvent) error
+}
+
+// PurchaseRequestService holds the negotiation between buyers and the seller of an Offer
+type PurchaseRequestService struct {
+	repo   purchaseRequestRepository
+	offers offerRepository
+}
+
+func InitPurchaseRequestService(purchaseRequestRepository *repositories.PurchaseRequestRepository, offerRepository *repositories.OfferRepository) *PurchaseRequestService {
+	return &PurchaseRequestService{
+		repo:   purchaseRequestRepository,
+		offers: offerRepository,
+	}
+}
+
+// Method that sends a request of the buyer on an active Offer, in its currency and at its amount when none is given
+func (svc *PurchaseRequestService) Create(input *model.PurchaseRequestInput, offerUuid, buyer string) (model.PurchaseRequest, error) {
+
+	offer, err := svc.offers.Get(offerUuid, "")
+	if err != nil {
+		return model.PurchaseRequest{}, err
+	}
+
+	if offer.GetUsername() == buyer {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusForbidden, "Sellers can't request their own offers")
+	}
+	if offer.GetState() != model.OfferActive {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Only active offers take requests, this one is "+offer.GetState())
+	}
+
+	// Without an amount, the offer's is asked -> Read along with its currency as the request is created
+	amount := input.GetAmount()
+	request := model.NewPurchaseRequest(offer.GetId(), buyer, amount, offer.GetCurrency())
+	if err := svc.repo.Create(request, model.NewNegotiationEvent(buyer, model.ActionRequest, amount)); err != nil {
+		return model.PurchaseRequest{}, err
+	}
+	return *request, nil
+}
+
+// Method that lists the requests of the Offer with their history -> The seller sees them all and buyers only theirs
+func (svc *PurchaseRequestService) GetAll(offerUuid, username string) ([]model.PurchaseRequest, error) {
+
+	offer, err := svc.offers.Get(offerUuid, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.GetUsername() == username {
+		return svc.repo.GetAll(offer.GetId(), "")
+	}
+	return svc.repo.GetAll(offer.GetId(), username)
+}
+
+// Method that answers the other side with a new amount -> The seller counters pending requests and the buyer countered ones
+func (svc *PurchaseRequestService) Counter(input *model.CounterInput, offerUuid, requestUuid, username string) (model.PurchaseRequest, error) {
+
+	offer, request, err := svc.get(offerUuid, requestUuid)
+	if err != nil {
+		return model.PurchaseRequest{}, err
+	}
+
+	var awaiting, next string
+	switch username {
+	case offer.GetUsername():
+		awaiting, next = model.RequestPending, model.RequestCountered
+	case request.GetBuyer():
+		awaiting, next = model.RequestCountered, model.RequestPending
+	default:
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusForbidden, "Only the seller and the buyer negotiate a request")
+	}
+
+	if request.GetStatus() != awaiting {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Request is "+request.GetStatus()+", it isn't your turn to counter")
+	}
+	if offer.GetState() != model.OfferActive {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Only active offers are negotiated, this one is "+offer.GetState())
+	}
+
+	request.SetStatus(next)
+	request.SetAmount(input.GetAmount())
+	return request, svc.repo.Negotiate(request, awaiting, model.NewNegotiationEvent(username, model.ActionCounter, input.GetAmount()))
+}
+
+// Method that lets the seller accept a pending request, reserving the Offer and declining its other requests
+func (svc *PurchaseRequestService) Accept(offerUuid, requestUuid, username string) (model.PurchaseRequest, error) {
+
+	offer, request, err := svc.getAsSeller(offerUuid, requestUuid, username)
+	if err != nil {
+		return model.PurchaseRequest{}, err
+	}
+
+	if request.GetStatus() != model.RequestPending {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Only pending requests can be accepted, this one is "+request.GetStatus())
+	}
+	if offer.GetState() != model.OfferActive {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Only active offers can be reserved, this one is "+offer.GetState())
+	}
+
+	request.SetStatus(model.RequestAccepted)
+	return request, svc.repo.Accept(request, model.RequestPending, model.NewNegotiationEvent(username, model.ActionAccept, request.GetAmount()))
+}
+
+// Method that lets the seller reject a request still under negotiation
+func (svc *PurchaseRequestService) Reject(offerUuid, requestUuid, username string) (model.PurchaseRequest, error) {
+
+	_, request, err := svc.getAsSeller(offerUuid, requestUuid, username)
+	if err != nil {
+		return model.PurchaseRequest{}, err
+	}
+
+	previous := request.GetStatus()
+	if previous != model.RequestPending && previous != model.RequestCountered {
+		return model.PurchaseRequest{}, middleware.NewError(http.StatusConflict, "Request is already "+previous)
+	}
+
+	request.SetStatus(model.RequestRejected)
+	return request, svc.repo.Negotiate(request, previous, model.NewNegotiationEvent(username, model.ActionReject, request.GetAmount()))
+}
+
+// Method that fetches the Offer and one of its requests
+func (svc *PurchaseRequestService) get(offerUuid, requestUuid string) (model.Offer, model.PurchaseRequest, error) {
+
+	offer, err := svc.offers.Get(offerUuid, "")
+	if err != nil {
+		return model.Offer{}, model.PurchaseRequest{}, err
+	}
+
+	request, err := svc.repo.Get(requestUuid, offer.GetId())
+	if err != nil {
+		return model.Offer{}, model.PurchaseRequest{}, err
+	}
+	return offer, request, nil
+}
+
+// Method that fetches the Offer and one of its requests, as long as the user is the seller
+func (svc *PurchaseRequestService) getAsSeller(offerUuid, requestUuid, username string) (model.Offer, model.PurchaseRequest, error) {
+
+	offer, request, err := svc.get(offerUuid, requestUuid)
+	if err != nil {
+		return model.Offer{}, model.PurchaseRequest{}, err
+	}
+
+	if offer.GetUsername() != username {
+		return model.Offer{}, model.PurchaseRequest{}, middleware.NewError(http.StatusForbidden, "Only the seller can answer requests")
+	}
+	return offer, request, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"marketplace/clients"
+	"marketplace/model"
+	"marketplace/repositories"
+	"marketplace/utils"
+)
+
+type reputationRepository interface {
+	GetSellers() ([]string, error)
+	Replace(reputations []model.SellerReputation) error
+}
+
+type ratingClient interface {
+	GetSummaries(namespace string, ids []string) ([]model.RatingSummary, error)
+}
+
+// ReputationService keeps a copy of the ratings of the sellers, so offers are shown and filtered with them without asking the rating service every time
+type ReputationService struct {
+	repo    reputationRepository
+	ratings ratingClient
+}
+
+func InitReputationService(reputationRepository *repositories.ReputationRepository, ratingClient *clients.RatingClient) *ReputationService {
+	return &ReputationService{
+		repo:    reputationRepository,
+		ratings: ratingClient,
+	}
+}
+
+// Method that syncs the reputations right away and then every interval, until the process stops -> Should run on its own goroutine
+func (svc *ReputationService) Sync(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if synced, err := svc.Refresh(); err != nil {
+			log.Println("Error - Failed to sync seller reputations: " + err.Error())
+		} else {
+			log.Println("Synced seller reputations: " + strconv.Itoa(synced))
+		}
+		<-ticker.C
+	}
+}
+
+// Method that fetches the reputation of every seller from the rating service, in batches, returning how many were synced
+func (svc *ReputationService) Refresh() (int, error) {
+
+	sellers, err := svc.repo.GetSellers()
+	if err != nil {
+		return 0, err
+	}
+
+	var usernames []string
+	for _, seller := range sellers {
+		if utils.IsUsername(seller) { // The rating service only references valid usernames
+			usernames = append(usernames, seller)
+		}
+	}
+
+	synced := 0
+	for start := 0; start < len(usernames); start += clients.MaxSummaryBatch {
+		batch := usernames[start:min(start+clients.MaxSummaryBatch, len(usernames))]
+
+		summaries, err := svc.ratings.GetSummaries(model.UserNamespace, batch)
+		if err != nil {
+			return synced, err
+		}
+
+		reputations := make([]model.SellerReputation, len(summaries))
+		for i, summary := range summaries {
+			reputations[i] = model.NewSellerReputation(summary)
+		}
+		if err := svc.repo.Replace(reputations); err != nil {
+			return synced, err
+		}
+		synced += len(reputations)
+	}
+	return synced, nil
+}
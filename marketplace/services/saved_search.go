@@ -0,0 +1,62 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+
+	"marketplace/middleware"
+	"marketplace/model"
+	"marketplace/repositories"
+)
+
+const maxSavedSearches = 20 // Most saved searches a user can have
+
+type savedSearchRepository interface {
+	Create(search *model.SavedSearch) error
+	GetAll(username string) ([]model.SavedSearch, error)
+	Count(username string) (int, error)
+	Delete(uuid, username string) error
+}
+
+// SavedSearchService keeps what buyers want, so the offers that match get into their alerts
+type SavedSearchService struct {
+	repo savedSearchRepository
+}
+
+func InitSavedSearchService(savedSearchRepository *repositories.SavedSearchRepository) *SavedSearchService {
+	return &SavedSearchService{
+		repo: savedSearchRepository,
+	}
+}
+
+// Method that saves the search of the user, whose filter was already validated
+func (svc *SavedSearchService) Create(input *model.SavedSearchInput, filter *model.OfferFilter, username string) (model.SavedSearch, error) {
+
+	if err := checkCurrency(input.Currency); err != nil {
+		return model.SavedSearch{}, err
+	}
+
+	count, err := svc.repo.Count(username)
+	if err != nil {
+		return model.SavedSearch{}, err
+	}
+	if count >= maxSavedSearches {
+		return model.SavedSearch{}, middleware.NewError(http.StatusConflict, "Users can have at most "+strconv.Itoa(maxSavedSearches)+" saved searches")
+	}
+
+	search := model.NewSavedSearch(username, input, filter)
+	if err := svc.repo.Create(search); err != nil {
+		return model.SavedSearch{}, err
+	}
+	return *search, nil
+}
+
+func (svc *SavedSearchService) GetAll(username string) ([]model.SavedSearch, error) {
+
+	return svc.repo.GetAll(username)
+}
+
+func (svc *SavedSearchService) Delete(uuid, username string) error {
+
+	return svc.repo.Delete(uuid, username)
+}
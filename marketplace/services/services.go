@@ -1,17 +1,47 @@
 package services
 
-import "marketplace/repositories"
+import (
+	"time"
+
+	"marketplace/clients"
+	"marketplace/repositories"
+	"marketplace/storage"
+)
 
 // Repositories contains all the repo structs
 type Services struct {
-	OfferService *OfferService
+	OfferService           *OfferService
+	PurchaseRequestService *PurchaseRequestService
+	ExchangeRateService    *ExchangeRateService
+	OfferImageService      *OfferImageService
+	MarketService          *MarketService
+	SavedSearchService     *SavedSearchService
+	AlertService           *AlertService
+	ReputationService      *ReputationService
+	ModerationService      *ModerationService
 }
 
 // InitRepositories should be called in main.go
-func InitServices(repositories *repositories.Repositories) *Services {
-	offerService := InitOfferService(repositories.OfferRepository)
+func InitServices(repositories *repositories.Repositories, catalogClient *clients.CatalogClient, ratingClient *clients.RatingClient, userClient *clients.UserClient, imageStorage storage.Storage, offerTTL time.Duration) *Services {
+	offerImageService := InitOfferImageService(repositories.OfferImageRepository, repositories.OfferRepository, imageStorage)
+	offerService := InitOfferService(repositories.OfferRepository, repositories.ExchangeRateRepository, offerImageService, catalogClient, offerTTL)
+	purchaseRequestService := InitPurchaseRequestService(repositories.PurchaseRequestRepository, repositories.OfferRepository)
+	exchangeRateService := InitExchangeRateService(repositories.ExchangeRateRepository)
+	marketService := InitMarketService(repositories.MarketRepository, repositories.ExchangeRateRepository)
+	savedSearchService := InitSavedSearchService(repositories.SavedSearchRepository)
+	alertService := InitAlertService(repositories.AlertRepository)
+	reputationService := InitReputationService(repositories.ReputationRepository, ratingClient)
+	moderationService := InitModerationService(repositories.ModerationRepository, repositories.OfferRepository, offerImageService, userClient)
 
 	return &Services{
-		OfferService: offerService,
+		OfferService:           offerService,
+		PurchaseRequestService: purchaseRequestService,
+		ExchangeRateService:    exchangeRateService,
+		OfferImageService:      offerImageService,
+		MarketService:          marketService,
+		SavedSearchService:     savedSearchService,
+		AlertService:           alertService,
+		ReputationService:      reputationService,
+		ModerationService:      moderationService,
 	}
 }
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Keys become file names, so they can't climb out of the directory
+var validKey = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// LocalStorage keeps files in a directory of the local disk
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates the directory when missing and stores files in it
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Println("Error creating storage directory: " + err.Error())
+		return nil, err
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save writes into a temporary file first and renames it, so a file is never read half written
+func (ls *LocalStorage) Save(key string, content io.Reader) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(ls.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ls *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes the file, doing nothing when it is already gone
+func (ls *LocalStorage) Delete(key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (ls *LocalStorage) path(key string) (string, error) {
+	if !validKey.MatchString(key) {
+		return "", errors.New("invalid storage key: " + key)
+	}
+	return filepath.Join(ls.dir, key), nil
+}
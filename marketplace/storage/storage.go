@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no file is stored under the key
+var ErrNotFound = errors.New("file not found")
+
+// Storage keeps files under keys, allowing the backend to be swapped (E.g local disk, object storage)
+type Storage interface {
+	Save(key string, content io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
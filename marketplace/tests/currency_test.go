@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+// Seller of the offers converted in these tests only
+const rounder = "rounder"
+
+/* Tests PUT the Exchange Rates as an admin with success*/
+func TestReplaceExchangeRates(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Put("/api/exchange-rate").
+		JSON(`{"base": "EUR", "rates": {"USD": "1.5", "GBP": "1.25", "JPY": "161.42"}}`).
+		Header("Authorization", header(admin)).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests PUT the Exchange Rates as a user that isn't an admin*/
+func TestReplaceExchangeRatesNotAdmin(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Put("/api/exchange-rate").
+		JSON(`{"base": "EUR", "rates": {"USD": "2"}}`).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+}
+
+/* Tests converted amounts are rounded half away from zero, between currencies of different minor units*/
+func TestConvertAmountRounding(t *testing.T) {
+	for _, amount := range []int{1, 3, 5, 1050} {
+		apitest.New().
+			HandlerFunc(router.ServeHTTP).
+			Post("/api/offer").
+			JSON(`{"catalog_ref": 1, "name": "Catan", "amount": `+strconv.Itoa(amount)+`, "currency": "EUR", "condition": "good", "shipping": true}`).
+			Header("Authorization", header(rounder)).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+	}
+
+	// 1.5 -> 2, 4.5 -> 5, 7.5 -> 8 and exactly 1575 cents of USD
+	assertConverted(t, "USD", map[int64]int64{1: 2, 3: 5, 5: 8, 1050: 1575})
+	// 1.25 -> 1, 3.75 -> 4, 6.25 -> 6 and 1312.5 -> 1313 pence
+	assertConverted(t, "GBP", map[int64]int64{1: 1, 3: 4, 5: 6, 1050: 1313})
+	// 1.61 -> 2, 4.84 -> 5, 8.07 -> 8 and 1694.91 -> 1695 yen, which have no minor unit
+	assertConverted(t, "JPY", map[int64]int64{1: 2, 3: 5, 5: 8, 1050: 1695})
+}
+
+/* Tests GET Offers filtered by amount without a currency*/
+func TestGetOffersAmountWithoutCurrency(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("min_amount", "500").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests GET Offers sorted by amount without a currency*/
+func TestGetOffersSortByAmountWithoutCurrency(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("sortBy", "amount.asc").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests GET Offers filtered and sorted by amount once converted*/
+func TestGetOffersAmountConverted(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("username", rounder).
+		Query("currency", "USD").
+		Query("min_amount", "5").
+		Query("max_amount", "8").
+		Query("sortBy", "amount.desc").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			offers, err := decodeOffers(res)
+			if err != nil {
+				return err
+			}
+			for i, offer := range offers {
+				if *offer.ConvertedAmount < 5 || *offer.ConvertedAmount > 8 {
+					return fmt.Errorf("converted amount %d out of range", *offer.ConvertedAmount)
+				}
+				if i > 0 && *offer.ConvertedAmount > *offers[i-1].ConvertedAmount {
+					return fmt.Errorf("converted amounts not in descending order")
+				}
+			}
+			return nil
+		}).
+		End()
+}
+
+// Checks every offer of the rounder converts into the currency as expected, by its amount in EUR
+func assertConverted(t *testing.T, currency string, expected map[int64]int64) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("username", rounder).
+		Query("currency", currency).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			offers, err := decodeOffers(res)
+			if err != nil {
+				return err
+			}
+			for _, offer := range offers {
+				if offer.ConvertedAmount == nil || *offer.ConvertedAmount != expected[offer.Amount] {
+					return fmt.Errorf("%d EUR should convert into %d %s", offer.Amount, expected[offer.Amount], currency)
+				}
+			}
+			return nil
+		}).
+		End()
+}
+
+// Decodes the offers of a listing page
+func decodeOffers(res *http.Response) ([]model.Offer, error) {
+	var page struct {
+		Items []model.Offer `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests listing, repricing and selling Offers records their price history and makes up the market stats*/
+func TestMarketStats(t *testing.T) {
+	// At the exchange rates of TestReplaceExchangeRates
+	listed := createOffer(t, seller, `{"catalog_ref": `+catalogSold+`, "name": "Carcassonne", "amount": 3000, "currency": "USD", "condition": "good", "shipping": true}`)
+	updateMarketOffer(t, listed.GetId(), 4500, "USD")
+
+	negotiated := createOffer(t, seller, `{"catalog_ref": `+catalogSold+`, "name": "Carcassonne", "amount": 2000, "currency": "EUR", "condition": "good", "shipping": true}`)
+	request := requestOffer(t, buyer, negotiated.GetId(), `{"amount": 1800}`, http.StatusOK)
+	answer(t, seller, request, "accept", http.StatusOK)
+	transition(t, seller, negotiated.GetId(), "sell", http.StatusOK)
+
+	draft := createOffer(t, seller, `{"catalog_ref": `+catalogSold+`, "name": "Carcassonne", "amount": 1200, "currency": "EUR", "condition": "good", "shipping": true, "state": "draft"}`)
+	updateMarketOffer(t, draft.GetId(), 1000, "EUR") // Drafts aren't asked for yet
+	transition(t, seller, draft.GetId(), "publish", http.StatusOK)
+	transition(t, seller, draft.GetId(), "sell", http.StatusOK)
+
+	// Without a rate, it counts as an active listing but its price is left out of the medians
+	createOffer(t, seller, `{"catalog_ref": `+catalogSold+`, "name": "Carcassonne", "amount": 9900, "currency": "CHF", "condition": "good", "shipping": true}`)
+
+	assertPriceEvents(t, listed.GetId(), []model.PriceEvent{
+		{Event: model.PriceListed, Amount: 3000, Currency: "USD"},
+		{Event: model.PriceChanged, Amount: 4500, Currency: "USD"},
+	})
+	assertPriceEvents(t, negotiated.GetId(), []model.PriceEvent{
+		{Event: model.PriceListed, Amount: 2000, Currency: "EUR"},
+		{Event: model.PriceSold, Amount: 1800, Currency: "EUR"},
+	})
+	assertPriceEvents(t, draft.GetId(), []model.PriceEvent{
+		{Event: model.PriceListed, Amount: 1000, Currency: "EUR"},
+		{Event: model.PriceSold, Amount: 1000, Currency: "EUR"},
+	})
+
+	assertStats(t, catalogSold, "EUR", func(stats model.MarketStats) error {
+		if stats.ActiveListings != 2 || !isAmount(stats.MedianAsking, 3000) {
+			return fmt.Errorf("%d active listings at a median of %v instead of 2 at 3000", stats.ActiveListings, stats.MedianAsking)
+		}
+		if stats.Sales != 2 || !isAmount(stats.MedianSold, 1400) {
+			return fmt.Errorf("%d sales at a median of %v instead of 2 at 1400", stats.Sales, stats.MedianSold)
+		}
+		return assertTrend(stats, 4, 2) // The listing without a rate is left out too
+	})
+
+	assertStats(t, catalogSold, "USD", func(stats model.MarketStats) error {
+		if !isAmount(stats.MedianAsking, 4500) || !isAmount(stats.MedianSold, 2100) {
+			return fmt.Errorf("medians of %v asked and %v sold instead of 4500 and 2100", stats.MedianAsking, stats.MedianSold)
+		}
+		return nil
+	})
+
+	apitest.New(). // Amounts can't be converted into it
+			HandlerFunc(router.ServeHTTP).
+			Get("/api/market/boardgame/"+catalogSold+"/stats").
+			Query("currency", "CHF").
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+}
+
+/* Tests the market stats of a boardgame listed but never sold*/
+func TestMarketStatsNoSales(t *testing.T) {
+	createOffer(t, seller, `{"catalog_ref": `+catalogUnsold+`, "name": "Carcassonne", "amount": 2500, "currency": "EUR", "condition": "good", "shipping": true}`)
+
+	assertStats(t, catalogUnsold, "EUR", func(stats model.MarketStats) error {
+		if stats.ActiveListings != 1 || !isAmount(stats.MedianAsking, 2500) {
+			return fmt.Errorf("%d active listings at a median of %v instead of 1 at 2500", stats.ActiveListings, stats.MedianAsking)
+		}
+		if stats.Sales != 0 || stats.MedianSold != nil {
+			return fmt.Errorf("%d sales at a median of %v instead of none", stats.Sales, stats.MedianSold)
+		}
+		return assertTrend(stats, 1, 0)
+	})
+}
+
+/* Tests GET the market stats with parameters outside of what they allow*/
+func TestMarketStatsMalformed(t *testing.T) {
+	for _, query := range []map[string]string{
+		{"currency": "EURO"},
+		{"period": "yesterday"},
+		{"period": "731d"},
+		{"bucket": "year"},
+	} {
+		request := apitest.New().
+			HandlerFunc(router.ServeHTTP).
+			Get("/api/market/boardgame/" + catalogSold + "/stats")
+		for key, value := range query {
+			request = request.Query(key, value)
+		}
+		request.Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	}
+}
+
+// Reprices the Offer of the seller to the amount in the currency
+func updateMarketOffer(t *testing.T, uuid string, amount int64, currency string) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/offer/"+uuid).
+		JSON(fmt.Sprintf(`{"catalog_ref": %s, "name": "Carcassonne", "amount": %d, "currency": "%s", "condition": "good", "shipping": true}`, catalogSold, amount, currency)).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+// Checks the price history of the Offer has the events, oldest first -> The history isn't served, so it is read off the database
+func assertPriceEvents(t *testing.T, uuid string, expected []model.PriceEvent) {
+	var events []model.PriceEvent
+	if err := db.GetAll(`SELECT * FROM price_event WHERE offer_uuid=$1 ORDER BY id asc`, &events, uuid); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != len(expected) {
+		t.Errorf("offer %s has %d price events instead of %d", uuid, len(events), len(expected))
+		return
+	}
+	for i, event := range events {
+		if event.Event != expected[i].Event || event.Amount != expected[i].Amount || event.Currency != expected[i].Currency {
+			t.Errorf("price event %s at %d %s in place of %s at %d %s", event.Event, event.Amount, event.Currency, expected[i].Event, expected[i].Amount, expected[i].Currency)
+		}
+	}
+}
+
+// Fetches the market stats of the boardgame in the currency over the last day, checking them
+func assertStats(t *testing.T, catalogRef, currency string, check func(stats model.MarketStats) error) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/market/boardgame/"+catalogRef+"/stats").
+		Query("currency", currency).
+		Query("period", "1d").
+		Query("bucket", model.MarketBucketDay).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var stats model.MarketStats
+			if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+				return err
+			}
+			return check(stats)
+		}).
+		End()
+}
+
+// Checks the trend covers the period with empty buckets included, adding up to the listings and sales
+func assertTrend(stats model.MarketStats, listings, sales int64) error {
+	if len(stats.Trend) != 2 { // Yesterday's and today's
+		return fmt.Errorf("trend of %d buckets instead of 2", len(stats.Trend))
+	}
+
+	var listed, sold int64
+	for _, bucket := range stats.Trend {
+		listed += bucket.Listings
+		sold += bucket.Sales
+	}
+	if listed != listings || sold != sales {
+		return fmt.Errorf("trend of %d listings and %d sales instead of %d and %d", listed, sold, listings, sales)
+	}
+	return nil
+}
+
+func isAmount(amount *int64, expected int64) bool {
+	return amount != nil && *amount == expected
+}
@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests the moderation endpoints as users without the role they need*/
+func TestModerationForbidden(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/moderation/queue").
+		Expect(t).
+		Status(http.StatusUnauthorized).
+		End()
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/moderation/queue").
+		Header("Authorization", header(buyer)).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	moderate(t, header(buyer), offer.GetId(), model.ModerationHide, http.StatusForbidden)
+	moderate(t, header(seller), offer.GetId(), model.ModerationDelete, http.StatusForbidden)
+
+	// Taking offers away from their owners is left to admins
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationDelete, http.StatusForbidden)
+	transfer(t, roleHeader(moderator, "moderator"), offer.GetId(), buyer, http.StatusForbidden)
+
+	assertActions(t, offer.GetId(), nil)
+}
+
+/* Tests the admins are the users listed as admins and the ones whose token has the admin role*/
+func TestModerationAdminRole(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	moderate(t, header(admin), offer.GetId(), model.ModerationHide, http.StatusOK)
+	moderate(t, roleHeader(buyer, "admin"), offer.GetId(), model.ModerationRestore, http.StatusOK)
+
+	assertActions(t, offer.GetId(), []string{model.ModerationHide, model.ModerationRestore})
+}
+
+/* Tests POST a report of an Offer, once per reporter until resolved and never of their own offers*/
+func TestReportOffer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	report(t, buyer, offer.GetId(), http.StatusOK)
+	report(t, buyer, offer.GetId(), http.StatusConflict)
+	report(t, seller, offer.GetId(), http.StatusUnprocessableEntity)
+	report(t, buyer, "00000000-0000-0000-0000-000000000000", http.StatusNotFound)
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/moderation/offer/"+offer.GetId()+"/reports").
+		Header("Authorization", roleHeader(moderator, "moderator")).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var reports []model.OfferReport
+			if err := json.NewDecoder(res.Body).Decode(&reports); err != nil {
+				return err
+			}
+			if len(reports) != 1 || reports[0].Reporter != buyer || reports[0].ResolvedAt != nil {
+				return fmt.Errorf("offer has %d reports instead of the open one of %s", len(reports), buyer)
+			}
+			return nil
+		}).
+		End()
+}
+
+/* Tests GET the moderation queue, which only lists the offers with enough open reports*/
+func TestModerationQueue(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	report(t, buyer, offer.GetId(), http.StatusOK)
+	report(t, admin, offer.GetId(), http.StatusOK)
+	assertQueued(t, offer.GetId(), 0)
+
+	report(t, moderator, offer.GetId(), http.StatusOK)
+	assertQueued(t, offer.GetId(), 3)
+
+	// Dismissing resolves the reports, taking the offer out of the queue
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationDismiss, http.StatusOK)
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationDismiss, http.StatusConflict)
+	assertQueued(t, offer.GetId(), 0)
+	getOffer(t, "", offer.GetId(), http.StatusOK)
+
+	assertActions(t, offer.GetId(), []string{model.ModerationDismiss})
+}
+
+/* Tests hiding an Offer, which only moderators and its seller see until restored*/
+func TestHideOffer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	report(t, buyer, offer.GetId(), http.StatusOK)
+
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationHide, http.StatusOK)
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationHide, http.StatusConflict)
+
+	getOffer(t, "", offer.GetId(), http.StatusNotFound)
+	getOffer(t, buyer, offer.GetId(), http.StatusNotFound)
+	getOffer(t, seller, offer.GetId(), http.StatusOK)
+	getOffer(t, admin, offer.GetId(), http.StatusOK)
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/"+offer.GetId()).
+		Header("Authorization", roleHeader(moderator, "moderator")).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+	listAllStates(t, "", func(offers []model.Offer) error {
+		for _, listed := range offers {
+			if listed.GetId() == offer.GetId() {
+				return fmt.Errorf("hidden offer %s listed", offer.GetId())
+			}
+		}
+		return nil
+	})
+	report(t, buyer, offer.GetId(), http.StatusNotFound) // Hiding resolved the report, and hidden offers can't be reported
+
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationRestore, http.StatusOK)
+	moderate(t, roleHeader(moderator, "moderator"), offer.GetId(), model.ModerationRestore, http.StatusConflict)
+	getOffer(t, "", offer.GetId(), http.StatusOK)
+
+	assertActions(t, offer.GetId(), []string{model.ModerationHide, model.ModerationRestore})
+}
+
+/* Tests force deleting an Offer of another user, whose audit is kept*/
+func TestForceDeleteOffer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	moderate(t, header(admin), offer.GetId(), model.ModerationDelete, http.StatusOK)
+	moderate(t, header(admin), offer.GetId(), model.ModerationDelete, http.StatusNotFound)
+
+	getOffer(t, seller, offer.GetId(), http.StatusNotFound)
+	getOffer(t, admin, offer.GetId(), http.StatusNotFound)
+
+	assertActions(t, offer.GetId(), []string{model.ModerationDelete})
+}
+
+/* Tests transferring an Offer, only to another registered user*/
+func TestTransferOffer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	transfer(t, header(admin), offer.GetId(), "nobody", http.StatusUnprocessableEntity) // Unknown to user-management
+	transfer(t, header(admin), offer.GetId(), "no one", http.StatusUnprocessableEntity)
+	transfer(t, header(admin), offer.GetId(), seller, http.StatusUnprocessableEntity)
+	transfer(t, header(admin), offer.GetId(), buyer, http.StatusOK)
+
+	updateOffer(t, seller, offer.GetId(), http.StatusNotFound)
+	updateOffer(t, buyer, offer.GetId(), http.StatusOK)
+
+	assertActions(t, offer.GetId(), []string{model.ModerationTransfer})
+}
+
+// Reports the Offer as the user, expecting the status
+func report(t *testing.T, username, uuid string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+uuid+"/report").
+		JSON(`{"reason": "Reported by `+username+`"}`).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Takes the moderation action on the Offer with the header, for a reason naming the action, expecting the status
+func moderate(t *testing.T, authorization, uuid, action string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/moderation/offer/"+uuid+"/"+action).
+		JSON(`{"reason": "Reason to `+action+`"}`).
+		Header("Authorization", authorization).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Transfers the Offer to the user with the header, expecting the status
+func transfer(t *testing.T, authorization, uuid, username string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/moderation/offer/"+uuid+"/transfer").
+		JSON(`{"username": "`+username+`", "reason": "Reason to transfer"}`).
+		Header("Authorization", authorization).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Checks the moderation queue has the Offer with the open reports, or doesn't when there are none expected
+func assertQueued(t *testing.T, uuid string, reports int64) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/moderation/queue").
+		Query("min_reports", "3").
+		Query("limit", "100").
+		Header("Authorization", roleHeader(moderator, "moderator")).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var page struct {
+				Items []model.ModerationQueueItem `json:"items"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+				return err
+			}
+			for _, item := range page.Items {
+				if item.OfferUuid == uuid && item.Reports != reports {
+					return fmt.Errorf("offer %s queued with %d reports instead of %d", uuid, item.Reports, reports)
+				}
+				if item.OfferUuid == uuid {
+					return nil
+				}
+			}
+			if reports > 0 {
+				return fmt.Errorf("offer %s not queued", uuid)
+			}
+			return nil
+		}).
+		End()
+}
+
+// Checks the audit of the Offer has one entry per action, oldest first, each with the reason it was taken for
+func assertActions(t *testing.T, uuid string, actions []string) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/moderation/audit").
+		Query("offer_uuid", uuid).
+		Header("Authorization", header(admin)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var page struct {
+				Items []model.ModerationAction `json:"items"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+				return err
+			}
+			if len(page.Items) != len(actions) {
+				return fmt.Errorf("audit has %d entries instead of %d", len(page.Items), len(actions))
+			}
+			for i, action := range actions {
+				taken := page.Items[len(actions)-1-i] // Newest first
+				if taken.Action != action || taken.Reason != "Reason to "+action || taken.Owner != seller {
+					return fmt.Errorf("audit entry %d is %s for %q instead of %s", i, taken.Action, taken.Reason, action)
+				}
+			}
+			return nil
+		}).
+		End()
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+// Seller of the offers filtered in these tests only, new on every run so earlier runs don't show up
+var filterer = "filterer" + strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+
+/* Tests POST the Offers filtered in these tests*/
+func TestCreateFilteredOffers(t *testing.T) {
+	createOffer(t, filterer, `{"catalog_ref": `+catalogBoardgame+`, "name": "Catan Big Box", "amount": 1000, "currency": "EUR", "condition": "new", "shipping": true}`)
+	createOffer(t, filterer, `{"catalog_ref": `+catalogBoardgame+`, "name": "Catan", "amount": 3000, "currency": "EUR", "condition": "worn", "shipping": true}`)
+	createOffer(t, filterer, `{"catalog_ref": `+catalogExpansion+`, "name": "Seafarers", "amount": 2000, "currency": "EUR", "condition": "good", "shipping": true}`)
+}
+
+/* Tests GET Offers whose name has every word searched, ignoring case*/
+func TestGetOffersSearch(t *testing.T) {
+	assertFiltered(t, map[string]string{"q": "big CATAN"}, func(offers []model.Offer) error {
+		if len(offers) != 1 || offers[0].GetName() != "Catan Big Box" {
+			return fmt.Errorf("search found %d offers instead of the big box", len(offers))
+		}
+		return nil
+	})
+}
+
+/* Tests GET Offers filtered by type, condition and catalog boardgame*/
+func TestGetOffersFiltered(t *testing.T) {
+	assertFiltered(t, map[string]string{"type": "expansion"}, func(offers []model.Offer) error {
+		if len(offers) != 1 || offers[0].GetType() != model.OfferTypeExpansion {
+			return fmt.Errorf("type filter found %d offers instead of the expansion", len(offers))
+		}
+		return nil
+	})
+
+	assertFiltered(t, map[string]string{"condition": "new,worn"}, func(offers []model.Offer) error {
+		if len(offers) != 2 {
+			return fmt.Errorf("condition filter found %d offers instead of 2", len(offers))
+		}
+		return nil
+	})
+
+	assertFiltered(t, map[string]string{"catalog_ref": catalogBoardgame, "max_age": "1d"}, func(offers []model.Offer) error {
+		for _, offer := range offers {
+			if offer.GetCatalogRef() != 1 {
+				return fmt.Errorf("catalog filter found an offer of %d", offer.GetCatalogRef())
+			}
+		}
+		if len(offers) != 2 {
+			return fmt.Errorf("catalog filter found %d offers instead of 2", len(offers))
+		}
+		return nil
+	})
+}
+
+/* Tests GET Offers sorted by when they were added*/
+func TestGetOffersSorted(t *testing.T) {
+	assertFiltered(t, map[string]string{"sortBy": "added_at.asc"}, func(offers []model.Offer) error {
+		names := []string{"Catan Big Box", "Catan", "Seafarers"}
+		if len(offers) != len(names) {
+			return fmt.Errorf("listed %d offers instead of %d", len(offers), len(names))
+		}
+		for i, offer := range offers {
+			if offer.GetName() != names[i] {
+				return fmt.Errorf("offer %s listed in place of %s", offer.GetName(), names[i])
+			}
+		}
+		return nil
+	})
+}
+
+/* Tests GET Offers with filters and sorts outside of what the listing allows*/
+func TestGetOffersMalformed(t *testing.T) {
+	for _, query := range []map[string]string{
+		{"sortBy": "name.asc"},
+		{"sortBy": "added_at.asc;DROP TABLE offer"},
+		{"sortBy": "added_at.asc,added_at.desc"},
+		{"condition": "mint"},
+		{"type": "Miniature"},
+		{"max_age": "yesterday"},
+		{"catalog_ref": "0"},
+		{"currency": "EUR", "min_amount": "3000", "max_amount": "1000"},
+		{"q": "one two three four five six"},
+	} {
+		request := apitest.New().
+			HandlerFunc(router.ServeHTTP).
+			Get("/api/offer")
+		for key, value := range query {
+			request = request.Query(key, value)
+		}
+		request.Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	}
+}
+
+// Lists the Offers of the filterer with the query, checking the offers listed
+func assertFiltered(t *testing.T, query map[string]string, check func(offers []model.Offer) error) {
+	request := apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("username", filterer)
+	for key, value := range query {
+		request = request.Query(key, value)
+	}
+	request.Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			offers, err := decodeOffers(res)
+			if err != nil {
+				return err
+			}
+			return check(offers)
+		}).
+		End()
+}
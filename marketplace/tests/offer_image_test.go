@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests POST a photo of an Offer, stored as a jpeg with a single thumbnail*/
+func TestUploadOfferImage(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	photo := upload(t, seller, offer.GetId(), pngImage(800, 400), http.StatusOK)
+	if photo.Width != 800 || photo.Height != 400 {
+		t.Errorf("image stored as %dx%d instead of 800x400", photo.Width, photo.Height)
+	}
+	assertStored(t, photo, true)
+
+	getImage(t, "", photo.URL, http.StatusOK, nil)
+	getImage(t, "", photo.ThumbnailURL, http.StatusOK, func(img image.Image) error {
+		if side := img.Bounds().Dx(); side != model.ThumbnailSide {
+			return fmt.Errorf("thumbnail is %d wide instead of %d", side, model.ThumbnailSide)
+		}
+		return nil
+	})
+
+	assertImages(t, offer.GetId(), photo)
+}
+
+/* Tests POST photos that aren't images, or are too big to take*/
+func TestUploadOfferImageRejected(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	upload(t, seller, offer.GetId(), []byte("not an image"), http.StatusUnsupportedMediaType)
+	upload(t, seller, offer.GetId(), bytes.Repeat([]byte{0}, 12<<20), http.StatusRequestEntityTooLarge)
+	upload(t, seller, offer.GetId(), pngBomb(), http.StatusRequestEntityTooLarge)
+	upload(t, buyer, offer.GetId(), pngImage(10, 10), http.StatusNotFound)
+
+	assertImages(t, offer.GetId())
+}
+
+/* Tests GET the photos of a draft Offer, which only its seller sees*/
+func TestOfferImageDraft(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, model.OfferDraft))
+	photo := upload(t, seller, offer.GetId(), pngImage(10, 10), http.StatusOK)
+
+	getImage(t, "", photo.URL, http.StatusNotFound, nil)
+	getImage(t, buyer, photo.ThumbnailURL, http.StatusNotFound, nil)
+	getImage(t, seller, photo.URL, http.StatusOK, nil)
+}
+
+/* Tests GET the photos of a hidden Offer, which only its seller and moderators see*/
+func TestOfferImageHidden(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	photo := upload(t, seller, offer.GetId(), pngImage(10, 10), http.StatusOK)
+	moderate(t, header(admin), offer.GetId(), model.ModerationHide, http.StatusOK)
+
+	getImage(t, "", photo.URL, http.StatusNotFound, nil)
+	getImage(t, buyer, photo.ThumbnailURL, http.StatusNotFound, nil)
+	getImage(t, seller, photo.URL, http.StatusOK, nil)
+	getImage(t, admin, photo.ThumbnailURL, http.StatusOK, nil)
+}
+
+/* Tests DELETE a photo of an Offer, removing its files*/
+func TestDeleteOfferImage(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	photo := upload(t, seller, offer.GetId(), pngImage(10, 10), http.StatusOK)
+
+	deleteImage(t, buyer, photo, http.StatusNotFound)
+	assertStored(t, photo, true)
+
+	deleteImage(t, seller, photo, http.StatusNoContent)
+	assertStored(t, photo, false)
+	getImage(t, seller, photo.URL, http.StatusNotFound, nil)
+}
+
+/* Tests DELETE an Offer, removing the files of its photos only once it is deleted*/
+func TestDeleteOfferImageFiles(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	photo := upload(t, seller, offer.GetId(), pngImage(10, 10), http.StatusOK)
+
+	deleteOffer(t, buyer, offer.GetId(), http.StatusNotFound)
+	assertStored(t, photo, true)
+
+	deleteOffer(t, seller, offer.GetId(), http.StatusNoContent)
+	assertStored(t, photo, false)
+}
+
+/* Tests POST and PATCH Offers with a condition or delivery outside of what offers allow*/
+func TestOfferDetailsMalformed(t *testing.T) {
+	for body, status := range map[string]int{
+		`{"catalog_ref": ` + catalogBoardgame + `, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "mint", "shipping": true}`:                 http.StatusForbidden,
+		`{"catalog_ref": ` + catalogBoardgame + `, "name": "Catan", "amount": 2500, "currency": "EUR", "shipping": true}`:                                      http.StatusForbidden,
+		`{"catalog_ref": ` + catalogBoardgame + `, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good"}`:                                   http.StatusUnprocessableEntity,
+		`{"catalog_ref": ` + catalogBoardgame + `, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good", "pickup": true}`:                   http.StatusUnprocessableEntity,
+		`{"catalog_ref": ` + catalogBoardgame + `, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good", "pickup": true, "location": "\n"}`: http.StatusForbidden,
+	} {
+		apitest.New().
+			HandlerFunc(router.ServeHTTP).
+			Post("/api/offer").
+			JSON(body).
+			Header("Authorization", header(seller)).
+			Expect(t).
+			Status(status).
+			End()
+	}
+
+	offer := createOffer(t, seller, `{"catalog_ref": `+catalogBoardgame+`, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "like-new", "pickup": true, "location": "Lisbon"}`)
+	if offer.GetCondition() != model.ConditionLikeNew || offer.GetLocation() != "Lisbon" {
+		t.Errorf("offer created as %s in %s", offer.GetCondition(), offer.GetLocation())
+	}
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/offer/"+offer.GetId()).
+		JSON(`{"catalog_ref": `+catalogBoardgame+`, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "mint", "shipping": true}`).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+}
+
+// Uploads the image as the photo of the Offer as the user, expecting the status, returning the photo when stored
+func upload(t *testing.T, username, uuid string, content []byte, status int) model.OfferImage {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	var photo model.OfferImage
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+uuid+"/images").
+		Body(body.String()).
+		Header("Content-Type", form.FormDataContentType()).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		Assert(func(res *http.Response, req *http.Request) error {
+			if status != http.StatusOK {
+				return nil
+			}
+			return json.NewDecoder(res.Body).Decode(&photo)
+		}).
+		End()
+	return photo
+}
+
+// Fetches the image at the url as the user, or anonymously without one, expecting the status, checking the jpeg when given a check
+func getImage(t *testing.T, username, url string, status int, check func(img image.Image) error) {
+	request := apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get(url)
+	if username != "" {
+		request = request.Header("Authorization", header(username))
+	}
+	request.Expect(t).
+		Status(status).
+		Assert(func(res *http.Response, req *http.Request) error {
+			if check == nil {
+				return nil
+			}
+			img, err := jpeg.Decode(res.Body)
+			if err != nil {
+				return err
+			}
+			return check(img)
+		}).
+		End()
+}
+
+func deleteImage(t *testing.T, username string, photo model.OfferImage, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Delete(photo.URL).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+func deleteOffer(t *testing.T, username, uuid string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Delete("/api/offer/"+uuid).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Checks the Offer has the photos, oldest first
+func assertImages(t *testing.T, uuid string, photos ...model.OfferImage) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/" + uuid + "/images").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var images []model.OfferImage
+			if err := json.NewDecoder(res.Body).Decode(&images); err != nil {
+				return err
+			}
+			if len(images) != len(photos) {
+				return fmt.Errorf("offer has %d images instead of %d", len(images), len(photos))
+			}
+			for i, photo := range photos {
+				if images[i].GetId() != photo.GetId() {
+					return fmt.Errorf("image %s listed in place of %s", images[i].GetId(), photo.GetId())
+				}
+			}
+			return nil
+		}).
+		End()
+}
+
+// Checks the photo and its thumbnail are the only files stored for it, or that none are
+func assertStored(t *testing.T, photo model.OfferImage, stored bool) {
+	files, err := filepath.Glob(filepath.Join(imageDir, photo.GetId()+"*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{}
+	if stored {
+		expected = []string{filepath.Join(imageDir, photo.GetKey()), filepath.Join(imageDir, photo.GetThumbnailKey())}
+	}
+	if len(files) != len(expected) {
+		t.Errorf("image %s has %d files instead of %d", photo.GetId(), len(files), len(expected))
+		return
+	}
+	for _, file := range expected {
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			t.Errorf("file %s missing", file)
+		}
+	}
+}
+
+// Encodes a png of the size
+func pngImage(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+
+	var buffer bytes.Buffer
+	png.Encode(&buffer, img)
+	return buffer.Bytes()
+}
+
+// Encodes a tiny png whose header claims far more pixels than it has
+func pngBomb() []byte {
+	content := pngImage(1, 1)
+	binary.BigEndian.PutUint32(content[16:], 20000) // Width and height of the IHDR chunk
+	binary.BigEndian.PutUint32(content[20:], 20000)
+	binary.BigEndian.PutUint32(content[29:], crc32.ChecksumIEEE(content[12:29]))
+	return content
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests moving an Offer through its lifecycle, refusing the transitions it doesn't allow*/
+func TestOfferLifecycle(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	if offer.GetState() != model.OfferActive {
+		t.Errorf("offer created as %s", offer.GetState())
+	}
+
+	transition(t, seller, offer.GetId(), "reserve", http.StatusOK)
+	transition(t, seller, offer.GetId(), "reserve", http.StatusConflict)
+	transition(t, seller, offer.GetId(), "release", http.StatusOK)
+	transition(t, seller, offer.GetId(), "sell", http.StatusOK)
+	transition(t, seller, offer.GetId(), "withdraw", http.StatusConflict) // Sold offers are final
+}
+
+/* Tests the transitions of an Offer by someone other than its seller*/
+func TestOfferTransitionNotSeller(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	transition(t, buyer, offer.GetId(), "withdraw", http.StatusNotFound)
+}
+
+/* Tests PATCH an Offer once reserved, and by someone other than its seller*/
+func TestUpdateOfferNotEditable(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	updateOffer(t, buyer, offer.GetId(), http.StatusNotFound)
+	transition(t, seller, offer.GetId(), "reserve", http.StatusOK)
+	updateOffer(t, seller, offer.GetId(), http.StatusConflict)
+}
+
+/* Tests GET a draft Offer, which only its seller sees until published*/
+func TestDraftOfferVisibility(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, model.OfferDraft))
+
+	getOffer(t, "", offer.GetId(), http.StatusNotFound)
+	getOffer(t, buyer, offer.GetId(), http.StatusNotFound)
+	getOffer(t, seller, offer.GetId(), http.StatusOK)
+
+	transition(t, seller, offer.GetId(), "publish", http.StatusOK)
+	getOffer(t, "", offer.GetId(), http.StatusOK)
+}
+
+/* Tests GET Offers in a state that isn't public by someone other than their seller*/
+func TestGetOffersStateNotSeller(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("state", model.OfferDraft).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("state", model.OfferDraft).
+		Query("username", seller).
+		Header("Authorization", header(buyer)).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+}
+
+/* Tests GET every Offer of a seller, which are only the public ones unless they list their own*/
+func TestGetOffersAllStates(t *testing.T) {
+	draft := createOffer(t, seller, offerBody(catalogBoardgame, model.OfferDraft))
+
+	listAllStates(t, "", func(offers []model.Offer) error {
+		for _, offer := range offers {
+			if !model.IsPublicState(offer.GetState()) {
+				return fmt.Errorf("offer %s listed as %s", offer.GetId(), offer.GetState())
+			}
+		}
+		return nil
+	})
+
+	listAllStates(t, seller, func(offers []model.Offer) error { // Newest first
+		for _, offer := range offers {
+			if offer.GetId() == draft.GetId() {
+				return nil
+			}
+		}
+		return fmt.Errorf("draft %s not listed to its seller", draft.GetId())
+	})
+}
+
+// Moves the Offer with the action as the user, expecting the status
+func transition(t *testing.T, username, uuid, action string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+uuid+"/"+action).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Reprices the Offer as the user, expecting the status
+func updateOffer(t *testing.T, username, uuid string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/offer/"+uuid).
+		JSON(`{"catalog_ref": `+catalogBoardgame+`, "name": "Catan", "amount": 1500, "currency": "EUR", "condition": "good", "shipping": true}`).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Fetches the Offer as the user, or anonymously without one, expecting the status
+func getOffer(t *testing.T, username, uuid string, status int) {
+	request := apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/" + uuid)
+	if username != "" {
+		request = request.Header("Authorization", header(username))
+	}
+	request.Expect(t).
+		Status(status).
+		End()
+}
+
+// Lists every Offer of the seller in all states, as the user or anonymously without one, checking the offers listed
+func listAllStates(t *testing.T, username string, check func(offers []model.Offer) error) {
+	request := apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer").
+		Query("state", "all").
+		Query("username", seller)
+	if username != "" {
+		request = request.Header("Authorization", header(username))
+	}
+	request.Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			offers, err := decodeOffers(res)
+			if err != nil {
+				return err
+			}
+			return check(offers)
+		}).
+		End()
+}
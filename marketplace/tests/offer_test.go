@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests POST an Offer of a catalog boardgame, taking its type from the catalog*/
+func TestCreateOfferCatalogBoardgame(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	if offer.GetType() != model.OfferTypeBoardgame {
+		t.Errorf("offer of a boardgame has type %s", offer.GetType())
+	}
+}
+
+/* Tests POST an Offer of a catalog expansion, taking its type from the catalog*/
+func TestCreateOfferCatalogExpansion(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogExpansion, ""))
+
+	if offer.GetType() != model.OfferTypeExpansion {
+		t.Errorf("offer of an expansion has type %s", offer.GetType())
+	}
+}
+
+/* Tests POST an Offer of a boardgame missing from the catalog*/
+func TestCreateOfferCatalogMissing(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer").
+		JSON(offerBody("404", "")).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests POST an Offer whose type doesn't match its catalog entry*/
+func TestCreateOfferCatalogTypeMismatch(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer").
+		JSON(`{"catalog_ref": `+catalogExpansion+`, "type": "Boardgame", "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good", "shipping": true}`).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests PATCH an Offer into a boardgame missing from the catalog*/
+func TestUpdateOfferCatalogMissing(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/offer/"+offer.GetId()).
+		JSON(`{"catalog_ref": 404, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good", "shipping": true}`).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+// Body of an Offer of the catalog entry, listed in the state when one is given
+func offerBody(catalogRef, state string) string {
+	body := `{"catalog_ref": ` + catalogRef + `, "name": "Catan", "amount": 2500, "currency": "EUR", "condition": "good", "shipping": true`
+	if state != "" {
+		body += `, "state": "` + state + `"`
+	}
+	return body + `}`
+}
+
+// Creates the Offer as the user, returning it as listed
+func createOffer(t *testing.T, username, body string) model.Offer {
+	var offer model.Offer
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer").
+		JSON(body).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(decodeOffer(&offer)).
+		End()
+	return offer
+}
+
+// Reads the Offer answered
+func decodeOffer(offer *model.Offer) func(res *http.Response, req *http.Request) error {
+	return func(res *http.Response, req *http.Request) error {
+		if err := json.NewDecoder(res.Body).Decode(offer); err != nil {
+			return err
+		}
+		if offer.GetId() == "" {
+			return errors.New("offer uuid missing")
+		}
+		return nil
+	}
+}
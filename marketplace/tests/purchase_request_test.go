@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+// Second buyer, competing with the first on the same offers
+const bidder = "bidder"
+
+/* Tests negotiating a Purchase Request until the seller accepts it, reserving the Offer*/
+func TestPurchaseRequestNegotiation(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	request := requestOffer(t, buyer, offer.GetId(), `{"amount": 2000}`, http.StatusOK)
+
+	counter(t, seller, request, 2300, http.StatusOK)
+	counter(t, seller, request, 2250, http.StatusConflict) // The buyer's turn
+	counter(t, buyer, request, 2200, http.StatusOK)
+	answer(t, buyer, request, "accept", http.StatusForbidden)
+	answer(t, seller, request, "accept", http.StatusOK)
+
+	assertOfferState(t, offer.GetId(), model.OfferReserved)
+	assertRequests(t, buyer, offer.GetId(), func(requests []model.PurchaseRequest) error {
+		if len(requests) != 1 || requests[0].GetStatus() != model.RequestAccepted || requests[0].GetAmount() != 2200 {
+			return errors.New("buyer should see their request accepted at 2200")
+		}
+		if len(requests[0].History) != 4 {
+			return fmt.Errorf("negotiation has %d events instead of 4", len(requests[0].History))
+		}
+		return nil
+	})
+	requestOffer(t, bidder, offer.GetId(), `{}`, http.StatusConflict) // Reserved
+}
+
+/* Tests accepting a Purchase Request declines the others of the Offer*/
+func TestPurchaseRequestAcceptDeclinesOthers(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	accepted := requestOffer(t, buyer, offer.GetId(), `{}`, http.StatusOK)
+	declined := requestOffer(t, bidder, offer.GetId(), `{"amount": 2400}`, http.StatusOK)
+
+	answer(t, seller, accepted, "accept", http.StatusOK)
+
+	assertRequestStatus(t, offer.GetId(), declined.GetId(), model.RequestDeclined)
+	answer(t, seller, declined, "accept", http.StatusConflict)
+}
+
+/* Tests withdrawing an Offer declines its open Purchase Requests, which can no longer be negotiated*/
+func TestPurchaseRequestOfferWithdrawn(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	request := requestOffer(t, buyer, offer.GetId(), `{"amount": 2000}`, http.StatusOK)
+
+	transition(t, seller, offer.GetId(), "withdraw", http.StatusOK)
+
+	assertRequestStatus(t, offer.GetId(), request.GetId(), model.RequestDeclined)
+	counter(t, seller, request, 2300, http.StatusConflict)
+}
+
+/* Tests POST a Purchase Request on an Offer of the same user*/
+func TestPurchaseRequestOwnOffer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+
+	requestOffer(t, seller, offer.GetId(), `{}`, http.StatusForbidden)
+}
+
+/* Tests GET the Purchase Requests of an Offer, buyers only seeing their own*/
+func TestGetPurchaseRequestsBuyer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	requestOffer(t, buyer, offer.GetId(), `{}`, http.StatusOK)
+	requestOffer(t, bidder, offer.GetId(), `{}`, http.StatusOK)
+
+	assertRequests(t, seller, offer.GetId(), func(requests []model.PurchaseRequest) error {
+		if len(requests) != 2 {
+			return fmt.Errorf("seller sees %d requests instead of 2", len(requests))
+		}
+		return nil
+	})
+	assertRequests(t, bidder, offer.GetId(), func(requests []model.PurchaseRequest) error {
+		if len(requests) != 1 || requests[0].GetBuyer() != bidder {
+			return errors.New("bidder should only see their own request")
+		}
+		return nil
+	})
+}
+
+// Sends the Purchase Request of the body as the user, expecting the status, returning the request when created
+func requestOffer(t *testing.T, username, offerUuid, body string, status int) model.PurchaseRequest {
+	var request model.PurchaseRequest
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+offerUuid+"/requests").
+		JSON(body).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		Assert(func(res *http.Response, req *http.Request) error {
+			if status != http.StatusOK {
+				return nil
+			}
+			return json.NewDecoder(res.Body).Decode(&request)
+		}).
+		End()
+	return request
+}
+
+// Counters the Purchase Request with the amount as the user, expecting the status
+func counter(t *testing.T, username string, request model.PurchaseRequest, amount int64, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+request.GetOfferId()+"/requests/"+request.GetId()+"/counter").
+		JSON(`{"amount": `+strconv.FormatInt(amount, 10)+`}`).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Accepts or rejects the Purchase Request as the user, expecting the status
+func answer(t *testing.T, username string, request model.PurchaseRequest, action string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/offer/"+request.GetOfferId()+"/requests/"+request.GetId()+"/"+action).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Checks the Purchase Requests of the Offer the user sees
+func assertRequests(t *testing.T, username, offerUuid string, check func(requests []model.PurchaseRequest) error) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/"+offerUuid+"/requests").
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var requests []model.PurchaseRequest
+			if err := json.NewDecoder(res.Body).Decode(&requests); err != nil {
+				return err
+			}
+			return check(requests)
+		}).
+		End()
+}
+
+// Checks the seller sees the Purchase Request of the Offer in the status
+func assertRequestStatus(t *testing.T, offerUuid, requestUuid, status string) {
+	assertRequests(t, seller, offerUuid, func(requests []model.PurchaseRequest) error {
+		for _, request := range requests {
+			if request.GetId() == requestUuid {
+				if request.GetStatus() != status {
+					return fmt.Errorf("request %s is %s instead of %s", requestUuid, request.GetStatus(), status)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("request %s not listed", requestUuid)
+	})
+}
+
+// Checks anyone sees the Offer in the state
+func assertOfferState(t *testing.T, uuid, state string) {
+	var offer model.Offer
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/" + uuid).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(decodeOffer(&offer)).
+		End()
+	if offer.GetState() != state {
+		t.Errorf("offer %s is %s instead of %s", uuid, offer.GetState(), state)
+	}
+}
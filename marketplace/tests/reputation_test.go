@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests GET the Sale of an Offer sold through a Purchase Request, which only its seller and buyer see*/
+func TestGetSale(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	getSale(t, seller, offer.GetId(), http.StatusNotFound, nil) // Not sold yet
+
+	request := requestOffer(t, buyer, offer.GetId(), `{"amount": 2000}`, http.StatusOK)
+	answer(t, seller, request, "accept", http.StatusOK)
+	transition(t, seller, offer.GetId(), "sell", http.StatusOK)
+
+	for _, username := range []string{seller, buyer} {
+		getSale(t, username, offer.GetId(), http.StatusOK, func(sale model.Sale) error {
+			if sale.Seller != seller || sale.Buyer != buyer || sale.SoldAt.IsZero() {
+				return fmt.Errorf("sale of %s to %s instead of %s to %s", sale.Seller, sale.Buyer, seller, buyer)
+			}
+			return nil
+		})
+	}
+	getSale(t, bidder, offer.GetId(), http.StatusForbidden, nil)
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/" + offer.GetId() + "/sale").
+		Expect(t).
+		Status(http.StatusUnauthorized).
+		End()
+}
+
+/* Tests GET the Sale of an Offer sold without a Purchase Request, which has no buyer*/
+func TestGetSaleWithoutBuyer(t *testing.T) {
+	offer := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	transition(t, seller, offer.GetId(), "sell", http.StatusOK)
+
+	getSale(t, seller, offer.GetId(), http.StatusOK, func(sale model.Sale) error {
+		if sale.Buyer != "" {
+			return fmt.Errorf("sale to %s without a purchase request", sale.Buyer)
+		}
+		return nil
+	})
+	getSale(t, buyer, offer.GetId(), http.StatusForbidden, nil)
+}
+
+/* Tests Offers show the rating of their seller once synced from the rating service, null while unrated*/
+func TestOfferSellerRating(t *testing.T) {
+	rated := createOffer(t, ratedSeller, offerBody(catalogBoardgame, ""))
+	unrated := createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	if _, err := reputations.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSellerRating(t, rated.GetId(), 8.5, 4)
+	assertSellerRating(t, unrated.GetId(), -1, 0)
+
+	// Offers created afterwards show it right away
+	if offer := createOffer(t, ratedSeller, offerBody(catalogBoardgame, "")); offer.SellerRating == nil || *offer.SellerRating != 8.5 {
+		t.Errorf("offer created with seller rating %v instead of 8.5", offer.SellerRating)
+	}
+}
+
+/* Tests GET Offers of sellers rated at least the min_seller_rating, leaving out unrated sellers*/
+func TestGetOffersMinSellerRating(t *testing.T) {
+	createOffer(t, ratedSeller, offerBody(catalogBoardgame, ""))
+	createOffer(t, seller, offerBody(catalogBoardgame, ""))
+	if _, err := reputations.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	for minRating, sellers := range map[string][]string{
+		"8.5": {ratedSeller},
+		"9":   {},
+		"0":   {ratedSeller}, // Unrated sellers aren't rated 0
+	} {
+		for _, username := range []string{ratedSeller, seller} {
+			apitest.New().
+				HandlerFunc(router.ServeHTTP).
+				Get("/api/offer").
+				Query("username", username).
+				Query("min_seller_rating", minRating).
+				Expect(t).
+				Status(http.StatusOK).
+				Assert(func(res *http.Response, req *http.Request) error {
+					offers, err := decodeOffers(res)
+					if err != nil {
+						return err
+					}
+					listed := len(offers) > 0
+					expected := len(sellers) > 0 && sellers[0] == username
+					if listed != expected {
+						return fmt.Errorf("offers of %s listed is %t at a min seller rating of %s", username, listed, minRating)
+					}
+					return nil
+				}).
+				End()
+		}
+	}
+
+	for _, minRating := range []string{"-1", "10.5", "good"} {
+		apitest.New().
+			HandlerFunc(router.ServeHTTP).
+			Get("/api/offer").
+			Query("min_seller_rating", minRating).
+			Expect(t).
+			Status(http.StatusUnprocessableEntity).
+			End()
+	}
+}
+
+// Fetches the Sale of the Offer as the user, expecting the status, checking the sale when given a check
+func getSale(t *testing.T, username, uuid string, status int, check func(sale model.Sale) error) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/"+uuid+"/sale").
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		Assert(func(res *http.Response, req *http.Request) error {
+			if check == nil {
+				return nil
+			}
+			var sale model.Sale
+			if err := json.NewDecoder(res.Body).Decode(&sale); err != nil {
+				return err
+			}
+			return check(sale)
+		}).
+		End()
+}
+
+// Checks anyone sees the Offer with the rating of its seller, a negative rating meaning unrated
+func assertSellerRating(t *testing.T, uuid string, rating float64, count int64) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/offer/" + uuid).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var offer map[string]interface{}
+			if err := json.NewDecoder(res.Body).Decode(&offer); err != nil {
+				return err
+			}
+			if rating < 0 && offer["seller_rating"] != nil {
+				return fmt.Errorf("unrated seller rated %v", offer["seller_rating"])
+			}
+			if rating >= 0 && offer["seller_rating"] != rating {
+				return fmt.Errorf("seller rated %v instead of %v", offer["seller_rating"], rating)
+			}
+			if offer["seller_rating_count"] != float64(count) {
+				return fmt.Errorf("seller rated %v times instead of %d", offer["seller_rating_count"], count)
+			}
+			return nil
+		}).
+		End()
+}
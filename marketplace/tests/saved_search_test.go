@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"marketplace/model"
+
+	"github.com/steinfletcher/apitest"
+)
+
+// Buyers and game of the saved searches of these tests only, new on every run so earlier runs don't show up
+var (
+	watcher = "watcher" + run
+	other   = "other" + run
+	wanted  = "Wanted" + run // Word in the name of the offers searched
+)
+
+/* Tests listing and repricing Offers alerts exactly the saved searches they match*/
+func TestSavedSearchAlerts(t *testing.T) {
+	search := saveSearch(t, watcher, "q="+wanted+"&condition=new", 2000)
+	saveSearch(t, watcher, "q="+wanted+"&type=expansion", 5000)
+	saveSearch(t, other, "q="+wanted, 1000)
+
+	// Alerted once listed, and not again when repriced
+	listed := createOffer(t, seller, wantedBody(1500, model.OfferDraft))
+	assertAlerts(t, watcher, nil)
+	transition(t, seller, listed.GetId(), "publish", http.StatusOK)
+	reprice(t, listed.GetId(), 1200)
+
+	// Alerted once repriced under the max amount
+	repriced := createOffer(t, seller, wantedBody(2500, ""))
+	assertAlerts(t, watcher, []string{listed.GetId()})
+	reprice(t, repriced.GetId(), 1800)
+
+	createOffer(t, watcher, wantedBody(1500, "")) // Own offers never alert
+
+	alerts := assertAlerts(t, watcher, []string{listed.GetId(), repriced.GetId()})
+	for _, alert := range alerts {
+		if alert.SearchUuid != search.GetId() || alert.ConvertedCurrency != "EUR" {
+			t.Errorf("alert %d of search %s in %s", alert.Id, alert.SearchUuid, alert.ConvertedCurrency)
+		}
+	}
+	assertAlerts(t, other, nil)
+	assertAlerts(t, buyer, nil)
+}
+
+/* Tests marking alerts read, which only moves forward and only over alerts of the user*/
+func TestMarkAlertsRead(t *testing.T) {
+	reader := "reader" + run
+	saveSearch(t, reader, "q="+wanted, 5000)
+	createOffer(t, seller, wantedBody(1000, ""))
+	createOffer(t, seller, wantedBody(1100, ""))
+
+	assertRead(t, reader, false, false)
+	alerts := getAlerts(t, reader)
+	if len(alerts) != 2 {
+		return
+	}
+
+	markRead(t, reader, alerts[0].Id, http.StatusNoContent)
+	assertRead(t, reader, true, false)
+	markRead(t, reader, alerts[1].Id, http.StatusNoContent)
+	assertRead(t, reader, true, true)
+	markRead(t, reader, alerts[0].Id, http.StatusNoContent) // Doesn't mark the later one unread
+	assertRead(t, reader, true, true)
+
+	markRead(t, other, alerts[0].Id, http.StatusNotFound)
+	markRead(t, reader, 0, http.StatusForbidden)
+
+	createOffer(t, seller, wantedBody(1200, ""))
+	assertRead(t, reader, true, true, false)
+}
+
+// Body of an Offer of the game the saved searches want, listed in the state when one is given
+func wantedBody(amount int64, state string) string {
+	body := `{"catalog_ref": ` + catalogBoardgame + `, "name": "` + wanted + ` Catan", "amount": ` + strconv.FormatInt(amount, 10) + `, "currency": "EUR", "condition": "new", "shipping": true`
+	if state != "" {
+		body += `, "state": "` + state + `"`
+	}
+	return body + `}`
+}
+
+// Saves the search of the filter at most at the amount in EUR as the user, returning it
+func saveSearch(t *testing.T, username, filter string, maxAmount int64) model.SavedSearch {
+	var search model.SavedSearch
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/saved-searches").
+		JSON(`{"name": "Wanted", "filter": "`+filter+`", "max_amount": `+strconv.FormatInt(maxAmount, 10)+`, "currency": "EUR"}`).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			return json.NewDecoder(res.Body).Decode(&search)
+		}).
+		End()
+	return search
+}
+
+// Reprices the Offer of the seller to the amount in EUR
+func reprice(t *testing.T, uuid string, amount int64) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/offer/"+uuid).
+		JSON(wantedBody(amount, "")).
+		Header("Authorization", header(seller)).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+func markRead(t *testing.T, username string, lastId int64, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/alerts/read").
+		JSON(`{"last_id": `+strconv.FormatInt(lastId, 10)+`}`).
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Checks the inbox of the user has alerts of the offers, oldest first, returning the alerts
+func assertAlerts(t *testing.T, username string, offers []string) []model.Alert {
+	alerts := getAlerts(t, username)
+	if len(alerts) != len(offers) {
+		t.Errorf("%s has %d alerts instead of %d", username, len(alerts), len(offers))
+		return alerts
+	}
+	for i, alert := range alerts {
+		if alert.OfferUuid != offers[i] {
+			t.Errorf("alert of offer %s in place of %s", alert.OfferUuid, offers[i])
+		}
+	}
+	return alerts
+}
+
+// Checks the inbox of the user has alerts read as expected, oldest first
+func assertRead(t *testing.T, username string, read ...bool) {
+	alerts := getAlerts(t, username)
+	if len(alerts) != len(read) {
+		t.Errorf("%s has %d alerts instead of %d", username, len(alerts), len(read))
+		return
+	}
+	for i, alert := range alerts {
+		if alert.Read != read[i] {
+			t.Errorf("alert %d read is %t instead of %t", alert.Id, alert.Read, read[i])
+		}
+	}
+}
+
+// Fetches the inbox of the user, oldest first
+func getAlerts(t *testing.T, username string) []model.Alert {
+	var page struct {
+		Items []model.Alert `json:"items"`
+	}
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/alerts").
+		Query("limit", "100").
+		Header("Authorization", header(username)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			return json.NewDecoder(res.Body).Decode(&page)
+		}).
+		End()
+	return page.Items
+}
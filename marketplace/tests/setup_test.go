@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace/clients"
+	"marketplace/controllers"
+	"marketplace/database"
+	"marketplace/model"
+	"marketplace/repositories"
+	"marketplace/route"
+	"marketplace/services"
+	"marketplace/storage"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+var router *chi.Mux
+var oauthKey string
+var imageDir string
+var db *database.PostgresqlRepository
+var reputations *services.ReputationService
+
+// Suffix of the usernames and names the tests need new on every run, so earlier runs don't show up
+var run = strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+
+// Usernames the tests act as -> The admin is the only one listed in the admins, the moderator has the role claim
+const (
+	admin     = "admin"
+	moderator = "moderator"
+	seller    = "seller"
+	buyer     = "buyer"
+)
+
+// Seller the fake rating service has ratings of, new on every run so their offers are the only ones rated
+var ratedSeller = "rated" + run
+
+// Catalog entries the fake catalog knows -> Any other id is missing from it
+const (
+	catalogBoardgame = "1"
+	catalogExpansion = "2"
+)
+
+// Catalog boardgames only offered in the market tests, new on every run so earlier runs don't count in their stats
+var (
+	catalogSold   = strconv.FormatInt(1e9+time.Now().UnixNano()%1e9, 10)
+	catalogUnsold = strconv.FormatInt(2e9+time.Now().UnixNano()%1e9, 10)
+)
+
+// Prepares test environment
+func init() {
+	log.Println("Setup Starting")
+
+	// Set Database
+	var err error
+	db, err = database.Connect()
+	if err != nil {
+		log.Println("Error occurred while connecting to database")
+		return
+	}
+
+	// Fetch Oauth Key
+	key, oauthKeyPresent := os.LookupEnv("OAUTH_KEY")
+	if !oauthKeyPresent {
+		log.Println("Error occurred while fetching essential env variables")
+		return
+	}
+	oauthKey = key
+
+	// Images are kept in a temporary directory
+	imageDir, err = os.MkdirTemp("", "images")
+	if err != nil {
+		log.Println("Error occurred while creating the image directory")
+		return
+	}
+	imageStorage, err := storage.NewLocalStorage(imageDir)
+	if err != nil {
+		log.Println("Error occurred while preparing image storage")
+		return
+	}
+
+	// Fakes the catalog -> Only the boardgame and its expansion exist
+	catalog := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/boardgame/" + catalogBoardgame:
+			w.Write([]byte(`{"ID": 1, "name": "Catan", "publisher": "Kosmos"}`))
+		case "/api/boardgame/" + catalogExpansion:
+			w.Write([]byte(`{"ID": 2, "name": "Catan: Seafarers", "publisher": "Kosmos", "boardgame_id": 1}`))
+		case "/api/boardgame/" + catalogSold, "/api/boardgame/" + catalogUnsold:
+			w.Write([]byte(`{"ID": ` + strings.TrimPrefix(r.URL.Path, "/api/boardgame/") + `, "name": "Carcassonne", "publisher": "Hans im Glück"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	// Fakes the rating service -> Only the rated seller was rated
+	rating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var summaries []model.RatingSummary
+		for _, id := range strings.Split(r.URL.Query().Get("reference_ids"), ",") {
+			summary := model.RatingSummary{ReferenceNamespace: r.URL.Query().Get("reference_namespace"), ReferenceId: id}
+			if id == ratedSeller {
+				summary.Count, summary.Mean = 4, 8.5
+			}
+			summaries = append(summaries, summary)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summaries)
+	}))
+
+	// Fakes the user management service -> Only the users the tests act as are registered
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/user/" + admin, "/api/user/" + moderator, "/api/user/" + seller, "/api/user/" + buyer:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	// Set Repositories & Controllers & Services
+	repositories := repositories.InitRepositories(db)
+	catalogClient := clients.NewCatalogClient(catalog.URL, time.Second, time.Minute)
+	ratingClient := clients.NewRatingClient(rating.URL, time.Second)
+	userClient := clients.NewUserClient(users.URL, time.Second)
+	services := services.InitServices(repositories, catalogClient, ratingClient, userClient, imageStorage, 30*24*time.Hour)
+	controllers := controllers.InitControllers(services)
+	reputations = services.ReputationService
+
+	router = chi.NewRouter()
+
+	// Adds Routers
+	admins := []string{admin}
+	route.AddOfferRouter(router, oauthKey, admins, controllers.OfferController)
+	route.AddPurchaseRequestRouter(router, oauthKey, controllers.PurchaseRequestController)
+	route.AddOfferImageRouter(router, oauthKey, admins, controllers.OfferImageController)
+	route.AddExchangeRateRouter(router, oauthKey, admins, controllers.ExchangeRateController)
+	route.AddMarketRouter(router, controllers.MarketController)
+	route.AddSavedSearchRouter(router, oauthKey, controllers.SavedSearchController, controllers.AlertController)
+	route.AddModerationRouter(router, oauthKey, admins, controllers.ModerationController)
+
+	log.Println("Setup Complete")
+}
+
+// Creates the Bearer header of a verified user
+func header(username string) string {
+	return roleHeader(username, "user")
+}
+
+// Creates the Bearer header of a verified user with the role
+func roleHeader(username, role string) string {
+	token, err := oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(oauthKey))).CryptToken(&oauth.Token{
+		CreationDate: time.Now().UTC(),
+		ExpiresIn:    time.Hour,
+		Claims:       map[string]string{"username": username, "role": role, "email_verified": "true"},
+		TokenType:    oauth.BearerToken,
+	})
+	if err != nil {
+		log.Println("Error occurred while creating the token of " + username)
+	}
+	return "Bearer " + token
+}
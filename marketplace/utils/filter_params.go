@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+const (
+	maxSearchWords  = 5   // Most words a name search can have
+	maxSearchLength = 100 // Longest name search accepted
+	maxRating       = 10  // Highest value of a rating
+)
+
+// Main function of constructing the OfferFilter -> Validates every filter, search and sort of the listing query
+// The viewer is the user listing, who is the only one to see their own offers in every state
+func GetOfferFilter(query url.Values, viewer string) (*model.OfferFilter, error) {
+
+	own := viewer != "" && query.Get("username") == viewer
+	states, err := GetStates(query.Get("state"), own)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := GetCurrency(query.Get("currency"))
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := GetSort(query.Get("sortBy"), currency != "")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.OfferFilter{
+		States:   states,
+		Currency: currency,
+		Username: query.Get("username"),
+		Order:    order,
+	}
+
+	if filter.MinAmount, err = getAmount(query.Get("min_amount"), "min_amount"); err != nil {
+		return nil, err
+	}
+	if filter.MaxAmount, err = getAmount(query.Get("max_amount"), "max_amount"); err != nil {
+		return nil, err
+	}
+	if currency == "" && (filter.MinAmount != nil || filter.MaxAmount != nil) { // Raw amounts of different currencies don't compare
+		log.Println("Error - Amount range without a currency")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed amount range, min_amount and max_amount need a currency")
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		log.Println("Error - Amount range malformed, min_amount over max_amount")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed amount range, min_amount can't be over max_amount")
+	}
+
+	if filter.MinSellerRating, err = getRating(query.Get("min_seller_rating")); err != nil {
+		return nil, err
+	}
+
+	if filter.Conditions, err = getConditions(query.Get("condition")); err != nil {
+		return nil, err
+	}
+
+	if filter.Type, err = getType(query.Get("type")); err != nil {
+		return nil, err
+	}
+
+	if value := query.Get("catalog_ref"); value != "" {
+		catalogRef, err := getCatalogRef(value, "catalog_ref query parameter")
+		if err != nil {
+			return nil, err
+		}
+		filter.CatalogRef = &catalogRef
+	}
+
+	if filter.MaxAge, err = getAge(query.Get("max_age"), "max_age"); err != nil {
+		return nil, err
+	}
+
+	if filter.Search, err = getSearchWords(query.Get("q")); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Function that validates the ISO-4217 currency amounts are converted into, ignoring case
+func GetCurrency(value string) (string, error) {
+
+	if value == "" {
+		return "", nil
+	}
+
+	currency := strings.ToUpper(value)
+	if _, ok := model.GetMinorUnit(currency); !ok {
+		log.Println("Error - Currency not supported: " + value)
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed currency query parameter, should be a supported ISO-4217 code (E.g EUR)")
+	}
+	return currency, nil
+}
+
+// Function that parses an amount bound in minor units, which can't be negative
+func getAmount(value, name string) (*int64, error) {
+
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || amount < 0 {
+		log.Println("Error - Amount malformed: " + value)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed "+name+" query parameter, should be a positive number of minor units (E.g cents)")
+	}
+	return &amount, nil
+}
+
+// Function that parses a minimum average rating, on the 0 to 10 scale of the rating service
+func getRating(value string) (*float64, error) {
+
+	if value == "" {
+		return nil, nil
+	}
+
+	rating, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(rating) || rating < 0 || rating > maxRating {
+		log.Println("Error - Rating malformed: " + value)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed min_seller_rating query parameter, should be between 0 and 10")
+	}
+	return &rating, nil
+}
+
+// Function that parses the comma separated conditions offers can be in
+func getConditions(value string) ([]string, error) {
+
+	if value == "" {
+		return nil, nil
+	}
+
+	var conditions []string
+	for _, condition := range strings.Split(value, ",") {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+		if !model.IsCondition(condition) {
+			log.Println("Error - Offer condition malformed: " + value)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed condition query parameter, should be some of "+strings.Join(model.OfferConditions, ","))
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// Function that matches the type with one an Offer can have, ignoring case
+func getType(value string) (string, error) {
+
+	if value == "" {
+		return "", nil
+	}
+
+	for _, offerType := range []string{model.OfferTypeBoardgame, model.OfferTypeExpansion} {
+		if strings.EqualFold(value, offerType) {
+			return offerType, nil
+		}
+	}
+	log.Println("Error - Offer type malformed: " + value)
+	return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed type query parameter, should be "+model.OfferTypeBoardgame+" or "+model.OfferTypeExpansion)
+}
+
+// Function that parses the id of a catalog boardgame
+func getCatalogRef(value, name string) (uint, error) {
+
+	ref, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || ref == 0 {
+		log.Println("Error - Catalog ref malformed: " + value)
+		return 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed "+name+", should be a boardgame id")
+	}
+	return uint(ref), nil
+}
+
+// Function that parses an age as days (E.g 7d) or a duration (E.g 12h, 30m)
+func getAge(value, name string) (time.Duration, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+
+	var age time.Duration
+	var err error
+	if days, found := strings.CutSuffix(value, "d"); found {
+		var count int
+		if count, err = strconv.Atoi(days); err == nil {
+			age = time.Duration(count) * 24 * time.Hour
+		}
+	} else {
+		age, err = time.ParseDuration(value)
+	}
+
+	if err != nil || age <= 0 {
+		log.Println("Error - Age malformed: " + value)
+		return 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed "+name+" query parameter, should be days (E.g 7d) or a duration (E.g 12h)")
+	}
+	return age, nil
+}
+
+// Function that splits the search into the words the name must contain
+func getSearchWords(value string) ([]string, error) {
+
+	if len(value) > maxSearchLength {
+		log.Println("Error - Search too long: " + value)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, should have at most "+strconv.Itoa(maxSearchLength)+" characters")
+	}
+
+	words := strings.Fields(value)
+	if len(words) > maxSearchWords {
+		log.Println("Error - Search has too many words: " + value)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed q query parameter, should have at most "+strconv.Itoa(maxSearchWords)+" words")
+	}
+	return words, nil
+}
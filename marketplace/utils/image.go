@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+
+	_ "image/gif" // Registers the decoders of the accepted formats
+	_ "image/png"
+
+	"marketplace/middleware"
+)
+
+const (
+	maxImagePixels = 40_000_000 // Biggest image decoded, guarding memory against decompression bombs
+	jpegQuality    = 85
+)
+
+// Function that decodes a jpeg, png or gif, checking its size before decoding the pixels
+func DecodeImage(content io.ReadSeeker) (image.Image, error) {
+
+	config, format, err := image.DecodeConfig(content)
+	if err != nil {
+		log.Println("Error - Image not decodable: " + err.Error())
+		return nil, middleware.NewError(http.StatusUnsupportedMediaType, "Image should be a jpeg, png or gif")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		log.Println("Error - Image too big to decode")
+		return nil, middleware.NewError(http.StatusRequestEntityTooLarge, "Image has too many pixels")
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(content)
+	if err != nil {
+		log.Println("Error - Image of format " + format + " malformed: " + err.Error())
+		return nil, middleware.NewError(http.StatusUnsupportedMediaType, "Image is malformed")
+	}
+	return img, nil
+}
+
+// Function that fits the image into a square of the side, keeping its proportions, over a white background
+// Every pixel of the result averages the area of the source it covers, so downscaling doesn't alias
+func FitImage(src image.Image, side int) *image.RGBA {
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > side || height > side {
+		if width >= height {
+			width, height = side, max(1, height*side/bounds.Dx())
+		} else {
+			width, height = max(1, width*side/bounds.Dy()), side
+		}
+	}
+
+	// Transparent images would turn black once encoded as jpeg
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, src, bounds.Min, draw.Over)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return flat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					offset := flat.PixOffset(sx, sy)
+					r += uint64(flat.Pix[offset])
+					g += uint64(flat.Pix[offset+1])
+					b += uint64(flat.Pix[offset+2])
+					count++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / count), G: uint8(g / count), B: uint8(b / count), A: 0xff})
+		}
+	}
+	return dst
+}
+
+// Function that encodes the image as jpeg, which drops any metadata of the upload (E.g its GPS location)
+func EncodeJPEG(img image.Image) ([]byte, error) {
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		log.Println("Error encoding image: " + err.Error())
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
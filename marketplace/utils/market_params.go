@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+const (
+	defaultMarketCurrency = "EUR"
+	defaultMarketPeriod   = 90 * 24 * time.Hour
+	maxMarketPeriod       = 2 * 365 * 24 * time.Hour // Longest period the trend covers, so day buckets stay a few hundred
+	defaultMarketBucket   = model.MarketBucketWeek
+)
+
+// Main function of constructing the MarketQuery -> Validates the boardgame id and the currency, period and bucket of the stats
+func GetMarketQuery(id string, query url.Values) (*model.MarketQuery, error) {
+
+	catalogRef, err := getCatalogRef(id, "boardgame id")
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := GetCurrency(query.Get("currency"))
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = defaultMarketCurrency
+	}
+
+	period, err := getAge(query.Get("period"), "period")
+	if err != nil {
+		return nil, err
+	}
+	if period == 0 {
+		period = defaultMarketPeriod
+	}
+	if period > maxMarketPeriod {
+		log.Println("Error - Period too long: " + query.Get("period"))
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed period query parameter, should be at most 730d")
+	}
+
+	bucket := strings.ToLower(query.Get("bucket"))
+	if bucket == "" {
+		bucket = defaultMarketBucket
+	}
+	if !model.IsMarketBucket(bucket) {
+		log.Println("Error - Bucket malformed: " + bucket)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed bucket query parameter, should be one of "+strings.Join(model.MarketBuckets, ","))
+	}
+
+	return &model.MarketQuery{
+		CatalogRef: catalogRef,
+		Currency:   currency,
+		Period:     period,
+		Bucket:     bucket,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"marketplace/middleware"
+
+	"github.com/asaskevich/govalidator"
+)
+
+const defaultMinReports = 3 // Open reports an Offer needs to get into the moderation queue
+
+// Function that parses how many open reports the offers of the moderation queue need at least
+func GetMinReports(value string) (int, error) {
+
+	if value == "" {
+		return defaultMinReports, nil
+	}
+
+	minReports, err := strconv.Atoi(value)
+	if err != nil || minReports < 1 {
+		log.Println("Error - Minimum reports malformed: " + value)
+		return 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed min_reports query parameter, should be a positive number")
+	}
+	return minReports, nil
+}
+
+// Function that validates the Offer the moderation audit is narrowed to, if any
+func GetOfferUuid(value string) (string, error) {
+
+	if value != "" && !govalidator.IsUUID(value) {
+		log.Println("Error - Offer uuid malformed: " + value)
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed offer_uuid query parameter, should be an Offer id")
+	}
+	return value, nil
+}
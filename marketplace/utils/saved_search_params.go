@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+// Listing query parameters a saved search can filter by, the max amount and currency being its own
+var savedSearchParams = map[string]bool{"q": true, "type": true, "catalog_ref": true, "condition": true}
+
+// Function that validates the filter of a saved search, written as listing query parameters (E.g q=catan&condition=new)
+func GetSavedSearchFilter(expression string) (*model.OfferFilter, error) {
+
+	query, err := url.ParseQuery(expression)
+	if err != nil {
+		log.Println("Error - Saved search filter malformed: " + expression)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filter, should be listing query parameters (E.g q=catan&condition=new)")
+	}
+
+	for param := range query {
+		if !savedSearchParams[param] {
+			log.Println("Error - Saved search filter has an unknown parameter: " + param)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filter, can only have the q, type, catalog_ref and condition query parameters")
+		}
+	}
+
+	filter, err := GetOfferFilter(query, "") // Alerts are about offers anyone can see
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.CatalogRef == nil && filter.Type == "" && len(filter.Conditions) == 0 && len(filter.Search) == 0 {
+		log.Println("Error - Saved search filter is empty: " + expression)
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed filter, should narrow the offers down with at least one query parameter")
+	}
+	return filter, nil
+}
+
+// Function that parses the id of the last alert read, the ones after it being fetched
+func GetAfter(value string) (int64, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+
+	after, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || after < 0 {
+		log.Println("Error - Alert id malformed: " + value)
+		return 0, middleware.NewError(http.StatusUnprocessableEntity, "Malformed after query parameter, should be the id of the last alert read")
+	}
+	return after, nil
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"marketplace/middleware"
+)
+
+const (
+	sortSeparator = ","                  // Sorts separated by a comma are applied in order
+	defaultSort   = "added_at desc"      // Newest offers first
+	tieBreaker    = "uuid asc"           // Unique column ending every order, so keyset cursors are stable
+	sortFields    = "amount or added_at" // Fields named in errors
+)
+
+// Columns offers can be sorted by -> Only these reach the query, never the raw parameter
+var sortableColumns = map[string]string{
+	"amount":   "amount",
+	"price":    "amount",
+	"added_at": "added_at",
+	"addedat":  "added_at",
+}
+
+// Main function of constructing the Sort -> Ends on the uuid so that entries with equal values keep a deterministic order
+// Amounts are only sorted once converted into a currency, so offers of different currencies compare
+func GetSort(sortBy string, converted bool) (string, error) {
+
+	if sortBy == "" {
+		return defaultSort + ", " + tieBreaker, nil
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for _, sort := range strings.Split(sortBy, sortSeparator) {
+		splits := strings.Split(sort, ".")
+		if len(splits) != 2 {
+			log.Println("Error - Sort malformed: " + sortBy)
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, should be field.order")
+		}
+
+		column, ok := sortableColumns[strings.ToLower(splits[0])]
+		if !ok {
+			log.Println("Error - Sort field unknown: " + sortBy)
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, field should be "+sortFields)
+		}
+		if column == "amount" { // Raw amounts of different currencies don't compare
+			if !converted {
+				log.Println("Error - Sort by amount without a currency: " + sortBy)
+				return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, sorting by amount needs a currency")
+			}
+			column = "converted_amount"
+		}
+
+		order := strings.ToLower(splits[1])
+		if order != "asc" && order != "desc" {
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, order should be asc or desc")
+		}
+
+		if seen[column] { // Validate if the field was already used
+			log.Println("Error - Sort field repeated: " + column)
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, fields can't be repeated")
+		}
+		seen[column] = true
+		columns = append(columns, column+" "+order)
+	}
+
+	return strings.Join(append(columns, tieBreaker), ", "), nil
+
+	// Examples of sorts that work:
+	// added_at.asc                          --->  added_at asc, uuid asc
+	// amount.asc (converted)                --->  converted_amount asc, uuid asc
+	// amount.desc,added_at.asc (converted)  --->  converted_amount desc, added_at asc, uuid asc
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"marketplace/middleware"
+	"marketplace/model"
+)
+
+const allStates = "all" // Lists offers in any state the user can see
+
+// Function that parses the comma separated states to list -> Only active offers when none is given
+// Only sellers listing their own offers see them in states that aren't public, all meaning the public ones otherwise
+func GetStates(value string, own bool) ([]string, error) {
+
+	if value == "" {
+		return []string{model.OfferActive}, nil
+	}
+	if value == allStates {
+		if !own {
+			return model.PublicOfferStates, nil
+		}
+		return model.OfferStates, nil
+	}
+
+	var states []string
+	for _, state := range strings.Split(value, ",") {
+		state = strings.ToLower(strings.TrimSpace(state))
+		if !model.IsOfferState(state) {
+			log.Println("Error - Offer state malformed: " + value)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed state query parameter, should be all or some of "+strings.Join(model.OfferStates, ","))
+		}
+		if !own && !model.IsPublicState(state) {
+			log.Println("Error - Offer state " + state + " listed by someone other than the seller")
+			return nil, middleware.NewError(http.StatusForbidden, "Offers can only be listed as "+state+" by their seller, filtering by your own username")
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
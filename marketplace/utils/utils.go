@@ -31,3 +31,11 @@ func GetUsernameFromToken(r *http.Request) (string, error) {
 
 	return "", middleware.NewError(http.StatusInternalServerError, "Error - Username not present")
 }
+
+func GetAccessToken(r *http.Request) (string, error) {
+	token, ok := r.Context().Value(oauth.AccessTokenContext).(string)
+	if !ok || token == "" {
+		return "", middleware.NewError(http.StatusUnauthorized, "Error - Access token not present")
+	}
+	return token, nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// Registers the validation tags of free text, which alphanum is too strict for
+func init() {
+	govalidator.TagMap["text"] = govalidator.Validator(IsText)
+	govalidator.TagMap["paragraph"] = govalidator.Validator(IsParagraph)
+}
+
+// Usernames as user-management registers them -> 3 to 30 ASCII letters, digits, dots, dashes or underscores
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,30}$`)
+
+// IsUsername checks the string could be the username of a user
+func IsUsername(str string) bool {
+	return usernamePattern.MatchString(str)
+}
+
+// IsText checks the string is a single line of printable text -> Letters, digits, punctuation, symbols and spaces
+func IsText(str string) bool {
+	for _, r := range str {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsParagraph checks the string is printable text that can span several lines
+func IsParagraph(str string) bool {
+	for _, r := range str {
+		if r != '\n' && r != '\r' && r != '\t' && !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
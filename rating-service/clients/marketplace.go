@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"rating-service/middleware"
+	"rating-service/model"
+)
+
+const offerPath = "/api/offer/"
+
+// MarketplaceClient reads the sales of the marketplace service, as the user asking for them
+type MarketplaceClient struct {
+	url    string
+	client *http.Client
+}
+
+// Function that creates a client for the marketplace at baseURL, giving up on each request after timeout
+func NewMarketplaceClient(baseURL string, timeout time.Duration) *MarketplaceClient {
+	return &MarketplaceClient{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Method that fetches the sale of the offer with the authorization of the user -> The marketplace only shows it to its seller and buyer
+func (mc *MarketplaceClient) GetSale(offerId, authorization string) (model.Sale, error) {
+
+	req, err := http.NewRequest(http.MethodGet, mc.url+offerPath+url.PathEscape(offerId)+"/sale", nil)
+	if err != nil {
+		return model.Sale{}, err
+	}
+	req.Header.Set("Authorization", authorization)
+
+	res, err := mc.client.Do(req)
+	if err != nil {
+		log.Println("Error - Failed to reach the marketplace: " + err.Error())
+		return model.Sale{}, middleware.NewError(http.StatusServiceUnavailable, "Marketplace unavailable")
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return model.Sale{}, middleware.NewError(http.StatusUnprocessableEntity, "No sold offer with id: "+offerId)
+	case http.StatusForbidden:
+		return model.Sale{}, middleware.NewError(http.StatusForbidden, "Only the buyer of the offer can rate its seller")
+	default:
+		log.Println("Error - Marketplace answered with status " + strconv.Itoa(res.StatusCode))
+		return model.Sale{}, middleware.NewError(http.StatusBadGateway, "Marketplace failed")
+	}
+
+	var sale model.Sale
+	if err := json.NewDecoder(res.Body).Decode(&sale); err != nil {
+		log.Println("Error - Failed to decode marketplace response: " + err.Error())
+		return model.Sale{}, middleware.NewError(http.StatusBadGateway, "Marketplace answered with an invalid sale")
+	}
+	return sale, nil
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"rating-service/middleware"
 	"rating-service/model"
@@ -12,10 +13,15 @@ import (
 )
 
 type ratingService interface {
-	Create(rating *model.Rating) error
-	GetAll(sort string) ([]model.Rating, error)
+	Create(rating *model.Rating, authorization string) error
+	GetAll(sort string, page *model.Page) ([]model.Rating, error)
 	Get(id string) (model.Rating, error)
-	Delete(id string) error
+	Update(input *model.RatingUpdate, id, username string) (model.Rating, error)
+	Delete(id, username string) error
+	GetSummary(namespace, id string) (model.Summary, error)
+	GetSummaries(namespace string, ids []string) ([]model.Summary, error)
+	GetRankings(namespace string, page *model.Page) ([]model.Ranking, error)
+	GetRankingsByIds(namespace string, ids []string) ([]model.Ranking, error)
 }
 
 type RatingController struct {
@@ -30,7 +36,7 @@ func InitRatingController(ratingSvc *services.RatingService) *RatingController {
 }
 
 // Create Rating godoc
-// @Summary 	Creates a Rating using model
+// @Summary 	Creates a Rating using model, as the token's user -> Rating the same object again replaces the value, and sellers (user namespace) need the bought offer as proof
 // @Tags 		ratings
 // @Produce 	json
 // @Param 		data body model.Rating true "The Rating model"
@@ -46,13 +52,22 @@ func (controller *RatingController) Create(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Get username from oauth Token -> Nobody rates as someone else
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	rating.SetUsername(user)
+
 	// Validate Rating input
 	if err := utils.ValidateStruct(&rating); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	if err := controller.service.Create(&rating); err != nil {
+	// Sellers are rated with the token of the buyer, which the marketplace checks the proof with
+	if err := controller.service.Create(&rating, r.Header.Get("Authorization")); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
@@ -64,8 +79,12 @@ func (controller *RatingController) Create(w http.ResponseWriter, r *http.Reques
 // @Summary 	Fetches all Ratings
 // @Tags 		ratings
 // @Produce 	json
+// @Param 		sortBy query string  false  "Sort using field.order, joined by , for several fields (E.g publisher.asc,name.asc)"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
 // @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
-// @Success 	200 {object} model.Rating
+// @Success 	200 {object} model.Paginated
 // @Router 		/rating [get]
 func (controller *RatingController) GetAll(w http.ResponseWriter, r *http.Request) {
 
@@ -76,12 +95,18 @@ func (controller *RatingController) GetAll(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ratings, err := controller.service.GetAll(sort)
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	ratings, err := controller.service.GetAll(sort, page)
 	if err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
-	render.New().JSON(w, http.StatusOK, ratings)
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(ratings, page))
 }
 
 // Get Rating godoc
@@ -104,8 +129,49 @@ func (controller *RatingController) Get(w http.ResponseWriter, r *http.Request)
 	render.New().JSON(w, http.StatusOK, rating)
 }
 
+// Update Rating godoc
+// @Summary 	Updates the value of a specific Rating, only by its owner
+// @Tags 		ratings
+// @Produce 	json
+// @Param 		id path string true "The Rating id"
+// @Param 		data body model.RatingUpdate true "The new value"
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 	200 {object} model.Rating
+// @Router 		/rating/{id} [patch]
+func (controller *RatingController) Update(w http.ResponseWriter, r *http.Request) {
+
+	// Deserialize input
+	var input model.RatingUpdate
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	id := utils.GetFieldFromURL(r, "id")
+
+	rating, err := controller.service.Update(&input, id, user)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	render.New().JSON(w, http.StatusOK, rating)
+}
+
 // Delete Rating godoc
-// @Summary 	Deletes a specific Rating
+// @Summary 	Deletes a specific Rating, only by its owner
 // @Tags 		ratings
 // @Produce 	json
 // @Param 		id path string true "The Rating id"
@@ -114,13 +180,130 @@ func (controller *RatingController) Get(w http.ResponseWriter, r *http.Request)
 // @Router 		/rating/{id} [delete]
 func (controller *RatingController) Delete(w http.ResponseWriter, r *http.Request) {
 
+	// Get username from oauth Token
+	user, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
 	id := utils.GetFieldFromURL(r, "id")
 
 	// Delete by id
-	if err := controller.service.Delete(id); err != nil {
+	if err := controller.service.Delete(id, user); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
 	render.New().JSON(w, http.StatusNoContent, id)
 }
+
+// Get Rating Summary godoc
+// @Summary 	Fetches the count, mean, median, standard deviation and histogram of the Ratings of an object
+// @Tags 		ratings
+// @Produce 	json
+// @Param 		reference_namespace query string  true  "The namespace of the rated object (E.g boardgame)"
+// @Param 		reference_id query string  true  "The id of the rated object"
+// @Success 	200 {object} model.Summary
+// @Router 		/rating/summary [get]
+func (controller *RatingController) GetSummary(w http.ResponseWriter, r *http.Request) {
+
+	id := r.URL.Query().Get("reference_id")
+	if strings.Contains(id, ",") { // A single object -> Batches have their own endpoint
+		middleware.ErrorHandler(w, middleware.NewError(http.StatusUnprocessableEntity, "Malformed reference_id query parameter, use the batch summary for several ids"))
+		return
+	}
+
+	namespace, ids, err := utils.GetReferences(r.URL.Query().Get("reference_namespace"), id)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	summary, err := controller.service.GetSummary(namespace, ids[0])
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	render.New().JSON(w, http.StatusOK, summary)
+}
+
+// Get Rating Summaries godoc
+// @Summary 	Fetches the Summary of the Ratings of several objects of a namespace, in the given order
+// @Tags 		ratings
+// @Produce 	json
+// @Param 		reference_namespace query string  true  "The namespace of the rated objects (E.g boardgame)"
+// @Param 		reference_ids query string  true  "Comma separated ids of the rated objects (max 100)"
+// @Success 	200 {array} model.Summary
+// @Router 		/rating/summary/batch [get]
+func (controller *RatingController) GetSummaries(w http.ResponseWriter, r *http.Request) {
+
+	namespace, ids, err := utils.GetReferences(r.URL.Query().Get("reference_namespace"), r.URL.Query().Get("reference_ids"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	summaries, err := controller.service.GetSummaries(namespace, ids)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	render.New().JSON(w, http.StatusOK, summaries)
+}
+
+// Get Rankings godoc
+// @Summary 	Fetches the objects of a namespace ordered by their Bayesian average score, best first
+// @Tags 		ratings
+// @Produce 	json
+// @Param 		reference_namespace query string  true  "The namespace of the rated objects (E.g boardgame)"
+// @Param 		limit query int  false  "Number of entries per page (default 20, max 100)"
+// @Param 		offset query int  false  "Number of entries to skip"
+// @Param 		cursor query string  false  "The next_cursor of the previous page"
+// @Success 	200 {object} model.Paginated
+// @Router 		/rating/ranking [get]
+func (controller *RatingController) GetRankings(w http.ResponseWriter, r *http.Request) {
+
+	namespace, err := utils.GetNamespace(r.URL.Query().Get("reference_namespace"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	page, err := utils.GetPage(r.URL.Query().Get("limit"), r.URL.Query().Get("offset"), r.URL.Query().Get("cursor"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	rankings, err := controller.service.GetRankings(namespace, page)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	render.New().JSON(w, http.StatusOK, model.NewPaginated(rankings, page))
+}
+
+// Get Rankings of objects godoc
+// @Summary 	Fetches the Bayesian average score of several objects of a namespace, in the given order
+// @Tags 		ratings
+// @Produce 	json
+// @Param 		reference_namespace query string  true  "The namespace of the rated objects (E.g boardgame)"
+// @Param 		reference_ids query string  true  "Comma separated ids of the rated objects (max 100)"
+// @Success 	200 {array} model.Ranking
+// @Router 		/rating/ranking/batch [get]
+func (controller *RatingController) GetRankingsByIds(w http.ResponseWriter, r *http.Request) {
+
+	namespace, ids, err := utils.GetReferences(r.URL.Query().Get("reference_namespace"), r.URL.Query().Get("reference_ids"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	rankings, err := controller.service.GetRankingsByIds(namespace, ids)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	render.New().JSON(w, http.StatusOK, rankings)
+}
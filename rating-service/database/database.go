@@ -35,6 +35,7 @@ func Connect(isTest bool) (*PostgresqlRepository, error) {
 	log.Println("Connected to the Database")
 
 	migrate(db, &model.Rating{})
+	migrate(db, &model.Ranking{})
 
 	log.Println("Database Migration Completed")
 
@@ -91,32 +92,79 @@ func (instance *PostgresqlRepository) Create(value interface{}, omits ...string)
 	return nil
 }
 
-func (instance *PostgresqlRepository) Read(value interface{}, sort, search, identifier string) error {
+// Method that creates the entry unless it conflicts with a unique one, telling if it was created -> A concurrent insert is waited for instead of failing
+func (instance *PostgresqlRepository) CreateIfAbsent(value interface{}) (bool, error) {
 
-	var result *gorm.DB
-	if isSliceOrArray(value) {
-		if search == "" {
-			result = instance.db.Preload(clause.Associations).Order(sort).Find(value) // Find all with sort and NO filters
-		} else {
-			result = instance.db.Preload(clause.Associations).Order(sort).Find(value, search, identifier) // Find all with filters and sort
-		}
+	result := instance.db.Clauses(clause.OnConflict{DoNothing: true}).Create(value)
+	if result.Error != nil {
+		log.Println("Error while creating a database entry: " + fmt.Sprintf("%v", value))
+		return false, result.Error
+	}
+
+	log.Println("Created database entries: " + fmt.Sprintf("%d", result.RowsAffected))
+	return result.RowsAffected > 0, nil
+}
+
+// Method that reads one entry or, when value is a slice, all entries -> When a page is given only that page is read
+func (instance *PostgresqlRepository) Read(value interface{}, page *model.Page, sort, search, identifier string) error {
+
+	query := instance.db
+	if search != "" {
+		query = query.Where(search, identifier).Session(&gorm.Session{}) // Session allows reusing the filters for counting and finding
+	}
+
+	var err error
+	if !isSliceOrArray(value) {
+		err = query.Preload(clause.Associations).First(value).Error // Find 1 Specific
+	} else if page == nil {
+		err = query.Preload(clause.Associations).Order(sort).Find(value).Error // Find all with filters and sort
 	} else {
-		result = instance.db.Preload(clause.Associations).First(value, search, identifier) // Find 1 Specific
+		err = instance.readPage(query, value, page, sort) // Find a page with filters and sort
 	}
 
-	if result.Error != nil {
+	if err != nil {
 		log.Println("Error while reading a database entry: " + search + " " + identifier)
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Println("Error Record not found: " + search + " " + identifier)
 			return middleware.NewError(http.StatusNotFound, "Record not found")
 		}
-		return result.Error
+		return err
 	}
 
 	log.Println("Fetched database entry: " + fmt.Sprintf("%v", value))
 	return nil
 }
 
+// Method that runs a raw query, scanning its rows into value -> Only for what the generic methods can't express (E.g Aggregations)
+func (instance *PostgresqlRepository) Raw(value interface{}, query string, values ...interface{}) error {
+
+	if err := instance.db.Raw(query, values...).Scan(value).Error; err != nil {
+		log.Println("Error while running a raw query: " + query)
+		return err
+	}
+
+	log.Println("Fetched raw query: " + fmt.Sprintf("%v", value))
+	return nil
+}
+
+// Method that runs a raw statement -> Only for what the generic methods can't express (E.g Upserts)
+func (instance *PostgresqlRepository) Exec(query string, values ...interface{}) error {
+
+	if err := instance.db.Exec(query, values...).Error; err != nil {
+		log.Println("Error while running a raw statement: " + query)
+		return err
+	}
+	return nil
+}
+
+// Method that runs fn in a transaction -> Everything fn does through tx is committed together or rolled back on error
+func (instance *PostgresqlRepository) Transaction(fn func(tx *PostgresqlRepository) error) error {
+
+	return instance.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&PostgresqlRepository{tx})
+	})
+}
+
 func (instance *PostgresqlRepository) Update(value interface{}, omits ...string) error {
 	result := instance.db.Omit(omits...).Save(value)
 	if result.Error != nil {
@@ -139,6 +187,9 @@ func (instance *PostgresqlRepository) Delete(value interface{}) error {
 		}
 		return result.Error
 	}
+	if result.RowsAffected == 0 { // Deleted meanwhile
+		return middleware.NewError(http.StatusNotFound, "Record Not found")
+	}
 
 	log.Println("Deleted database entry: " + fmt.Sprintf("%v", value))
 	return nil
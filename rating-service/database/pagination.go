@@ -0,0 +1,203 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"rating-service/middleware"
+	"rating-service/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var schemaCache = &sync.Map{}
+
+// Column of the listing order and its direction
+type keysetColumn struct {
+	field *schema.Field
+	desc  bool
+}
+
+// Position right after the last entry of a page, tied to the order it was created with
+type cursor struct {
+	Order  string            `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// Method that fetches one page of the query ordered by sort and a primary key tie-breaker, so that keyset cursors are stable
+func (instance *PostgresqlRepository) readPage(query *gorm.DB, value interface{}, page *model.Page, sort string) error {
+
+	modelSchema, err := schema.Parse(value, schemaCache, instance.db.NamingStrategy)
+	if err != nil {
+		log.Println("Error while parsing model schema: " + err.Error())
+		return err
+	}
+
+	columns, err := getKeysetColumns(modelSchema, sort)
+	if err != nil {
+		return err
+	}
+	order := constructOrder(columns)
+
+	var total int64
+	if err := query.Model(value).Count(&total).Error; err != nil {
+		log.Println("Error while counting database entries: " + err.Error())
+		return err
+	}
+	page.SetTotal(total)
+
+	find := query.Preload(clause.Associations).Order(order)
+	if page.GetCursor() != "" {
+		search, identifiers, err := decodeCursor(page.GetCursor(), order, columns)
+		if err != nil {
+			return err
+		}
+		find = find.Where(search, identifiers...)
+	}
+
+	if err := find.Offset(page.GetOffset()).Limit(page.GetLimit() + 1).Find(value).Error; err != nil {
+		return err
+	}
+
+	// The extra entry only tells there is a next page
+	entries := reflect.ValueOf(value).Elem()
+	if entries.Len() > page.GetLimit() {
+		next, err := encodeCursor(entries.Index(page.GetLimit()-1), order, columns)
+		if err != nil {
+			log.Println("Error while encoding page cursor: " + err.Error())
+			return err
+		}
+		page.SetNextCursor(next)
+		entries.Set(entries.Slice(0, page.GetLimit()))
+	}
+
+	log.Println("Fetched database page ordered by: " + order)
+	return nil
+}
+
+// Function that maps the sort (E.g username asc, id desc) into model fields, appending the primary key when missing
+func getKeysetColumns(modelSchema *schema.Schema, sort string) ([]keysetColumn, error) {
+
+	var columns []keysetColumn
+	for _, part := range strings.Split(sort, ",") {
+		splits := strings.Fields(part)
+		if len(splits) == 0 {
+			continue
+		}
+
+		field := lookUpField(modelSchema, splits[0])
+		if field == nil {
+			log.Println("Error - Unknown sort field for pagination: " + sort)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
+		}
+		columns = append(columns, keysetColumn{field: field, desc: len(splits) > 1 && strings.EqualFold(splits[1], "desc")})
+	}
+
+	primaryKey := modelSchema.PrioritizedPrimaryField
+	if primaryKey == nil {
+		return columns, nil
+	}
+	for _, column := range columns {
+		if column.field == primaryKey {
+			return columns, nil
+		}
+	}
+	return append(columns, keysetColumn{field: primaryKey}), nil
+}
+
+// Function that finds a field by column name, struct name or lowercase struct name
+func lookUpField(modelSchema *schema.Schema, name string) *schema.Field {
+
+	if field := modelSchema.LookUpField(name); field != nil && field.DBName != "" {
+		return field
+	}
+	for _, field := range modelSchema.Fields {
+		if field.DBName != "" && strings.ToLower(field.Name) == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Function that constructs the order clause of the columns
+func constructOrder(columns []keysetColumn) string {
+
+	var order []string
+	for _, column := range columns {
+		direction := " asc"
+		if column.desc {
+			direction = " desc"
+		}
+		order = append(order, column.field.DBName+direction)
+	}
+	return strings.Join(order, ", ")
+}
+
+// Function that stores the order values of the entry into an opaque cursor
+func encodeCursor(entry reflect.Value, order string, columns []keysetColumn) (string, error) {
+
+	c := cursor{Order: order}
+	for _, column := range columns {
+		raw, err := json.Marshal(reflect.Indirect(entry).FieldByName(column.field.Name).Interface())
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, raw)
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Function that compiles the cursor into the condition selecting the entries after it
+// E.g value asc, id asc ---> (value > ?) OR (value = ? AND id > ?)
+func decodeCursor(encoded, order string, columns []keysetColumn) (string, []interface{}, error) {
+
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Order != order || len(c.Values) != len(columns) {
+		log.Println("Error - Cursor malformed or from another order: " + encoded)
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.field.FieldType)
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			log.Println("Error - Cursor value malformed: " + encoded)
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed cursor query parameter, it does not belong to this listing")
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var groups []string
+	var identifiers []interface{}
+	for i, column := range columns {
+		var clauses []string
+		for _, previous := range columns[:i] {
+			clauses = append(clauses, previous.field.DBName+" = ?")
+		}
+		operator := " > ?"
+		if column.desc {
+			operator = " < ?"
+		}
+		clauses = append(clauses, column.field.DBName+operator)
+
+		groups = append(groups, "("+strings.Join(clauses, " AND ")+")")
+		identifiers = append(identifiers, values[:i+1]...)
+	}
+	return "(" + strings.Join(groups, " OR ") + ")", identifiers, nil
+}
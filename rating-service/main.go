@@ -4,20 +4,25 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"rating-service/clients"
 	"rating-service/controllers"
 	"rating-service/database"
 	_ "rating-service/docs"
 	"rating-service/repositories"
 	"rating-service/route"
 	"rating-service/services"
+	"rating-service/utils"
 )
 
+const marketplaceTimeout = 5 * time.Second // Longest wait for the marketplace to prove a sale
+
 // @title Rating Service App Swagger
 // @version 1.0
 // @description This microservice is an abstracted way of rating other services' products in the architecture.
@@ -37,14 +42,30 @@ func main() {
 	// Fetch Env variables
 	oauthKey, oauthKeyPresent := os.LookupEnv("OAUTH_KEY")
 	port, portPresent := os.LookupEnv("PORT")
-	if !oauthKeyPresent || !portPresent {
+	marketplaceURL, marketplaceURLPresent := os.LookupEnv("MARKETPLACE_URL")
+	if !oauthKeyPresent || !portPresent || !marketplaceURLPresent {
 		log.Println("Error occurred while fetching essential env variables")
 		return
 	}
 
 	// Initialize Repositories & Services & controllers
-	repositories := repositories.InitRepositories(db)
-	services := services.InitServices(repositories)
+	rankingConfig, err := utils.GetRankingConfig()
+	if err != nil {
+		log.Println("Error occurred while fetching ranking env variables")
+		return
+	}
+	repositories := repositories.InitRepositories(db, rankingConfig)
+
+	// Recompute rankings, as the prior or minimum votes may have changed
+	if err := repositories.RankingRepository.Sync(); err != nil {
+		log.Println("Error occurred while syncing rankings")
+		return
+	}
+
+	// Sales are checked with the marketplace when sellers are rated
+	marketplaceClient := clients.NewMarketplaceClient(marketplaceURL, marketplaceTimeout)
+
+	services := services.InitServices(repositories, marketplaceClient)
 	controllers := controllers.InitControllers(services)
 
 	// Creates routing
@@ -4,7 +4,7 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
-	"github.com/jinzhu/gorm"
+	"gorm.io/gorm"
 )
 
 // Base model so that ID is UUID
@@ -16,10 +16,15 @@ type CustomBase struct {
 }
 
 // BeforeCreate will set a UUID rather than numeric ID.
-func (base *CustomBase) BeforeCreate(scope *gorm.Scope) error {
+func (base *CustomBase) BeforeCreate(tx *gorm.DB) error {
+	if base.ID != uuid.Nil {
+		return nil
+	}
+
 	uuid, err := uuid.NewV4()
 	if err != nil {
 		return err
 	}
-	return scope.SetColumn("ID", uuid)
+	base.ID = uuid
+	return nil
 }
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Ranking holds the Bayesian average score of one referenced object, kept up to date as its ratings change
+// score = (sum + prior * minVotes) / (count + minVotes) -> Objects with few ratings stay close to the prior
+type Ranking struct {
+	Reference_namespace string    `json:"reference_namespace" gorm:"primaryKey;index:idx_ranking_score,priority:1"`
+	Reference_id        string    `json:"reference_id" gorm:"primaryKey"`
+	Count               int64     `json:"count"`
+	Sum                 int64     `json:"-"`
+	Score               float64   `json:"score" gorm:"index:idx_ranking_score,priority:2,sort:desc"`
+	UpdatedAt           time.Time `json:"-"`
+}
+
+// RankingConfig is the prior belief every score starts from
+type RankingConfig struct {
+	Prior    float64 // Score of an object without ratings
+	MinVotes int     // Number of ratings the prior weighs as
+}
+
+// Function that creates the Ranking of an object without ratings
+func NewRanking(namespace, id string, config RankingConfig) Ranking {
+	return Ranking{
+		Reference_namespace: namespace,
+		Reference_id:        id,
+		Score:               config.Prior,
+	}
+}
@@ -2,14 +2,32 @@ package model
 
 import "github.com/gofrs/uuid"
 
+// Namespace of marketplace sellers, rated by username -> Only their buyers can rate them, with the sold offer as proof
+const UserNamespace = "user"
+
 type Rating struct {
 	CustomBase          `swaggerignore:"true"`
-	Username            string `json:"username" db:"username" valid:"required,alphanum,maxstringlength(50)" gorm:"index:unique_rating,unique"`
+	Username            string `json:"username" db:"username" valid:"required,username" gorm:"index:unique_rating,unique"`
 	Reference_namespace string `json:"reference_namespace" db:"reference_namespace" gorm:"index:unique_rating,unique" valid:"required,alpha,maxstringlength(50)"`
-	Reference_id        string `json:"reference_id" db:"reference_id" gorm:"index:unique_rating,unique" valid:"required,uuid"`
+	Reference_id        string `json:"reference_id" db:"reference_id" gorm:"index:unique_rating,unique" valid:"required,maxstringlength(50),matches(^[a-zA-Z0-9._-]+$)"` // Usernames of the user namespace have dots and underscores
 	Value               int    `json:"value" db:"value" valid:"required,int,range(0|10)"`
+	Proof               string `json:"proof,omitempty" db:"proof" valid:"maxstringlength(50),matches(^[a-zA-Z0-9-]+$)"` // Id of what entitles the user to rate (E.g the offer they bought from a seller)
+}
+
+// Only the value of a Rating can change -> What and who it rates stay the same
+type RatingUpdate struct {
+	Value int `json:"value" valid:"int,range(0|10)"` // Not required, which rejects a rating of 0
 }
 
 func (rating *Rating) GetId() uuid.UUID {
 	return rating.ID
 }
+
+func (rating *Rating) SetUsername(username string) {
+	rating.Username = username
+}
+
+// Method that checks if the user is the one who rated
+func (rating *Rating) IsOwner(username string) bool {
+	return rating.Username == username
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Sale is the proof from the marketplace that an offer was sold, and to whom
+type Sale struct {
+	OfferUuid string    `json:"offer_uuid"`
+	Seller    string    `json:"seller"`
+	Buyer     string    `json:"buyer"`
+	SoldAt    time.Time `json:"sold_at"`
+}
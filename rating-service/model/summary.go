@@ -0,0 +1,23 @@
+package model
+
+// Number of histogram buckets -> One per possible rating value, from 0 to 10
+const HistogramSize = 11
+
+// Summary aggregates every Rating of one referenced object
+type Summary struct {
+	Reference_namespace string               `json:"reference_namespace"`
+	Reference_id        string               `json:"reference_id"`
+	Count               int64                `json:"count"`
+	Mean                float64              `json:"mean"`
+	Median              float64              `json:"median"`
+	StdDev              float64              `json:"stddev"`
+	Histogram           [HistogramSize]int64 `json:"histogram"` // Number of ratings of each value, index being the value
+}
+
+// Function that creates the Summary of an object without ratings
+func NewSummary(namespace, id string) Summary {
+	return Summary{
+		Reference_namespace: namespace,
+		Reference_id:        id,
+	}
+}
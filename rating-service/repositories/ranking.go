@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"rating-service/database"
+	"rating-service/model"
+)
+
+const (
+	// Adds the count and sum deltas of a rating to the ranking of its object, recomputing the score
+	rankingApplyQuery = `INSERT INTO rankings (reference_namespace, reference_id, count, sum, score, updated_at)
+		VALUES (@namespace, @id, CAST(@count AS bigint), CAST(@sum AS bigint), (CAST(@sum AS bigint) + CAST(@prior AS float8) * CAST(@votes AS bigint)) / (CAST(@count AS bigint) + CAST(@votes AS bigint)), now())
+		ON CONFLICT (reference_namespace, reference_id) DO UPDATE SET
+			count = rankings.count + EXCLUDED.count,
+			sum = rankings.sum + EXCLUDED.sum,
+			score = (rankings.sum + EXCLUDED.sum + CAST(@prior AS float8) * CAST(@votes AS bigint)) / (rankings.count + EXCLUDED.count + CAST(@votes AS bigint)),
+			updated_at = now()`
+	rankingCleanQuery = `DELETE FROM rankings WHERE reference_namespace = ? AND reference_id = ? AND count <= 0`
+
+	// Recomputes every ranking from the ratings themselves
+	rankingSyncQuery = `INSERT INTO rankings (reference_namespace, reference_id, count, sum, score, updated_at)
+		SELECT reference_namespace, reference_id, count(*), sum(value), (sum(value) + CAST(@prior AS float8) * CAST(@votes AS bigint)) / (count(*) + CAST(@votes AS bigint)), now()
+		FROM ratings GROUP BY reference_namespace, reference_id
+		ON CONFLICT (reference_namespace, reference_id) DO UPDATE SET
+			count = EXCLUDED.count, sum = EXCLUDED.sum, score = EXCLUDED.score, updated_at = now()`
+	rankingSyncCleanQuery = `DELETE FROM rankings WHERE NOT EXISTS
+		(SELECT 1 FROM ratings WHERE ratings.reference_namespace = rankings.reference_namespace AND ratings.reference_id = rankings.reference_id)`
+
+	rankingSort = "score desc, reference_id asc"
+)
+
+type RankingRepository struct {
+	db     *database.PostgresqlRepository
+	config model.RankingConfig
+}
+
+func NewRankingRepository(instance *database.PostgresqlRepository, config model.RankingConfig) *RankingRepository {
+	return &RankingRepository{
+		db:     instance,
+		config: config,
+	}
+}
+
+// Method that adds the change of a rating to the ranking of its object, using the transaction that changes the rating
+// E.g Created -> count 1, sum value; Deleted -> count -1, sum -value; Re-rated -> count 0, sum new - old
+func (repo *RankingRepository) apply(tx *database.PostgresqlRepository, rating *model.Rating, count, sum int) error {
+
+	if err := tx.Exec(rankingApplyQuery, map[string]interface{}{
+		"namespace": rating.Reference_namespace,
+		"id":        rating.Reference_id,
+		"count":     count,
+		"sum":       sum,
+		"prior":     repo.config.Prior,
+		"votes":     repo.config.MinVotes,
+	}); err != nil {
+		return err
+	}
+
+	// Objects without ratings have no ranking
+	return tx.Exec(rankingCleanQuery, rating.Reference_namespace, rating.Reference_id)
+}
+
+// Method that recomputes every ranking from the ratings -> Keeps scores right after the prior or minimum votes change
+func (repo *RankingRepository) Sync() error {
+
+	return repo.db.Transaction(func(tx *database.PostgresqlRepository) error {
+		if err := tx.Exec(rankingSyncQuery, map[string]interface{}{"prior": repo.config.Prior, "votes": repo.config.MinVotes}); err != nil {
+			return err
+		}
+		return tx.Exec(rankingSyncCleanQuery)
+	})
+}
+
+func (repo *RankingRepository) GetAll(namespace string, page *model.Page) ([]model.Ranking, error) {
+
+	var rankings []model.Ranking
+	return rankings, repo.db.Read(&rankings, page, rankingSort, "reference_namespace = ?", namespace)
+}
+
+// Method that fetches the ranking of each object -> Objects without ratings get the prior score, keeping the order of ids
+func (repo *RankingRepository) GetByIds(namespace string, ids []string) ([]model.Ranking, error) {
+
+	var found []model.Ranking
+	if err := repo.db.Raw(&found, "SELECT * FROM rankings WHERE reference_namespace = ? AND reference_id IN ?", namespace, ids); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]model.Ranking, len(found))
+	for _, ranking := range found {
+		byId[ranking.Reference_id] = ranking
+	}
+
+	rankings := make([]model.Ranking, len(ids))
+	for i, id := range ids {
+		ranking, ok := byId[id]
+		if !ok {
+			ranking = model.NewRanking(namespace, id, repo.config)
+		}
+		rankings[i] = ranking
+	}
+	return rankings, nil
+}
@@ -2,36 +2,121 @@ package repositories
 
 import (
 	"errors"
+	"net/http"
 	"rating-service/database"
 	"rating-service/middleware"
 	"rating-service/model"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	summaryQuery = `SELECT reference_id, count(*) AS count, avg(value)::float8 AS mean,
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY value) AS median, stddev_pop(value)::float8 AS std_dev
+		FROM ratings WHERE reference_namespace = ? AND reference_id IN ?
+		GROUP BY reference_id`
+	// Locks the rating of the user for the object, if there is one, until the transaction ends
+	ratingOfUserQuery = `SELECT * FROM ratings WHERE username = ? AND reference_namespace = ? AND reference_id = ? FOR UPDATE`
+	// Locks the rating, so concurrent changes apply their ranking deltas one after the other from its current value
+	ratingByIdQuery = `SELECT * FROM ratings WHERE id = ? FOR UPDATE`
+	histogramQuery  = `SELECT reference_id, value, count(*) AS count
+		FROM ratings WHERE reference_namespace = ? AND reference_id IN ?
+		GROUP BY reference_id, value`
 )
 
+// Aggregations of the ratings of one referenced object
+type summaryStat struct {
+	Reference_id string
+	Count        int64
+	Mean         float64
+	Median       float64
+	StdDev       float64
+}
+
+// Number of ratings with a given value of one referenced object
+type histogramBucket struct {
+	Reference_id string
+	Value        int
+	Count        int64
+}
+
 type RatingRepository struct {
-	db *database.PostgresqlRepository
+	db       *database.PostgresqlRepository
+	rankings *RankingRepository
 }
 
-func NewRatingRepository(instance *database.PostgresqlRepository) *RatingRepository {
+func NewRatingRepository(instance *database.PostgresqlRepository, rankingRepo *RankingRepository) *RatingRepository {
 	return &RatingRepository{
-		db: instance,
+		db:       instance,
+		rankings: rankingRepo,
 	}
 }
 
+// Method that creates the rating, or replaces the value when the user already rated the object (unique_rating), updating the ranking together
 func (repo *RatingRepository) Create(rating *model.Rating) error {
 
-	return repo.db.Create(rating)
+	return repo.db.Transaction(func(tx *database.PostgresqlRepository) error {
+		var existing model.Rating
+		if err := tx.Raw(&existing, ratingOfUserQuery, rating.Username, rating.Reference_namespace, rating.Reference_id); err != nil {
+			return err
+		}
+
+		if existing.GetId() == uuid.Nil { // First rating of the user for the object
+			created, err := tx.CreateIfAbsent(rating)
+			if err != nil {
+				return err
+			}
+			if created {
+				return repo.rankings.apply(tx, rating, 1, rating.Value)
+			}
+
+			// Rated meanwhile by a concurrent request, now committed -> Re-rates it instead
+			if err := tx.Raw(&existing, ratingOfUserQuery, rating.Username, rating.Reference_namespace, rating.Reference_id); err != nil {
+				return err
+			}
+		}
+
+		// Re-rating -> Replaces the value, and its proof
+		previous := existing.Value
+		existing.Value = rating.Value
+		existing.Proof = rating.Proof
+		if err := tx.Update(&existing); err != nil {
+			return err
+		}
+		*rating = existing
+		return repo.rankings.apply(tx, rating, 0, rating.Value-previous)
+	})
+}
+
+// Method that changes the value of the rating, updating the ranking of its object together
+func (repo *RatingRepository) Update(rating *model.Rating, value int) error {
+
+	return repo.db.Transaction(func(tx *database.PostgresqlRepository) error {
+		current, err := lockRating(tx, rating.GetId().String())
+		if err != nil {
+			return err
+		}
+
+		previous := current.Value
+		current.Value = value
+		if err := tx.Update(&current); err != nil {
+			return err
+		}
+		*rating = current
+		return repo.rankings.apply(tx, rating, 0, value-previous)
+	})
 }
 
-func (repo *RatingRepository) GetAll(sort string) ([]model.Rating, error) {
+func (repo *RatingRepository) GetAll(sort string, page *model.Page) ([]model.Rating, error) {
 
 	var ratings []model.Rating
-	return ratings, repo.db.Read(&ratings, sort, "", "")
+	return ratings, repo.db.Read(&ratings, page, sort, "", "")
 }
 
 func (repo *RatingRepository) Get(id string) (model.Rating, error) {
 
 	var rating model.Rating
-	err := repo.db.Read(&rating, "", "id = ?", id)
+	err := repo.db.Read(&rating, nil, "", "id = ?", id)
 
 	var mr *middleware.MalformedRequest
 	if err != nil && errors.As(err, &mr) {
@@ -41,7 +126,65 @@ func (repo *RatingRepository) Get(id string) (model.Rating, error) {
 	return rating, err
 }
 
+// Method that deletes the rating and removes it from the ranking of its object, together
 func (repo *RatingRepository) Delete(rating *model.Rating) error {
 
-	return repo.db.Delete(rating)
+	return repo.db.Transaction(func(tx *database.PostgresqlRepository) error {
+		current, err := lockRating(tx, rating.GetId().String())
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&current); err != nil { // Not found when deleted meanwhile, leaving the ranking alone
+			return err
+		}
+		return repo.rankings.apply(tx, &current, -1, -current.Value)
+	})
+}
+
+// Function that reads the rating as it is now, locking it until the transaction ends -> Not found once deleted
+func lockRating(tx *database.PostgresqlRepository, id string) (model.Rating, error) {
+
+	var rating model.Rating
+	if err := tx.Raw(&rating, ratingByIdQuery, id); err != nil {
+		return rating, err
+	}
+	if rating.GetId() == uuid.Nil {
+		return rating, middleware.NewError(http.StatusNotFound, "Rating not found with id: "+id)
+	}
+	return rating, nil
+}
+
+// Method that aggregates the ratings of each referenced object -> Objects without ratings get an empty Summary, keeping the order of ids
+func (repo *RatingRepository) GetSummaries(namespace string, ids []string) ([]model.Summary, error) {
+
+	var stats []summaryStat
+	if err := repo.db.Raw(&stats, summaryQuery, namespace, ids); err != nil {
+		return nil, err
+	}
+
+	var buckets []histogramBucket
+	if err := repo.db.Raw(&buckets, histogramQuery, namespace, ids); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]*model.Summary, len(ids))
+	summaries := make([]model.Summary, len(ids))
+	for i, id := range ids {
+		summaries[i] = model.NewSummary(namespace, id)
+		byId[id] = &summaries[i]
+	}
+
+	for _, stat := range stats {
+		if summary, ok := byId[stat.Reference_id]; ok {
+			summary.Count, summary.Mean, summary.Median, summary.StdDev = stat.Count, stat.Mean, stat.Median, stat.StdDev
+		}
+	}
+	for _, bucket := range buckets {
+		if summary, ok := byId[bucket.Reference_id]; ok && bucket.Value >= 0 && bucket.Value < model.HistogramSize {
+			summary.Histogram[bucket.Value] = bucket.Count
+		}
+	}
+
+	return summaries, nil
 }
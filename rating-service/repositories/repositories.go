@@ -1,17 +1,23 @@
 package repositories
 
-import "rating-service/database"
+import (
+	"rating-service/database"
+	"rating-service/model"
+)
 
 // Repositories contains all the repo structs
 type Repositories struct {
-	RatingRepository *RatingRepository
+	RatingRepository  *RatingRepository
+	RankingRepository *RankingRepository
 }
 
 // InitRepositories should be called in main.go
-func InitRepositories(db *database.PostgresqlRepository) *Repositories {
-	ratingRepository := NewRatingRepository(db)
+func InitRepositories(db *database.PostgresqlRepository, rankingConfig model.RankingConfig) *Repositories {
+	rankingRepository := NewRankingRepository(db, rankingConfig)
+	ratingRepository := NewRatingRepository(db, rankingRepository)
 
 	return &Repositories{
-		RatingRepository: ratingRepository,
+		RatingRepository:  ratingRepository,
+		RankingRepository: rankingRepository,
 	}
 }
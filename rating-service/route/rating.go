@@ -16,7 +16,15 @@ func AddRatingRouter(router chi.Router, oauthKey string, ratingController *contr
 		router.Get("/api/rating", ratingController.GetAll)
 		router.Get("/api/rating/{id}", ratingController.Get)
 		router.Post("/api/rating", ratingController.Create)
-		//router.Patch("/api/rating/{id}", ratingController.Update)
+		router.Patch("/api/rating/{id}", ratingController.Update)
 		router.Delete("/api/rating/{id}", ratingController.Delete)
 	})
+
+	// Public layer -> Aggregated scores are shown to everyone (E.g On catalog listings)
+	router.Group(func(router chi.Router) {
+		router.Get("/api/rating/summary", ratingController.GetSummary)
+		router.Get("/api/rating/summary/batch", ratingController.GetSummaries)
+		router.Get("/api/rating/ranking", ratingController.GetRankings)
+		router.Get("/api/rating/ranking/batch", ratingController.GetRankingsByIds)
+	})
 }
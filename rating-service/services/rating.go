@@ -1,35 +1,81 @@
 package services
 
 import (
+	"log"
+	"net/http"
+
+	"rating-service/clients"
+	"rating-service/middleware"
 	"rating-service/model"
 	"rating-service/repositories"
 )
 
 type ratingRepository interface {
 	Create(rating *model.Rating) error
-	GetAll(sort string) ([]model.Rating, error)
+	GetAll(sort string, page *model.Page) ([]model.Rating, error)
 	Get(id string) (model.Rating, error)
+	Update(rating *model.Rating, value int) error
 	Delete(rating *model.Rating) error
+	GetSummaries(namespace string, ids []string) ([]model.Summary, error)
+}
+
+type rankingRepository interface {
+	GetAll(namespace string, page *model.Page) ([]model.Ranking, error)
+	GetByIds(namespace string, ids []string) ([]model.Ranking, error)
+}
+
+type marketplaceClient interface {
+	GetSale(offerId, authorization string) (model.Sale, error)
 }
 
 type RatingService struct {
-	repo ratingRepository
+	repo        ratingRepository
+	rankings    rankingRepository
+	marketplace marketplaceClient
 }
 
-func InitRatingService(tagRepo *repositories.RatingRepository) *RatingService {
+func InitRatingService(tagRepo *repositories.RatingRepository, rankingRepo *repositories.RankingRepository, marketplaceClient *clients.MarketplaceClient) *RatingService {
 	return &RatingService{
-		repo: tagRepo,
+		repo:        tagRepo,
+		rankings:    rankingRepo,
+		marketplace: marketplaceClient,
 	}
 }
 
-func (svc *RatingService) Create(rating *model.Rating) error {
+// Method that creates the rating -> Sellers are only rated by their buyers, checked with the authorization of the user
+func (svc *RatingService) Create(rating *model.Rating, authorization string) error {
+
+	if rating.Reference_namespace == model.UserNamespace {
+		if err := svc.checkSale(rating, authorization); err != nil {
+			return err
+		}
+	}
 
 	return svc.repo.Create(rating)
 }
 
-func (svc *RatingService) GetAll(sort string) ([]model.Rating, error) {
+// Method that checks the proof of the rating is an offer the user bought from the rated seller
+func (svc *RatingService) checkSale(rating *model.Rating, authorization string) error {
+
+	if rating.Proof == "" {
+		return middleware.NewError(http.StatusUnprocessableEntity, "Rating a seller needs the id of the offer bought from them as proof")
+	}
+
+	sale, err := svc.marketplace.GetSale(rating.Proof, authorization)
+	if err != nil {
+		return err
+	}
+
+	if sale.Buyer != rating.Username || sale.Seller != rating.Reference_id {
+		log.Println("Error - User " + rating.Username + " did not buy offer " + rating.Proof + " from " + rating.Reference_id)
+		return middleware.NewError(http.StatusForbidden, "Only the buyer of the offer can rate its seller")
+	}
+	return nil
+}
+
+func (svc *RatingService) GetAll(sort string, page *model.Page) ([]model.Rating, error) {
 
-	return svc.repo.GetAll(sort)
+	return svc.repo.GetAll(sort, page)
 }
 
 func (svc *RatingService) Get(id string) (model.Rating, error) {
@@ -37,7 +83,23 @@ func (svc *RatingService) Get(id string) (model.Rating, error) {
 	return svc.repo.Get(id)
 }
 
-func (svc *RatingService) Delete(id string) error {
+func (svc *RatingService) Update(input *model.RatingUpdate, id, username string) (model.Rating, error) {
+
+	// Get rating by id
+	rating, err := svc.repo.Get(id)
+	if err != nil {
+		return model.Rating{}, err
+	}
+
+	if !rating.IsOwner(username) {
+		log.Println("Error - User " + username + " is not the owner of rating " + id)
+		return model.Rating{}, middleware.NewError(http.StatusForbidden, "Only the owner can update the rating")
+	}
+
+	return rating, svc.repo.Update(&rating, input.Value)
+}
+
+func (svc *RatingService) Delete(id, username string) error {
 
 	// Get rating by id
 	rating, err := svc.repo.Get(id)
@@ -45,6 +107,35 @@ func (svc *RatingService) Delete(id string) error {
 		return err
 	}
 
+	if !rating.IsOwner(username) {
+		log.Println("Error - User " + username + " is not the owner of rating " + id)
+		return middleware.NewError(http.StatusForbidden, "Only the owner can delete the rating")
+	}
+
 	// Delete by id
 	return svc.repo.Delete(&rating)
 }
+
+func (svc *RatingService) GetSummary(namespace, id string) (model.Summary, error) {
+
+	summaries, err := svc.repo.GetSummaries(namespace, []string{id})
+	if err != nil {
+		return model.Summary{}, err
+	}
+	return summaries[0], nil
+}
+
+func (svc *RatingService) GetSummaries(namespace string, ids []string) ([]model.Summary, error) {
+
+	return svc.repo.GetSummaries(namespace, ids)
+}
+
+func (svc *RatingService) GetRankings(namespace string, page *model.Page) ([]model.Ranking, error) {
+
+	return svc.rankings.GetAll(namespace, page)
+}
+
+func (svc *RatingService) GetRankingsByIds(namespace string, ids []string) ([]model.Ranking, error) {
+
+	return svc.rankings.GetByIds(namespace, ids)
+}
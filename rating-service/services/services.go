@@ -1,6 +1,9 @@
 package services
 
-import "rating-service/repositories"
+import (
+	"rating-service/clients"
+	"rating-service/repositories"
+)
 
 // Repositories contains all the repo structs
 type Services struct {
@@ -8,8 +11,8 @@ type Services struct {
 }
 
 // InitRepositories should be called in main.go
-func InitServices(repositories *repositories.Repositories) *Services {
-	ratingService := InitRatingService(repositories.RatingRepository)
+func InitServices(repositories *repositories.Repositories, marketplaceClient *clients.MarketplaceClient) *Services {
+	ratingService := InitRatingService(repositories.RatingRepository, repositories.RankingRepository, marketplaceClient)
 
 	return &Services{
 		RatingService: ratingService,
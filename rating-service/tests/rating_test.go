@@ -2,6 +2,7 @@ package tests
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -31,6 +32,198 @@ func TestCreateRating(t *testing.T) {
 		End()
 }
 
+/* Tests POST a Rating of a catalog Boardgame, referenced by its numeric id, with success*/
+func TestCreateBoardgameRating(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"username":"test", "reference_namespace": "boardgame", "reference_id": "1", "value": 7}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Body(`{"username":"test", "reference_namespace": "boardgame", "reference_id": "1", "value": 7}`).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests POST a Rating of a seller by the buyer of their sold offer with success*/
+func TestCreateSellerRating(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "user", "reference_id": "seller", "value": 9, "proof": "`+soldOffer+`"}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Body(`{"username":"test", "reference_namespace": "user", "reference_id": "seller", "value": 9, "proof": "` + soldOffer + `"}`).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests POST a Rating of a seller without being their buyer with errors*/
+func TestCreateSellerRatingFailure(t *testing.T) {
+	// Missing proof
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "user", "reference_id": "seller", "value": 9}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Offer not sold
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "user", "reference_id": "seller", "value": 9, "proof": "unsold"}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Not the buyer
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "user", "reference_id": "seller", "value": 1, "proof": "`+soldOffer+`"}`).
+		Header("Authorization", "Bearer "+otherHeader).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	// Not the seller of the offer
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "user", "reference_id": "other", "value": 1, "proof": "`+soldOffer+`"}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+}
+
+/* Tests GET the Summary of the Ratings of one and several objects with success*/
+func TestGetRatingSummary(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary").
+		Query("reference_namespace", "boardgame").
+		Query("reference_id", "1").
+		Expect(t).
+		Body(`{"reference_namespace":"boardgame", "reference_id":"1", "count":1, "mean":7, "median":7, "stddev":0, "histogram":[0,0,0,0,0,0,0,1,0,0,0]}`).
+		Status(http.StatusOK).
+		End()
+
+	// Batch keeps the order of ids and summarizes objects without ratings as empty
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary/batch").
+		Query("reference_namespace", "boardgame").
+		Query("reference_ids", "unrated,1").
+		Expect(t).
+		Body(`[{"reference_namespace":"boardgame", "reference_id":"unrated", "count":0, "mean":0, "median":0, "stddev":0, "histogram":[0,0,0,0,0,0,0,0,0,0,0]},
+			{"reference_namespace":"boardgame", "reference_id":"1", "count":1, "mean":7, "median":7, "stddev":0, "histogram":[0,0,0,0,0,0,0,1,0,0,0]}]`).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests POST and summarize Ratings of users whose usernames have dots, dashes and underscores, as user-management registers them*/
+func TestRatingUsernameCharset(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "charset", "reference_id": "1", "value": 5}`).
+		Header("Authorization", "Bearer "+dottedHeader).
+		Expect(t).
+		Body(`{"username":"` + dottedUsername + `", "reference_namespace": "charset", "reference_id": "1", "value": 5}`).
+		Status(http.StatusOK).
+		End()
+
+	// Sellers are summarized by the same usernames
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary/batch").
+		Query("reference_namespace", "user").
+		Query("reference_ids", dottedUsername).
+		Expect(t).
+		Body(`[{"reference_namespace":"user", "reference_id":"` + dottedUsername + `", "count":0, "mean":0, "median":0, "stddev":0, "histogram":[0,0,0,0,0,0,0,0,0,0,0]}]`).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests GET the Summary of Ratings with malformed references*/
+func TestGetRatingSummaryFailure(t *testing.T) {
+	// Missing reference_namespace
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary").
+		Query("reference_id", "1").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Several ids on the single summary
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary").
+		Query("reference_namespace", "boardgame").
+		Query("reference_id", "1,2").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Batch with a malformed id
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/summary/batch").
+		Query("reference_namespace", "boardgame").
+		Query("reference_ids", "1,,2").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests GET the Rankings of a namespace and of several objects with success*/
+func TestGetRankings(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/ranking").
+		Query("reference_namespace", "boardgame").
+		Query("limit", "5").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	// Objects without ratings are ranked with the prior score
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/ranking/batch").
+		Query("reference_namespace", "boardgame").
+		Query("reference_ids", "1,unrated").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests GET the Rankings with malformed references*/
+func TestGetRankingsFailure(t *testing.T) {
+	// Missing reference_namespace
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/ranking").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Batch without ids
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating/ranking/batch").
+		Query("reference_namespace", "boardgame").
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
 /* Tests GET all Ratings with success*/
 func TestGetAllRatings(t *testing.T) {
 	apitest.New().
@@ -42,6 +235,55 @@ func TestGetAllRatings(t *testing.T) {
 		End()
 }
 
+/* Tests GET a page of Ratings with success*/
+func TestGetAllRatingsPaginated(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating").
+		Query("limit", "1").
+		Query("sortBy", "value.desc,created_at.asc").
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+/* Tests GET a page of Ratings with malformed page errors*/
+func TestGetAllRatingsPaginatedFailure(t *testing.T) {
+	// Limit above maximum
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating").
+		Query("limit", "101").
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+
+	// Cursor that does not belong to the listing
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating").
+		Query("cursor", "test").
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
+/* Tests GET all Ratings with malformed sort errors*/
+func TestGetAllRatingsSortFailure(t *testing.T) {
+	// Repeated field
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/rating").
+		Query("sortBy", "value.desc,value.asc").
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusUnprocessableEntity).
+		End()
+}
+
 /* Tests GET a Rating with success*/
 func TestGetRating(t *testing.T) {
 	apitest.New().
@@ -54,6 +296,82 @@ func TestGetRating(t *testing.T) {
 		End()
 }
 
+/* Tests PATCH the value of an own Rating down to 0, the lowest of the range*/
+func TestUpdateRatingZero(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/rating/"+id).
+		JSON(`{"value": 0}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		Body(`{"username":"test", "reference_namespace": "test", "reference_id": "f299b8d9-135a-42df-9db8-d1d920d6f456", "value": 0}`).
+		End()
+}
+
+/* Tests PATCH the value of an own Rating with success*/
+func TestUpdateRating(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/rating/"+id).
+		JSON(`{"value": 8}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		Body(`{"username":"test", "reference_namespace": "test", "reference_id": "f299b8d9-135a-42df-9db8-d1d920d6f456", "value": 8}`).
+		End()
+}
+
+/* Tests POST a Rating of an already rated object, replacing its value*/
+func TestRerateRating(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/rating").
+		JSON(`{"reference_namespace": "test", "reference_id": "f299b8d9-135a-42df-9db8-d1d920d6f456", "value": 9}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error { // Same Rating, new value
+			var rating model.Rating
+			json.NewDecoder(res.Body).Decode(&rating)
+			if rating.GetId().String() != id || rating.Value != 9 {
+				return errors.New("rating was not replaced")
+			}
+			return nil
+		}).
+		End()
+}
+
+/* Tests PATCH and DELETE a Rating of another user with forbidden errors*/
+func TestUpdateRatingFailureNotOwner(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/rating/"+id).
+		JSON(`{"value": 1}`).
+		Header("Authorization", "Bearer "+otherHeader).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Delete("/api/rating/"+id).
+		Header("Authorization", "Bearer "+otherHeader).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	// Record not found
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Patch("/api/rating/test").
+		JSON(`{"value": 1}`).
+		Header("Authorization", "Bearer "+oauthHeader).
+		Expect(t).
+		Status(http.StatusNotFound).
+		End()
+}
+
 /* Tests DELETE a Rating with success*/
 /* func TestDeleteRating(t *testing.T) {
 	apitest.New().
@@ -193,11 +511,11 @@ func TestCreateRatingFailureInvalidStruct(t *testing.T) {
 		Status(http.StatusForbidden).
 		End()
 
-	// Invalid Struct -> reference_id NOT UUID
+	// Invalid Struct -> reference_id NOT an id (UUID or number)
 	apitest.New().
 		HandlerFunc(router.ServeHTTP).
 		Post("/api/category").
-		JSON(`{"username":"test", "reference_namespace": "test", "reference_id": "test", "value": 10}`).
+		JSON(`{"username":"test", "reference_namespace": "test", "reference_id": "asd.?_", "value": 10}`).
 		Header("Authorization", "Bearer "+oauthHeader).
 		Expect(t).
 		Status(http.StatusForbidden).
@@ -2,19 +2,32 @@ package tests
 
 import (
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"time"
 
+	"rating-service/clients"
 	"rating-service/controllers"
 	"rating-service/database"
 	"rating-service/repositories"
 	"rating-service/route"
 	"rating-service/services"
+	"rating-service/utils"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
 )
 
 var router *chi.Mux
 var oauthHeader string
+var otherHeader string  // Token of a user that owns no rating
+var dottedHeader string // Token of a user whose username has every character user-management allows
+
+const dottedUsername = "jane.doe_1-x"
+
+// Offer the fake marketplace sold from seller to the test user
+const soldOffer = "0b3c5a8e-6f1d-4e2a-9c7b-2d4f8e1a6b90"
 
 // Prepares test environment
 func init() {
@@ -37,10 +50,43 @@ func init() {
 
 	// Defines Token header to be used in requests
 	oauthHeader = header
+	otherHeader, err = createToken(oauthKey, "other")
+	if err != nil {
+		log.Println("Error occurred while creating the other user token")
+		return
+	}
+	dottedHeader, err = createToken(oauthKey, dottedUsername)
+	if err != nil {
+		log.Println("Error occurred while creating the dotted user token")
+		return
+	}
 
 	// Set Repositories & Controllers & Services
-	repositories := repositories.InitRepositories(db)
-	services := services.InitServices(repositories)
+	rankingConfig, err := utils.GetRankingConfig()
+	if err != nil {
+		log.Println("Error occurred while fetching ranking env variables")
+		return
+	}
+	repositories := repositories.InitRepositories(db, rankingConfig)
+
+	// Recompute rankings, as the prior or minimum votes may have changed
+	if err := repositories.RankingRepository.Sync(); err != nil {
+		log.Println("Error occurred while syncing rankings")
+		return
+	}
+
+	// Fakes the sales of the marketplace -> Only soldOffer was sold
+	marketplace := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/offer/"+soldOffer+"/sale" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"offer_uuid":"` + soldOffer + `", "seller":"seller", "buyer":"test", "sold_at":"2024-01-01T00:00:00Z"}`))
+	}))
+	marketplaceClient := clients.NewMarketplaceClient(marketplace.URL, time.Second)
+
+	services := services.InitServices(repositories, marketplaceClient)
 	controllers := controllers.InitControllers(services)
 
 	router = chi.NewRouter()
@@ -50,3 +96,13 @@ func init() {
 
 	log.Println("Setup Complete")
 }
+
+// Creates the token of the user, signed with the oauth key
+func createToken(oauthKey, username string) (string, error) {
+	return oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(oauthKey))).CryptToken(&oauth.Token{
+		CreationDate: time.Now().UTC(),
+		ExpiresIn:    time.Hour,
+		Claims:       map[string]string{"username": username},
+		TokenType:    oauth.BearerToken,
+	})
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"rating-service/middleware"
+	"rating-service/model"
+)
+
+const (
+	defaultLimit = 20  // Page size used when no limit is given
+	maxLimit     = 100 // Biggest page size that can be requested
+)
+
+// Main function of constructing the Page -> Validates limit, offset and cursor
+func GetPage(limit, offset, cursor string) (*model.Page, error) {
+
+	size := defaultLimit
+	if limit != "" {
+		var err error
+		if size, err = strconv.Atoi(limit); err != nil || size < 1 || size > maxLimit {
+			log.Println("Error - Page limit malformed: " + limit)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed limit query parameter, should be between 1 and "+strconv.Itoa(maxLimit))
+		}
+	}
+
+	skip := 0
+	if offset != "" {
+		var err error
+		if skip, err = strconv.Atoi(offset); err != nil || skip < 0 {
+			log.Println("Error - Page offset malformed: " + offset)
+			return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed offset query parameter, should be a positive number")
+		}
+	}
+
+	if skip > 0 && cursor != "" { // A cursor already points at where the page starts
+		log.Println("Error - Page offset and cursor used together")
+		return nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed page query parameters, use either offset or cursor")
+	}
+
+	return model.NewPage(size, skip, cursor), nil
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"rating-service/middleware"
+	"rating-service/model"
+)
+
+const (
+	defaultRankingPrior    = 5.0 // Middle of the 0-10 scale
+	defaultRankingMinVotes = 10
+)
+
+// Function that fetches the prior and minimum votes of the ranking from env vars, using defaults when absent
+func GetRankingConfig() (model.RankingConfig, error) {
+
+	config := model.RankingConfig{Prior: defaultRankingPrior, MinVotes: defaultRankingMinVotes}
+
+	if prior, present := os.LookupEnv("RANKING_PRIOR"); present {
+		value, err := strconv.ParseFloat(prior, 64)
+		if err != nil || value < 0 || value > 10 {
+			log.Println("Error - RANKING_PRIOR malformed: " + prior)
+			return config, middleware.NewError(http.StatusInternalServerError, "RANKING_PRIOR should be between 0 and 10")
+		}
+		config.Prior = value
+	}
+
+	if minVotes, present := os.LookupEnv("RANKING_MIN_VOTES"); present {
+		value, err := strconv.Atoi(minVotes)
+		if err != nil || value < 1 {
+			log.Println("Error - RANKING_MIN_VOTES malformed: " + minVotes)
+			return config, middleware.NewError(http.StatusInternalServerError, "RANKING_MIN_VOTES should be a positive number")
+		}
+		config.MinVotes = value
+	}
+
+	return config, nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"rating-service/middleware"
+)
+
+const (
+	referenceSeparator = "," // Reference ids of a batch are separated by a comma
+	maxReferences      = 100 // Maximum number of reference ids in one batch
+	maxReferenceLength = 50  // Same maximum length ratings are created with
+)
+
+var (
+	referenceNamespace = regexp.MustCompile(`^[a-zA-Z]+$`)
+	referenceID        = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`) // Uuids, numbers and usernames
+)
+
+// Function that validates the namespace of rated objects
+func GetNamespace(namespace string) (string, error) {
+
+	if namespace == "" || len(namespace) > maxReferenceLength || !referenceNamespace.MatchString(namespace) {
+		log.Println("Error - Reference namespace malformed: " + namespace)
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed reference_namespace query parameter")
+	}
+	return namespace, nil
+}
+
+// Main function of validating the references of rated objects -> Returns the namespace and the unique ids, in the given order
+func GetReferences(namespace, ids string) (string, []string, error) {
+
+	namespace, err := GetNamespace(namespace)
+	if err != nil {
+		return "", nil, err
+	}
+
+	splits := strings.Split(ids, referenceSeparator)
+	if len(splits) > maxReferences {
+		log.Println("Error - Too many references: " + strconv.Itoa(len(splits)))
+		return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed reference_id query parameter, at most "+strconv.Itoa(maxReferences)+" ids")
+	}
+
+	seen := make(map[string]bool)
+	var references []string
+	for _, id := range splits {
+		if id == "" || len(id) > maxReferenceLength || !referenceID.MatchString(id) {
+			log.Println("Error - Reference id malformed: " + id)
+			return "", nil, middleware.NewError(http.StatusUnprocessableEntity, "Malformed reference_id query parameter")
+		}
+
+		if !seen[id] { // Repeated ids are used once
+			seen[id] = true
+			references = append(references, id)
+		}
+	}
+
+	return namespace, references, nil
+}
@@ -5,17 +5,28 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"rating-service/middleware"
+
+	"gorm.io/gorm/schema"
+)
+
+const (
+	sortSeparator = "," // Sorts separated by a comma are applied in order
+	maxSorts      = 5   // Maximum number of fields accepted in one sortBy
 )
 
-// Function that checks if the sort parameters are valid for use
-func validateSortParameters(model interface{}, sortBy string) error {
+var schemaCache = &sync.Map{}
+
+// Function that checks if the sort parameters are valid for use, returning its column and order
+func validateSortParameters(model interface{}, sortBy string) (string, string, error) {
 
 	splits := strings.Split(sortBy, ".")
 
 	if len(splits) != 2 { // Validate if there are only 2 parameters
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, should be field.order")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, should be field.order")
 	}
 
 	field := splits[0]
@@ -23,66 +34,107 @@ func validateSortParameters(model interface{}, sortBy string) error {
 
 	if field == "" || order == "" { // Validate if there are no empty parameters
 		log.Printf("Error - Filter malformed, empty parameters")
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, can't be empty")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, can't be empty")
 	}
 
 	if order != "desc" && order != "asc" { // Validate if order is valid
-		return middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, order should be asc or desc")
+		return "", "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, order should be asc or desc")
 	}
 
-	return validateField(model, field) // Validate if field exists
+	column, err := validateField(model, field) // Validate if field exists
+	return column, order, err
 }
 
-// Function that checks if the field exists in the struct
-func validateField(model interface{}, fieldName string) error {
+// Function that checks if the field exists in the struct, including embedded ones like CustomBase, and returns its column
+func validateField(model interface{}, fieldName string) (string, error) {
 
+	naming := schema.NamingStrategy{}
 	fields := reflect.VisibleFields(reflect.TypeOf(model)) // Get all fields of Struct
 	for _, field := range fields {
-		if strings.ToLower(field.Name) == fieldName { // If there is a Field with this name
+		column := naming.ColumnName("", field.Name)
+		if strings.ToLower(field.Name) == fieldName || column == fieldName { // Accept both createdat and created_at
 
-			return isTypeSortable(field.Type.String()) // Checks if field is sortable.
+			return column, isTypeSortable(field.Type) // Checks if field is sortable.
 		}
 	}
 	log.Printf("Error - No field in struct %v with name %s", model, fieldName)
-	return middleware.NewError(http.StatusUnprocessableEntity, "No field with this name")
+	return "", middleware.NewError(http.StatusUnprocessableEntity, "No field with this name")
 }
 
 // Function that verifies if the field is sortable (E.g We cant sort by Tags)
-func isTypeSortable(typ string) error {
-	switch typ {
-	case "string", "int", "float64", "float32":
+func isTypeSortable(typ reflect.Type) error {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
 		return nil
 	}
-	log.Printf("Error - Field of type %s is not sortable", typ)
+	if typ == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+	log.Printf("Error - Field of type %s is not sortable", typ.String())
 	return middleware.NewError(http.StatusUnprocessableEntity, "Field not sortable")
 }
 
-// Function that constructs sort query
-func constructSort(sortBy string) string {
-	splits := strings.Split(sortBy, ".")
-	field := splits[0]
-	order := splits[1]
+// Function that returns the column of the model's primary key, if it has one
+func getPrimaryKey(model interface{}) (string, error) {
 
-	return field + " " + order
+	modelSchema, err := schema.Parse(model, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		log.Println("Error - Parsing schema of model failed: " + err.Error())
+		return "", err
+	}
 
+	if modelSchema.PrioritizedPrimaryField == nil {
+		return "", nil
+	}
+	return modelSchema.PrioritizedPrimaryField.DBName, nil
 }
 
-// Main function of constructing the Sort
+// Main function of constructing the Sort -> Ends on the primary key so that entries with equal values keep a deterministic order
 func GetSort(model interface{}, sortBy string) (string, error) {
 
-	if sortBy != "" {
-		log.Println("Sorting using %s " + sortBy)
-		err := validateSortParameters(model, sortBy) // Validates Sort -> By length, emptiness and by order and field existence
+	if sortBy == "" {
+		return "", nil // No sort -> No error
+	}
+
+	log.Println("Sorting using " + sortBy)
+	sorts := strings.Split(sortBy, sortSeparator)
+	if len(sorts) > maxSorts {
+		log.Println("Error - Too many sort fields: " + sortBy)
+		return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, too many fields")
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for _, sort := range sorts {
+		column, order, err := validateSortParameters(model, sort) // Validates Sort -> By length, emptiness and by order and field existence
 		if err != nil {
 			return "", err
 		}
 
-		sort := constructSort(sortBy) // After validating, constructs sort to be used in GetAll
-		return sort, nil
+		if seen[column] { // Validate if the field was already used
+			log.Println("Error - Sort field repeated: " + column)
+			return "", middleware.NewError(http.StatusUnprocessableEntity, "Malformed sortBy query parameter, fields can't be repeated")
+		}
+		seen[column] = true
+		columns = append(columns, column+" "+order)
+	}
+
+	primaryKey, err := getPrimaryKey(model)
+	if err != nil {
+		return "", err
 	}
-	return "", nil // No sort -> No error
+	if primaryKey != "" && !seen[primaryKey] { // Tie-breaker
+		columns = append(columns, primaryKey+" asc")
+	}
+
+	return strings.Join(columns, ", "), nil
 
 	// Examples of sorts that work:
-	// name.asc      --->  ordered by name in alphabetical ascending order
-	// price.desc    --->  ordered by price in numerical descending order
+	// value.desc                      --->  value desc, id asc
+	// reference_namespace.asc,value.desc  --->  reference_namespace asc, value desc, id asc
+	// created_at.desc                 --->  created_at desc, id asc
 }
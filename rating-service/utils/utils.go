@@ -39,7 +39,10 @@ func GetFieldFromURL(r *http.Request, field string) string {
 }
 
 func GetUsernameFromToken(r *http.Request) (string, error) {
-	claims := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+	claims, ok := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+	if !ok {
+		return "", middleware.NewError(http.StatusUnauthorized, "Error - Token claims not present")
+	}
 
 	if username, ok := claims["username"]; ok {
 		return username, nil
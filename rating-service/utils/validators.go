@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"regexp"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// Registers the validation tag of usernames
+func init() {
+	govalidator.TagMap["username"] = govalidator.Validator(IsUsername)
+}
+
+// Usernames as user-management registers them -> 3 to 30 ASCII letters, digits, dots, dashes or underscores
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,30}$`)
+
+// IsUsername checks the string could be the username of a user
+func IsUsername(str string) bool {
+	return usernamePattern.MatchString(str)
+}
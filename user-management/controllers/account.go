@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"user-management/middleware"
+	"user-management/models"
+	"user-management/services"
+	"user-management/utils"
+
+	"github.com/unrolled/render"
+)
+
+type accountService interface {
+	ResendVerification(username string) error
+	VerifyEmail(input *models.VerifyInput) (models.User, error)
+	ForgotPassword(input *models.ForgotInput) error
+	ResetPassword(input *models.ResetInput) error
+}
+
+type AccountController struct {
+	service accountService
+}
+
+// InitAccountController initializes the account controller.
+func InitAccountController(accountSvc *services.AccountService) *AccountController {
+	return &AccountController{
+		service: accountSvc,
+	}
+}
+
+// Verify Email godoc
+// @Summary 	Verifies the email a verification token was mailed to
+// @Tags 		account
+// @Produce 	json
+// @Param 		data body models.VerifyInput true "The token of the verification email"
+// @Success 	200 {object} models.User
+// @Router 		/email/verify [post]
+func (controller *AccountController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+
+	var input models.VerifyInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	user, err := controller.service.VerifyEmail(&input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, user)
+}
+
+// Resend Verification godoc
+// @Summary 	Mails another verification link to the email of the User of the token
+// @Tags 		account
+// @Success 	204
+// @Router 		/user/me/verification [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *AccountController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.ResendVerification(username); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, username)
+}
+
+// Forgot Password godoc
+// @Summary 	Mails a password reset link to the User of the email, answering the same for unknown emails
+// @Tags 		account
+// @Param 		data body models.ForgotInput true "The email of the User"
+// @Success 	204
+// @Router 		/password/forgot [post]
+func (controller *AccountController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+
+	var input models.ForgotInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.ForgotPassword(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, nil)
+}
+
+// Reset Password godoc
+// @Summary 	Sets the password of the User a password reset token was mailed to
+// @Tags 		account
+// @Param 		data body models.ResetInput true "The token of the password reset email and the new password"
+// @Success 	204
+// @Router 		/password/reset [post]
+func (controller *AccountController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+
+	var input models.ResetInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.ResetPassword(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+	"user-management/middleware"
+	"user-management/models"
+	"user-management/services"
+	"user-management/utils"
+
+	"github.com/unrolled/render"
+)
+
+type clientService interface {
+	Register(input *models.RegisterClientInput) (models.ClientSecret, error)
+	GetAll() ([]models.Client, error)
+	Get(clientID string) (models.Client, error)
+	Update(clientID string, input *models.ClientInput) (models.Client, error)
+	RotateSecret(clientID string) (models.ClientSecret, error)
+	Delete(clientID string) error
+	Authorize(input *models.AuthorizeInput) (models.Authorization, error)
+}
+
+type ClientController struct {
+	service clientService
+}
+
+// InitClientController initializes the client controller.
+func InitClientController(clientSvc *services.ClientService) *ClientController {
+	return &ClientController{
+		service: clientSvc,
+	}
+}
+
+// Register Client godoc
+// @Summary 	Registers an OAuth Client, the secret of confidential ones only shown now
+// @Tags 		client
+// @Produce 	json
+// @Param 		data body models.RegisterClientInput true "The client id, name, grant types, scopes and redirect URIs"
+// @Success 	201 {object} models.ClientSecret
+// @Router 		/clients [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) Register(w http.ResponseWriter, r *http.Request) {
+
+	var input models.RegisterClientInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	client, err := controller.service.Register(&input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusCreated, client)
+}
+
+// Get Clients godoc
+// @Summary 	Fetches all OAuth Clients, by client id
+// @Tags 		client
+// @Produce 	json
+// @Success 	200 {array} models.Client
+// @Router 		/clients [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) GetAll(w http.ResponseWriter, r *http.Request) {
+
+	clients, err := controller.service.GetAll()
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, clients)
+}
+
+// Get Client godoc
+// @Summary 	Fetches a specific OAuth Client using its id
+// @Tags 		client
+// @Produce 	json
+// @Param 		id path string true "The client id"
+// @Success 	200 {object} models.Client
+// @Router 		/clients/{id} [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) Get(w http.ResponseWriter, r *http.Request) {
+
+	client, err := controller.service.Get(utils.GetFieldFromURL(r, "id"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, client)
+}
+
+// Update Client godoc
+// @Summary 	Updates the name, grant types, scopes and redirect URIs of an OAuth Client
+// @Tags 		client
+// @Produce 	json
+// @Param 		id path string true "The client id"
+// @Param 		data body models.ClientInput true "The name, grant types, scopes and redirect URIs"
+// @Success 	200 {object} models.Client
+// @Router 		/clients/{id} [put]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) Update(w http.ResponseWriter, r *http.Request) {
+
+	var input models.ClientInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	client, err := controller.service.Update(utils.GetFieldFromURL(r, "id"), &input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, client)
+}
+
+// Rotate Client Secret godoc
+// @Summary 	Replaces the secret of a confidential OAuth Client, the new one only shown now
+// @Tags 		client
+// @Produce 	json
+// @Param 		id path string true "The client id"
+// @Success 	200 {object} models.ClientSecret
+// @Router 		/clients/{id}/secret [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) RotateSecret(w http.ResponseWriter, r *http.Request) {
+
+	client, err := controller.service.RotateSecret(utils.GetFieldFromURL(r, "id"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, client)
+}
+
+// Delete Client godoc
+// @Summary 	Deletes a specific OAuth Client, which can no longer get tokens
+// @Tags 		client
+// @Param 		id path string true "The client id"
+// @Success 	204
+// @Router 		/clients/{id} [delete]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *ClientController) Delete(w http.ResponseWriter, r *http.Request) {
+
+	if err := controller.service.Delete(utils.GetFieldFromURL(r, "id")); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, nil)
+}
+
+// Authorize godoc
+// @Summary 	Logs the User in to authorize an OAuth Client, returning the code it exchanges at /oauth/token with its PKCE verifier
+// @Tags 		oauth
+// @Produce 	json
+// @Param 		data body models.AuthorizeInput true "The client, redirect URI, scope, state, S256 code challenge and the user credentials"
+// @Success 	200 {object} models.Authorization
+// @Router 		/oauth/authorize [post]
+func (controller *ClientController) Authorize(w http.ResponseWriter, r *http.Request) {
+
+	var input models.AuthorizeInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	authorization, err := controller.service.Authorize(&input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, authorization)
+}
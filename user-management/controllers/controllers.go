@@ -5,6 +5,9 @@ import "user-management/services"
 // Controllers contains all the controllers
 type Controllers struct {
 	UserController     *UserController
+	AccountController  *AccountController
+	SessionController  *SessionController
+	ClientController   *ClientController
 	VerifierController VerifierController
 }
 
@@ -12,6 +15,9 @@ type Controllers struct {
 func InitControllers(services *services.Services) *Controllers {
 	return &Controllers{
 		UserController:     InitUserController(services.UserService),
-		VerifierController: InitVerifierController(services.UserService),
+		AccountController:  InitAccountController(services.AccountService),
+		SessionController:  InitSessionController(services.SessionService),
+		ClientController:   InitClientController(services.ClientService),
+		VerifierController: InitVerifierController(services.UserService, services.SessionService, services.ClientService),
 	}
 }
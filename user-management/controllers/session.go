@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+	"user-management/middleware"
+	"user-management/services"
+	"user-management/utils"
+
+	"github.com/unrolled/render"
+)
+
+type sessionService interface {
+	Logout(username, tokenID string) error
+	LogoutAll(username string) error
+}
+
+type SessionController struct {
+	service sessionService
+}
+
+// InitSessionController initializes the session controller.
+func InitSessionController(sessionSvc *services.SessionService) *SessionController {
+	return &SessionController{
+		service: sessionSvc,
+	}
+}
+
+// Logout godoc
+// @Summary 	Revokes the refresh token of the access token, which still works until it expires
+// @Tags 		session
+// @Success 	204
+// @Router 		/logout [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *SessionController) Logout(w http.ResponseWriter, r *http.Request) {
+
+	// Get username and token id from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+	tokenID, err := utils.GetTokenIDFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.Logout(username, tokenID); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, nil)
+}
+
+// Logout Everywhere godoc
+// @Summary 	Revokes the refresh tokens of every session of the User of the token
+// @Tags 		session
+// @Success 	204
+// @Router 		/logout/all [post]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *SessionController) LogoutAll(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.LogoutAll(username); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, nil)
+}
@@ -13,7 +13,11 @@ import (
 type userService interface {
 	Register(user *models.User) error
 	GetAll(sort string) ([]models.User, error)
+	Get(username string) (models.User, error)
 	Login(username, password string) error
+	ChangeEmail(username string, input *models.EmailInput) (models.User, error)
+	ChangePassword(username string, input *models.PasswordInput) error
+	DeleteAccount(username string, input *models.DeleteInput) error
 	Delete(name string) error
 }
 
@@ -30,55 +34,220 @@ func InitUserController(userSvc *services.UserService) *UserController {
 
 // Register User godoc
 // @Summary 	Registers a User
-// @Tags 		tags
+// @Tags 		user
 // @Produce 	json
-// @Param 		data body models.User true "The User name"
+// @Param 		data body models.RegisterInput true "The username, email and password"
 // @Success 	200 {object} models.User
-// @Router 		/user [post]
+// @Router 		/register [post]
 func (controller *UserController) Register(w http.ResponseWriter, r *http.Request) {
 
-	var user models.User
-	if err := utils.DecodeJSONBody(w, r, &user); err != nil {
+	var input models.RegisterInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
 		middleware.ErrorHandler(w, err)
 		return
 	}
 
-	controller.service.Register(&user)
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	user := models.NewUser(&input)
+	if err := controller.service.Register(user); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
 
 	render.New().JSON(w, http.StatusOK, user)
 }
 
-// Get Tags godoc
-// @Summary 	Fetches all Tags
-// @Tags 		tags
+// Get Users godoc
+// @Summary 	Fetches all Users, by username
+// @Tags 		user
 // @Produce 	json
-// @Success 	200 {object} models.User
+// @Success 	200 {array} models.User
 // @Router 		/user [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
 func (controller *UserController) GetAll(w http.ResponseWriter, r *http.Request) {
 
-	render.New().JSON(w, http.StatusOK, "user")
+	users, err := controller.service.GetAll("username asc")
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, users)
 }
 
 // Get User godoc
 // @Summary 	Fetches a specific User using a name
-// @Tags 		tags
+// @Tags 		user
 // @Produce 	json
 // @Param 		name path string true "The User name"
 // @Success 	200 {object} models.User
 // @Router 		/user/{name} [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
 func (controller *UserController) Get(w http.ResponseWriter, r *http.Request) {
 
-	render.New().JSON(w, http.StatusOK, "user")
+	user, err := controller.service.Get(utils.GetFieldFromURL(r, "name"))
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, user)
 }
 
 // Delete User godoc
 // @Summary 	Deletes a specific User
-// @Tags 		tags
-// @Produce 	json
+// @Tags 		user
 // @Param 		name path string true "The User name"
 // @Success 	204
 // @Router 		/user/{name} [delete]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
 func (controller *UserController) Delete(w http.ResponseWriter, r *http.Request) {
 
-	render.New().JSON(w, http.StatusNoContent, "user")
+	name := utils.GetFieldFromURL(r, "name")
+	if err := controller.service.Delete(name); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, name)
+}
+
+// Get Profile godoc
+// @Summary 	Fetches the User of the token
+// @Tags 		user
+// @Produce 	json
+// @Success 	200 {object} models.User
+// @Router 		/user/me [get]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *UserController) GetProfile(w http.ResponseWriter, r *http.Request) {
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	user, err := controller.service.Get(username)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, user)
+}
+
+// Change Email godoc
+// @Summary 	Changes the email of the User of the token
+// @Tags 		user
+// @Produce 	json
+// @Param 		data body models.EmailInput true "The new email and the current password"
+// @Success 	200 {object} models.User
+// @Router 		/user/me/email [put]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *UserController) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+
+	var input models.EmailInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	user, err := controller.service.ChangeEmail(username, &input)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusOK, user)
+}
+
+// Change Password godoc
+// @Summary 	Changes the password of the User of the token
+// @Tags 		user
+// @Param 		data body models.PasswordInput true "The current and the new password"
+// @Success 	204
+// @Router 		/user/me/password [put]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *UserController) ChangePassword(w http.ResponseWriter, r *http.Request) {
+
+	var input models.PasswordInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.ChangePassword(username, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, username)
+}
+
+// Delete Account godoc
+// @Summary 	Deletes the User of the token
+// @Tags 		user
+// @Param 		data body models.DeleteInput true "The current password"
+// @Success 	204
+// @Router 		/user/me [delete]
+// @Param 		Authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+func (controller *UserController) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+
+	var input models.DeleteInput
+	if err := utils.DecodeJSONBody(w, r, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Validate input
+	if err := utils.ValidateStruct(&input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	// Get username from oauth Token
+	username, err := utils.GetUsernameFromToken(r)
+	if err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	if err := controller.service.DeleteAccount(username, &input); err != nil {
+		middleware.ErrorHandler(w, err)
+		return
+	}
+
+	render.New().JSON(w, http.StatusNoContent, username)
 }
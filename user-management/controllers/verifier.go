@@ -1,21 +1,36 @@
 package controllers
 
 import (
-	"errors"
-	"log"
 	"net/http"
+	"strconv"
+	"user-management/middleware"
+	"user-management/models"
 	"user-management/services"
 
 	"github.com/go-chi/oauth"
 )
 
+type sessionStore interface {
+	Store(tokenType, credential, tokenID, refreshTokenID string) error
+	Validate(credential, tokenID, refreshTokenID string) error
+}
+
+type clientAuthenticator interface {
+	Authenticate(clientID, secret, grant string) (models.Client, error)
+	Exchange(clientID, secret, code, redirectURI, verifier, scope string) (string, error)
+}
+
 type VerifierController struct {
-	userService userService
+	userService    userService
+	sessionService sessionStore
+	clientService  clientAuthenticator
 }
 
-func InitVerifierController(userSvc *services.UserService) VerifierController {
+func InitVerifierController(userSvc *services.UserService, sessionSvc *services.SessionService, clientSvc *services.ClientService) VerifierController {
 	return VerifierController{
-		userService: userSvc,
+		userService:    userSvc,
+		sessionService: sessionSvc,
+		clientService:  clientSvc,
 	}
 }
 
@@ -25,24 +40,44 @@ func (service *VerifierController) ValidateUser(username, password, scope string
 	return service.userService.Login(username, password)
 }
 
-// ValidateClient validates clientID and secret returning an error if the client credentials are wrong
-func (*VerifierController) ValidateClient(clientID, clientSecret, scope string, r *http.Request) error {
-	if clientID == "id" && clientSecret == "secret" {
-		return nil
+// ValidateClient validates the registered client may use the client credentials grant for the scope
+func (service *VerifierController) ValidateClient(clientID, clientSecret, scope string, r *http.Request) error {
+	client, err := service.clientService.Authenticate(clientID, clientSecret, models.GrantClientCredentials)
+	if err != nil {
+		return err
 	}
 
-	return errors.New("wrong client")
+	if !client.AllowsScope(scope) {
+		return middleware.NewError(http.StatusUnauthorized, "Scope is not allowed for the client")
+	}
+	return nil
 }
 
-// ValidateCode validates token ID
-func (*VerifierController) ValidateCode(clientID, clientSecret, code, redirectURI string, r *http.Request) (string, error) {
-	return "WHAT AM I DOING", nil
+// ValidateCode exchanges the authorization code with its PKCE verifier, returning the user who granted it
+func (service *VerifierController) ValidateCode(clientID, clientSecret, code, redirectURI string, r *http.Request) (string, error) {
+	return service.clientService.Exchange(clientID, clientSecret, code, redirectURI, r.FormValue("code_verifier"), r.FormValue("scope"))
 }
 
 // AddClaims provides additional claims to the token
-func (*VerifierController) AddClaims(tokenType oauth.TokenType, credential, tokenID, scope string, r *http.Request) (map[string]string, error) {
+func (service *VerifierController) AddClaims(tokenType oauth.TokenType, credential, tokenID, scope string, r *http.Request) (map[string]string, error) {
 	claims := make(map[string]string)
+	claims["token_id"] = tokenID // Names the session to log out of
+
+	// Client tokens name the client, so they are never mistaken for a user of the same name
+	if tokenType == oauth.ClientToken {
+		claims["client_id"] = credential
+		return claims, nil
+	}
 	claims["username"] = credential
+
+	user, err := service.userService.Get(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other services grant access by these claims (E.g marketplace moderation)
+	claims["role"] = user.Role
+	claims["email_verified"] = strconv.FormatBool(user.EmailVerified)
 	return claims, nil
 }
 
@@ -52,13 +87,12 @@ func (*VerifierController) AddProperties(tokenType oauth.TokenType, credential,
 	return props, nil
 }
 
-// ValidateTokenID validates token ID
-func (*VerifierController) ValidateTokenID(tokenType oauth.TokenType, credential, tokenID, refreshTokenID string) error {
-	log.Println(credential)
-	return nil
+// ValidateTokenID uses up the session of the refresh token, rejecting revoked and expired ones
+func (service *VerifierController) ValidateTokenID(tokenType oauth.TokenType, credential, tokenID, refreshTokenID string) error {
+	return service.sessionService.Validate(credential, tokenID, refreshTokenID)
 }
 
-// StoreTokenID saves the token id generated for the user
-func (*VerifierController) StoreTokenID(tokenType oauth.TokenType, credential, tokenID, refreshTokenID string) error {
-	return nil
+// StoreTokenID saves the session of the tokens generated for the user
+func (service *VerifierController) StoreTokenID(tokenType oauth.TokenType, credential, tokenID, refreshTokenID string) error {
+	return service.sessionService.Store(string(tokenType), credential, tokenID, refreshTokenID)
 }
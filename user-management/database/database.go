@@ -35,6 +35,10 @@ func Connect() (*PostgresqlRepository, error) {
 	log.Println("Connected to the Database")
 
 	migrate(db, &models.User{})
+	migrate(db, &models.UserToken{})
+	migrate(db, &models.Session{})
+	migrate(db, &models.Client{})
+	migrate(db, &models.AuthorizationCode{})
 
 	log.Println("Database Migration Completed")
 
@@ -114,6 +118,27 @@ func (instance *PostgresqlRepository) Read(value interface{}, sort, search, iden
 	return nil
 }
 
+// Method that runs a raw query, scanning its rows into value -> Only for what the generic methods can't express (E.g Updates returning rows)
+func (instance *PostgresqlRepository) Raw(value interface{}, query string, values ...interface{}) error {
+
+	if err := instance.db.Raw(query, values...).Scan(value).Error; err != nil {
+		log.Println("Error while running a raw query: " + query)
+		return err
+	}
+	return nil
+}
+
+// Method that runs a raw statement, returning how many rows it affected -> Only for what the generic methods can't express (E.g Bulk updates)
+func (instance *PostgresqlRepository) Exec(query string, values ...interface{}) (int64, error) {
+
+	result := instance.db.Exec(query, values...)
+	if result.Error != nil {
+		log.Println("Error while running a raw statement: " + query)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (instance *PostgresqlRepository) Update(value interface{}) error {
 	result := instance.db.Save(value)
 	if result.Error != nil {
@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FileMailer writes the emails as .eml files of a directory, so local runs can read them
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer creates the directory when missing and writes emails in it
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Println("Error creating mail directory: " + err.Error())
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (fm *FileMailer) Send(message Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+
+	// Named by when they were sent, as recipients don't make safe file names
+	file, err := os.CreateTemp(fm.dir, strconv.FormatInt(time.Now().UnixNano(), 10)+"-*.eml")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(message.format(fm.from)); err != nil {
+		return err
+	}
+
+	log.Println("Wrote mail to " + message.To + " into " + filepath.Base(file.Name()))
+	return nil
+}
+
+// MemoryMailer keeps the emails in memory, so tests can read them
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (mm *MemoryMailer) Send(message Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.messages = append(mm.messages, message)
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first
+func (mm *MemoryMailer) Messages() []Message {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return append([]Message(nil), mm.messages...)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"errors"
+	"strings"
+)
+
+// Mailer delivers the emails of the service, wherever they go
+type Mailer interface {
+	Send(message Message) error
+}
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Headers can't span lines, which would let their values add headers of their own
+func (message *Message) validate() error {
+	if strings.ContainsAny(message.To, "\r\n") || strings.ContainsAny(message.Subject, "\r\n") {
+		return errors.New("mail headers can't contain line breaks")
+	}
+	return nil
+}
+
+// Function that writes the message as an email, with its headers first
+func (message *Message) format(from string) []byte {
+	return []byte("From: " + from + "\r\n" +
+		"To: " + message.To + "\r\n" +
+		"Subject: " + message.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(message.Body, "\n", "\r\n"))
+}
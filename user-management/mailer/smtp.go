@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"log"
+	"net"
+	"net/smtp"
+)
+
+// SMTPMailer sends the emails through an SMTP server
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer authenticates with PLAIN auth, unless the username is empty
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (sm *SMTPMailer) Send(message Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(sm.addr, sm.auth, sm.from, []string{message.To}, message.format(sm.from)); err != nil {
+		log.Println("Error sending mail through " + sm.addr + ": " + err.Error())
+		return err
+	}
+	return nil
+}
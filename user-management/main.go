@@ -1,15 +1,21 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"user-management/controllers"
 	"user-management/database"
+	"user-management/mailer"
+	"user-management/models"
 
 	"user-management/repositories"
+	route "user-management/routers"
 	"user-management/services"
 
 	"github.com/go-chi/chi/v5"
@@ -32,11 +38,57 @@ func main() {
 		return
 	}
 
+	// Fetch password policy, at least 8 characters with a lowercase letter and a digit by default
+	passwordPolicy, err := getPasswordPolicy()
+	if err != nil {
+		log.Println("Error occurred while parsing the password policy: " + err.Error())
+		return
+	}
+
+	// Fetch the secret the mailed tokens are signed with
+	tokenSecret, tokenSecretPresent := os.LookupEnv("TOKEN_SECRET")
+	if !tokenSecretPresent || tokenSecret == "" {
+		log.Println("Error occurred while fetching Token Secret")
+		return
+	}
+
+	// Links of the emails lead to the frontend
+	appURL, appURLPresent := os.LookupEnv("APP_URL")
+	if !appURLPresent {
+		appURL = "http://localhost:3000"
+	}
+
+	mail, err := getMailer()
+	if err != nil {
+		log.Println("Error occurred while preparing the mailer: " + err.Error())
+		return
+	}
+
+	refreshTTL, err := getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil || refreshTTL <= 0 {
+		log.Println("Error occurred while parsing REFRESH_TOKEN_TTL")
+		return
+	}
+	sweepInterval, err := getDuration("SESSION_SWEEP_INTERVAL", time.Hour)
+	if err != nil || sweepInterval <= 0 {
+		log.Println("Error occurred while parsing SESSION_SWEEP_INTERVAL")
+		return
+	}
+
 	// Initialize Repositories & Services & controllers
 	repositories := repositories.InitRepositories(db)
-	services := services.InitServices(repositories)
+	services := services.InitServices(repositories, passwordPolicy, mail, tokenSecret, appURL, refreshTTL)
 	controllers := controllers.InitControllers(services)
 
+	// Registers the frontend as a public client, so it logs users in with the authorization code grant
+	if err := bootstrapFrontendClient(services.ClientService); err != nil {
+		log.Println("Error occurred while registering the frontend client: " + err.Error())
+		return
+	}
+
+	// Deletes expired sessions, mailed tokens and authorization codes in the background
+	go services.SessionService.Sweep(sweepInterval)
+
 	// Creates routing
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
@@ -51,6 +103,11 @@ func main() {
 	router.Post("/api/register", controllers.UserController.Register)
 	router.Post("/api/login", oauthServer.UserCredentials)
 	router.Post("/api/auth", oauthServer.ClientCredentials)
+	router.Post("/api/oauth/token", oauthServer.AuthorizationCode)
+	route.AddUserRouter(router, oauthKey, controllers.UserController)
+	route.AddAccountRouter(router, oauthKey, controllers.AccountController)
+	route.AddSessionRouter(router, oauthKey, controllers.SessionController)
+	route.AddClientRouter(router, oauthKey, controllers.ClientController)
 
 	// Starts server
 	port, portPresent := os.LookupEnv("PORT")
@@ -65,3 +122,95 @@ func main() {
 	}
 	log.Println("Server is Running on localhost:" + port)
 }
+
+// Mails are sent through SMTP, written into MAIL_DIR (the default) or kept in memory, as MAILER says
+func getMailer() (mailer.Mailer, error) {
+	from, fromPresent := os.LookupEnv("MAIL_FROM")
+	if !fromPresent {
+		from = "no-reply@localhost"
+	}
+
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		host, hostPresent := os.LookupEnv("SMTP_HOST")
+		if !hostPresent {
+			return nil, errors.New("SMTP_HOST is required by the smtp mailer")
+		}
+		port, portPresent := os.LookupEnv("SMTP_PORT")
+		if !portPresent {
+			port = "587"
+		}
+		return mailer.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	case "memory":
+		return mailer.NewMemoryMailer(), nil
+	case "", "file":
+		dir, dirPresent := os.LookupEnv("MAIL_DIR")
+		if !dirPresent {
+			dir = "mail"
+		}
+		return mailer.NewFileMailer(dir, from)
+	}
+	return nil, errors.New("unknown MAILER " + os.Getenv("MAILER") + ", should be smtp, file or memory")
+}
+
+// FRONTEND_CLIENT_ID names the public client, allowed the comma separated FRONTEND_REDIRECT_URIS, registered when missing
+func bootstrapFrontendClient(clientService *services.ClientService) error {
+	clientID, clientIDPresent := os.LookupEnv("FRONTEND_CLIENT_ID")
+	if !clientIDPresent || clientID == "" {
+		return nil
+	}
+
+	var redirectURIs []string
+	for _, uri := range strings.Split(os.Getenv("FRONTEND_REDIRECT_URIS"), ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			redirectURIs = append(redirectURIs, uri)
+		}
+	}
+	return clientService.Bootstrap(clientID, "Frontend", redirectURIs)
+}
+
+func getPasswordPolicy() (models.PasswordPolicy, error) {
+	var policy models.PasswordPolicy
+	var err error
+
+	if policy.MinLength, err = getInt("PASSWORD_MIN_LENGTH", 8); err != nil {
+		return policy, err
+	}
+	if policy.RequireUpper, err = getBool("PASSWORD_REQUIRE_UPPER", false); err != nil {
+		return policy, err
+	}
+	if policy.RequireLower, err = getBool("PASSWORD_REQUIRE_LOWER", true); err != nil {
+		return policy, err
+	}
+	if policy.RequireDigit, err = getBool("PASSWORD_REQUIRE_DIGIT", true); err != nil {
+		return policy, err
+	}
+	if policy.RequireSymbol, err = getBool("PASSWORD_REQUIRE_SYMBOL", false); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return fallback, nil
+	}
+	return time.ParseDuration(value)
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value, present := os.LookupEnv(key)
+	if !present {
+		return fallback, nil
+	}
+	return strconv.ParseBool(value)
+}
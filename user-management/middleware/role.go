@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/oauth"
+)
+
+// RequireRole lets through only the requests whose token has one of the roles -> Must run after oauth.Authorize
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	granted := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		granted[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+			if !granted[claims["role"]] {
+				log.Println("Error - Endpoint of roles " + strings.Join(roles, ",") + " requested by " + claims["username"])
+				ErrorHandler(w, NewError(http.StatusForbidden, "Only "+strings.Join(roles, " or ")+" users can do this"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
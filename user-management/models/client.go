@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Grant types clients can be allowed -> Refreshing follows the session, whichever client started it
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// PKCE is only taken with the S256 code challenge method
+const CodeChallengeS256 = "S256"
+
+// Client is an application registered to get tokens, either confidential with a secret or public (E.g the frontend) with PKCE alone
+type Client struct {
+	ID           uint      `json:"-" gorm:"primarykey"`
+	ClientID     string    `json:"client_id" gorm:"uniqueIndex;not null"`
+	Name         string    `json:"name" gorm:"not null"`
+	SecretHash   string    `json:"-"` // Empty for public clients
+	Public       bool      `json:"public" gorm:"not null;default:false"`
+	GrantTypes   []string  `json:"grant_types" gorm:"serializer:json;not null"`
+	Scopes       []string  `json:"scopes" gorm:"serializer:json;not null"`        // The ones it can ask for
+	RedirectURIs []string  `json:"redirect_uris" gorm:"serializer:json;not null"` // Matched exactly by authorization requests
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ClientInput is what admins can change of a Client
+type ClientInput struct {
+	Name         string   `json:"name" valid:"required, maxstringlength(100)"`
+	GrantTypes   []string `json:"grant_types" valid:"required"`
+	Scopes       []string `json:"scopes"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// RegisterClientInput is the body of a new Client, whose id and kind never change
+type RegisterClientInput struct {
+	ClientID string `json:"client_id" valid:"required, username"`
+	Public   bool   `json:"public"`
+	ClientInput
+}
+
+// ClientSecret is a confidential Client along with its secret, only shown once when generated
+type ClientSecret struct {
+	Client
+	Secret string `json:"client_secret"`
+}
+
+// AuthorizationCode is a code a user authorized a Client with, exchanged once for tokens -> Only the digest of the code is kept
+type AuthorizationCode struct {
+	ID            uint      `gorm:"primarykey"`
+	Digest        string    `gorm:"uniqueIndex;not null"`
+	ClientID      string    `gorm:"not null"`
+	Username      string    `gorm:"not null"`
+	RedirectURI   string    `gorm:"not null"`
+	Scope         string    `gorm:"not null"`
+	CodeChallenge string    `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"index;not null"`
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// AuthorizeInput is the body of an authorization request, which the user logs in to with their credentials
+type AuthorizeInput struct {
+	ClientID            string `json:"client_id" valid:"required"`
+	RedirectURI         string `json:"redirect_uri" valid:"required"`
+	Scope               string `json:"scope"` // Space separated
+	State               string `json:"state" valid:"maxstringlength(500)"`
+	CodeChallenge       string `json:"code_challenge" valid:"required, length(43|128)"`
+	CodeChallengeMethod string `json:"code_challenge_method" valid:"required"`
+	Username            string `json:"username" valid:"required"`
+	Password            string `json:"password" valid:"required"`
+}
+
+// Authorization is the code the Client gets back, along with where to redirect the user to
+type Authorization struct {
+	Code        string `json:"code"`
+	State       string `json:"state,omitempty"`
+	RedirectURI string `json:"redirect_uri"` // The registered redirect URI with the code and state added
+}
+
+// RequestsGrant tells if the grant type is among the ones asked for
+func (input *ClientInput) RequestsGrant(grant string) bool {
+	return contains(input.GrantTypes, grant)
+}
+
+func (client *Client) AllowsGrant(grant string) bool {
+	return contains(client.GrantTypes, grant)
+}
+
+func (client *Client) AllowsRedirectURI(uri string) bool {
+	return contains(client.RedirectURIs, uri)
+}
+
+// AllowsScope tells if every space separated scope was granted to the Client
+func (client *Client) AllowsScope(scope string) bool {
+	for _, requested := range strings.Fields(scope) {
+		if !contains(client.Scopes, requested) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, known := range values {
+		if known == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"user-management/middleware"
+)
+
+const maxPasswordLength = 72 // Bcrypt ignores whatever comes after
+
+// PasswordPolicy is how strong passwords must be, set through the env vars
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Check tells which rules of the policy the password breaks, if any
+func (policy *PasswordPolicy) Check(password string) error {
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	var broken []string
+	if len([]rune(password)) < policy.MinLength {
+		broken = append(broken, "at least "+strconv.Itoa(policy.MinLength)+" characters")
+	}
+	if len(password) > maxPasswordLength {
+		broken = append(broken, "at most "+strconv.Itoa(maxPasswordLength)+" bytes")
+	}
+	if policy.RequireUpper && !upper {
+		broken = append(broken, "an uppercase letter")
+	}
+	if policy.RequireLower && !lower {
+		broken = append(broken, "a lowercase letter")
+	}
+	if policy.RequireDigit && !digit {
+		broken = append(broken, "a digit")
+	}
+	if policy.RequireSymbol && !symbol {
+		broken = append(broken, "a symbol")
+	}
+
+	if len(broken) > 0 {
+		log.Println("Error - Password breaks the policy")
+		return middleware.NewError(http.StatusUnprocessableEntity, "Password should have "+strings.Join(broken, ", "))
+	}
+	return nil
+}
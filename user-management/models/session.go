@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Session is an access and refresh token pair issued to a credential, kept until its refresh token expires
+// Refreshing uses the session up, issuing the next one -> Revoked sessions can't be refreshed
+type Session struct {
+	ID             uint      `gorm:"primarykey"`
+	TokenID        string    `gorm:"uniqueIndex;not null"`
+	RefreshTokenID string    `gorm:"uniqueIndex;not null"`
+	Credential     string    `gorm:"index;not null"` // Username, or client id of client tokens
+	TokenType      string    `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"index;not null"` // Of the refresh token
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
@@ -3,17 +3,60 @@ package models
 import (
 	"log"
 	"net/http"
+	"strings"
 	"user-management/middleware"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// Roles of users, added to their token as the role claim
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator" // Moderates the offers of the marketplace
+	RoleAdmin     = "admin"
+)
+
 type User struct {
-	gorm.Model `json:"-"`
-	Username   string `json:"username" gorm:"unique"`
-	Email      string `json:"email" gorm:"unique"`
-	Password   string `json:"password"`
+	gorm.Model    `json:"-"`
+	Username      string `json:"username" gorm:"unique"`
+	Email         string `json:"email" gorm:"unique"`
+	Password      string `json:"-"` // Hash of the password, never sent
+	Role          string `json:"role" gorm:"not null;default:user"`
+	EmailVerified bool   `json:"email_verified" gorm:"not null;default:false"` // Once a token mailed to the email came back
+}
+
+// RegisterInput is the body of a new User
+type RegisterInput struct {
+	Username string `json:"username" valid:"required, username"`
+	Email    string `json:"email" valid:"required, email, maxstringlength(254)"`
+	Password string `json:"password" valid:"required"` // Checked against the PasswordPolicy
+}
+
+// EmailInput is the body of an email change, which takes the current password
+type EmailInput struct {
+	Email           string `json:"email" valid:"required, email, maxstringlength(254)"`
+	CurrentPassword string `json:"current_password" valid:"required"`
+}
+
+// PasswordInput is the body of a password change, which takes the current password
+type PasswordInput struct {
+	CurrentPassword string `json:"current_password" valid:"required"`
+	NewPassword     string `json:"new_password" valid:"required"` // Checked against the PasswordPolicy
+}
+
+// DeleteInput is the body of an account deletion, which takes the current password
+type DeleteInput struct {
+	CurrentPassword string `json:"current_password" valid:"required"`
+}
+
+// NewUser builds the User out of its input, leaving the password to be hashed
+func NewUser(input *RegisterInput) *User {
+	return &User{
+		Username: input.Username,
+		Email:    strings.ToLower(input.Email),
+		Password: input.Password,
+	}
 }
 
 func (user *User) GetPassword() string {
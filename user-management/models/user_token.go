@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Purposes of the tokens mailed to users
+const (
+	TokenVerifyEmail   = "verify"
+	TokenResetPassword = "reset"
+)
+
+// UserToken is a token mailed to a user, used at most once before it expires -> Only the digest of its secret part is kept
+type UserToken struct {
+	ID        uint      `gorm:"primarykey"`
+	Digest    string    `gorm:"uniqueIndex;not null"`
+	Purpose   string    `gorm:"not null"`
+	Username  string    `gorm:"index;not null"`
+	Email     string    `gorm:"not null"` // Where it was mailed, so it only proves that address
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// VerifyInput is the body of an email verification
+type VerifyInput struct {
+	Token string `json:"token" valid:"required"`
+}
+
+// ForgotInput is the body of a password reset request
+type ForgotInput struct {
+	Email string `json:"email" valid:"required, email"`
+}
+
+// ResetInput is the body of a password reset
+type ResetInput struct {
+	Token       string `json:"token" valid:"required"`
+	NewPassword string `json:"new_password" valid:"required"` // Checked against the PasswordPolicy
+}
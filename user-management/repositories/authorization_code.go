@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"log"
+	"net/http"
+	"user-management/database"
+	"user-management/middleware"
+	"user-management/models"
+)
+
+type AuthorizationCodeRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewAuthorizationCodeRepository(instance *database.PostgresqlRepository) *AuthorizationCodeRepository {
+	return &AuthorizationCodeRepository{
+		db: instance,
+	}
+}
+
+func (repo *AuthorizationCodeRepository) Create(code *models.AuthorizationCode) error {
+
+	return repo.db.Create(code)
+}
+
+// Uses the code of the client in one statement, so it can't be exchanged twice however many requests race for it
+func (repo *AuthorizationCodeRepository) Consume(digest, clientID string) (models.AuthorizationCode, error) {
+
+	var code models.AuthorizationCode
+	query := `UPDATE authorization_codes SET used_at = now() WHERE digest = ? AND client_id = ? AND used_at IS NULL AND expires_at > now() RETURNING *`
+	if err := repo.db.Raw(&code, query, digest, clientID); err != nil {
+		return models.AuthorizationCode{}, err
+	}
+
+	if code.ID == 0 {
+		log.Println("Error - Authorization code unknown, expired or already used by " + clientID)
+		return models.AuthorizationCode{}, middleware.NewError(http.StatusUnauthorized, "Authorization code is invalid, expired or already used")
+	}
+	return code, nil
+}
+
+// Deletes the expired codes, used or not, returning how many there were
+func (repo *AuthorizationCodeRepository) DeleteExpired() (int64, error) {
+
+	return repo.db.Exec(`DELETE FROM authorization_codes WHERE expires_at < now()`)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"user-management/database"
+	"user-management/middleware"
+	"user-management/models"
+
+	"github.com/jackc/pgconn"
+)
+
+type ClientRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewClientRepository(instance *database.PostgresqlRepository) *ClientRepository {
+	return &ClientRepository{
+		db: instance,
+	}
+}
+
+func (repo *ClientRepository) Create(client *models.Client) error {
+
+	if err := repo.db.Create(client); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			log.Println("Error - Duplicate client " + client.ClientID)
+			return middleware.NewError(http.StatusConflict, "Client id already registered")
+		}
+		return err
+	}
+	return nil
+}
+
+func (repo *ClientRepository) GetAll() ([]models.Client, error) {
+
+	var clients []models.Client
+	return clients, repo.db.Read(&clients, "client_id asc", "", "")
+}
+
+func (repo *ClientRepository) Get(clientID string) (models.Client, error) {
+
+	var client models.Client
+	return client, repo.db.Read(&client, "", "client_id = ?", clientID)
+}
+
+func (repo *ClientRepository) Update(client *models.Client) error {
+
+	return repo.db.Update(client)
+}
+
+func (repo *ClientRepository) Delete(client *models.Client) error {
+
+	return repo.db.Delete(client)
+}
@@ -6,14 +6,26 @@ import (
 
 // Repositories contains all the repo structs
 type Repositories struct {
-	UserRepository *UserRepository
+	UserRepository      *UserRepository
+	UserTokenRepository *UserTokenRepository
+	SessionRepository   *SessionRepository
+	ClientRepository    *ClientRepository
+	CodeRepository      *AuthorizationCodeRepository
 }
 
 // InitRepositories should be called in main.go
 func InitRepositories(db *database.PostgresqlRepository) *Repositories {
 	userRepository := NewUserRepository(db)
+	userTokenRepository := NewUserTokenRepository(db)
+	sessionRepository := NewSessionRepository(db)
+	clientRepository := NewClientRepository(db)
+	codeRepository := NewAuthorizationCodeRepository(db)
 
 	return &Repositories{
-		UserRepository: userRepository,
+		UserRepository:      userRepository,
+		UserTokenRepository: userTokenRepository,
+		SessionRepository:   sessionRepository,
+		ClientRepository:    clientRepository,
+		CodeRepository:      codeRepository,
 	}
 }
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"log"
+	"net/http"
+	"user-management/database"
+	"user-management/middleware"
+	"user-management/models"
+
+	"github.com/go-chi/oauth"
+)
+
+// Sessions of users, which their usernames name -> Client sessions are named by client id, which can equal a username
+const userSessions = "token_type <> '" + string(oauth.ClientToken) + "'"
+
+type SessionRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewSessionRepository(instance *database.PostgresqlRepository) *SessionRepository {
+	return &SessionRepository{
+		db: instance,
+	}
+}
+
+func (repo *SessionRepository) Create(session *models.Session) error {
+
+	return repo.db.Create(session)
+}
+
+// Uses the session up in one statement, so its refresh token can't be used twice however many requests race for it
+func (repo *SessionRepository) Consume(credential, tokenID, refreshTokenID string) error {
+
+	query := `UPDATE sessions SET revoked_at = now()
+		WHERE refresh_token_id = ? AND token_id = ? AND credential = ? AND revoked_at IS NULL AND expires_at > now()`
+	consumed, err := repo.db.Exec(query, refreshTokenID, tokenID, credential)
+	if err != nil {
+		return err
+	}
+
+	if consumed == 0 {
+		log.Println("Error - Refresh token unknown, expired or revoked for " + credential)
+		return middleware.NewError(http.StatusUnauthorized, "Refresh token is invalid, expired or revoked")
+	}
+	return nil
+}
+
+// Revokes the session of the user access token -> Revoking it twice is the same as once
+func (repo *SessionRepository) Revoke(username, tokenID string) error {
+
+	_, err := repo.db.Exec(`UPDATE sessions SET revoked_at = now() WHERE token_id = ? AND credential = ? AND `+userSessions+` AND revoked_at IS NULL`, tokenID, username)
+	return err
+}
+
+// Revokes every session of the user, returning how many there were -> Clients of the same name keep theirs
+func (repo *SessionRepository) RevokeAll(username string) (int64, error) {
+
+	return repo.db.Exec(`UPDATE sessions SET revoked_at = now() WHERE credential = ? AND `+userSessions+` AND revoked_at IS NULL`, username)
+}
+
+// Deletes the sessions whose refresh token expired, revoked or not, returning how many there were
+func (repo *SessionRepository) DeleteExpired() (int64, error) {
+
+	return repo.db.Exec(`DELETE FROM sessions WHERE expires_at < now()`)
+}
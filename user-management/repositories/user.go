@@ -1,10 +1,19 @@
 package repositories
 
 import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
 	"user-management/database"
+	"user-management/middleware"
 	"user-management/models"
+
+	"github.com/jackc/pgconn"
 )
 
+const uniqueViolation = "23505" // Postgres error code of a unique constraint violation
+
 type UserRepository struct {
 	db *database.PostgresqlRepository
 }
@@ -17,7 +26,7 @@ func NewUserRepository(instance *database.PostgresqlRepository) *UserRepository
 
 func (repo *UserRepository) Register(user *models.User) error {
 
-	return repo.db.Create(user)
+	return duplicateError(repo.db.Create(user))
 }
 
 func (repo *UserRepository) GetAll(sort string) ([]models.User, error) {
@@ -32,7 +41,36 @@ func (repo *UserRepository) Get(username string) (models.User, error) {
 	return user, repo.db.Read(&user, "", "username = ?", username)
 }
 
+func (repo *UserRepository) GetByEmail(email string) (models.User, error) {
+
+	var user models.User
+	return user, repo.db.Read(&user, "", "email = ?", email)
+}
+
+func (repo *UserRepository) Update(user *models.User) error {
+
+	return duplicateError(repo.db.Update(user))
+}
+
 func (repo *UserRepository) Delete(user *models.User) error {
 
 	return repo.db.Delete(user)
 }
+
+// Function that tells which unique column a write collided on, the unique indexes being what keeps usernames and emails unique
+func duplicateError(err error) error {
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+
+	log.Println("Error - Duplicate user on constraint " + pgErr.ConstraintName)
+	switch {
+	case strings.Contains(pgErr.ConstraintName, "username"):
+		return middleware.NewError(http.StatusConflict, "Username already taken")
+	case strings.Contains(pgErr.ConstraintName, "email"):
+		return middleware.NewError(http.StatusConflict, "Email already registered")
+	}
+	return middleware.NewError(http.StatusConflict, "User already registered")
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"log"
+	"net/http"
+	"user-management/database"
+	"user-management/middleware"
+	"user-management/models"
+)
+
+type UserTokenRepository struct {
+	db *database.PostgresqlRepository
+}
+
+func NewUserTokenRepository(instance *database.PostgresqlRepository) *UserTokenRepository {
+	return &UserTokenRepository{
+		db: instance,
+	}
+}
+
+func (repo *UserTokenRepository) Create(token *models.UserToken) error {
+
+	return repo.db.Create(token)
+}
+
+// Uses the token in one statement, so it can't be used twice however many requests race for it
+func (repo *UserTokenRepository) Consume(digest, purpose string) (models.UserToken, error) {
+
+	var token models.UserToken
+	query := `UPDATE user_tokens SET used_at = now() WHERE digest = ? AND purpose = ? AND used_at IS NULL AND expires_at > now() RETURNING *`
+	if err := repo.db.Raw(&token, query, digest, purpose); err != nil {
+		return models.UserToken{}, err
+	}
+
+	if token.ID == 0 {
+		log.Println("Error - Token unknown, expired or already used")
+		return models.UserToken{}, middleware.NewError(http.StatusUnprocessableEntity, "Token is invalid, expired or already used")
+	}
+	return token, nil
+}
+
+// Uses up every open token of the user for the purpose (E.g Older reset tokens once the password got reset)
+func (repo *UserTokenRepository) Revoke(username, purpose string) error {
+
+	_, err := repo.db.Exec(`UPDATE user_tokens SET used_at = now() WHERE username = ? AND purpose = ? AND used_at IS NULL`, username, purpose)
+	return err
+}
+
+// Deletes the expired tokens, used or not, returning how many there were
+func (repo *UserTokenRepository) DeleteExpired() (int64, error) {
+
+	return repo.db.Exec(`DELETE FROM user_tokens WHERE expires_at < now()`)
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"user-management/controllers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddAccountRouter(router chi.Router, oauthKey string, controller *controllers.AccountController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Post("/api/user/me/verification", controller.ResendVerification)
+		},
+	)
+
+	// Public layer -> The mailed tokens are the proof
+	router.Group(
+		func(r chi.Router) {
+			r.Post("/api/email/verify", controller.VerifyEmail)
+			r.Post("/api/password/forgot", controller.ForgotPassword)
+			r.Post("/api/password/reset", controller.ResetPassword)
+		},
+	)
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"user-management/controllers"
+	"user-management/middleware"
+	"user-management/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddClientRouter(router chi.Router, oauthKey string, controller *controllers.ClientController) {
+	// Public layer
+	router.Post("/api/oauth/authorize", controller.Authorize)
+
+	// Admin layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+
+			r.Post("/api/clients", controller.Register)
+			r.Get("/api/clients", controller.GetAll)
+			r.Get("/api/clients/{id}", controller.Get)
+			r.Put("/api/clients/{id}", controller.Update)
+			r.Delete("/api/clients/{id}", controller.Delete)
+			r.Post("/api/clients/{id}/secret", controller.RotateSecret)
+		},
+	)
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"user-management/controllers"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func AddSessionRouter(router chi.Router, oauthKey string, controller *controllers.SessionController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Post("/api/logout", controller.Logout)
+			r.Post("/api/logout/all", controller.LogoutAll)
+		},
+	)
+}
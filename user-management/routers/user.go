@@ -1,8 +1,38 @@
 package route
 
 import (
+	"user-management/controllers"
+	"user-management/middleware"
+	"user-management/models"
+
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
 )
 
-func AddRouter(router chi.Router) {
+func AddUserRouter(router chi.Router, oauthKey string, controller *controllers.UserController) {
+	// Protected layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+
+			r.Get("/api/user/me", controller.GetProfile)
+			r.Put("/api/user/me/email", controller.ChangeEmail)
+			r.Put("/api/user/me/password", controller.ChangePassword)
+			r.Delete("/api/user/me", controller.DeleteAccount)
+		},
+	)
+
+	// Admin layer
+	router.Group(
+		func(r chi.Router) {
+			// Use the Bearer Authentication middleware
+			r.Use(oauth.Authorize(oauthKey, nil))
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+
+			r.Get("/api/user", controller.GetAll)
+			r.Get("/api/user/{name}", controller.Get)
+			r.Delete("/api/user/{name}", controller.Delete)
+		},
+	)
 }
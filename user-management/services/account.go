@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-management/mailer"
+	"user-management/middleware"
+	"user-management/models"
+	"user-management/repositories"
+)
+
+const (
+	verifyTokenTTL = 24 * time.Hour // How long the link of a verification email works
+	resetTokenTTL  = time.Hour      // How long the link of a password reset email works
+)
+
+type userTokenRepository interface {
+	Create(token *models.UserToken) error
+	Consume(digest, purpose string) (models.UserToken, error)
+	Revoke(username, purpose string) error
+}
+
+// AccountService proves users own their email through signed single-use tokens mailed to it
+type AccountService struct {
+	users    userRepository
+	tokens   userTokenRepository
+	mailer   mailer.Mailer
+	secret   []byte
+	appURL   string // Where the links of the emails lead (E.g the frontend)
+	policy   models.PasswordPolicy
+	sessions sessionRevoker
+}
+
+func InitAccountService(userRepo *repositories.UserRepository, userTokenRepo *repositories.UserTokenRepository, sessionService *SessionService, mail mailer.Mailer, secret, appURL string, policy models.PasswordPolicy) *AccountService {
+	return &AccountService{
+		users:    userRepo,
+		tokens:   userTokenRepo,
+		mailer:   mail,
+		secret:   []byte(secret),
+		appURL:   strings.TrimSuffix(appURL, "/"),
+		policy:   policy,
+		sessions: sessionService,
+	}
+}
+
+// Method that mails a verification link to the email of the user
+func (svc *AccountService) SendVerification(user models.User) error {
+
+	if user.EmailVerified {
+		return middleware.NewError(http.StatusConflict, "Email already verified")
+	}
+
+	token, err := svc.issue(user, models.TokenVerifyEmail, verifyTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return svc.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Verify your email by opening this link within 24 hours:\n" +
+			svc.appURL + "/verify-email?token=" + url.QueryEscape(token) + "\n",
+	})
+}
+
+func (svc *AccountService) ResendVerification(username string) error {
+
+	user, err := svc.users.Get(username)
+	if err != nil {
+		return err
+	}
+
+	return svc.SendVerification(user)
+}
+
+// Method that verifies the email the token was mailed to, as long as it still is the one of the user
+func (svc *AccountService) VerifyEmail(input *models.VerifyInput) (models.User, error) {
+
+	nonce, err := parseToken(svc.secret, models.TokenVerifyEmail, input.Token)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	token, err := svc.tokens.Consume(digest(nonce), models.TokenVerifyEmail)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := svc.users.Get(token.Username)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.Email != token.Email {
+		return models.User{}, middleware.NewError(http.StatusConflict, "Email changed since the token was sent")
+	}
+
+	user.EmailVerified = true
+	if err := svc.users.Update(&user); err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// Method that mails a password reset link to the user of the email -> Unknown emails answer the same, so they can't be probed
+func (svc *AccountService) ForgotPassword(input *models.ForgotInput) error {
+
+	user, err := svc.users.GetByEmail(strings.ToLower(input.Email))
+	if err != nil {
+		var mr *middleware.MalformedRequest
+		if errors.As(err, &mr) && mr.GetStatus() == http.StatusNotFound {
+			log.Println("Password reset requested for an unknown email")
+			return nil
+		}
+		return err
+	}
+
+	// Issued and mailed in the background, so known emails answer as fast as unknown ones
+	go svc.mailReset(user)
+	return nil
+}
+
+// Method that issues the password reset token of the user and mails its link, logging what fails as no one waits for it
+func (svc *AccountService) mailReset(user models.User) {
+
+	token, err := svc.issue(user, models.TokenResetPassword, resetTokenTTL)
+	if err != nil {
+		log.Println("Error - Failed to issue the password reset token of " + user.Username + ": " + err.Error())
+		return
+	}
+
+	if err := svc.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Reset your password by opening this link within an hour:\n" +
+			svc.appURL + "/reset-password?token=" + url.QueryEscape(token) + "\n\n" +
+			"If you didn't ask for it, ignore this email and your password stays the same.\n",
+	}); err != nil {
+		log.Println("Error - Failed to mail the password reset link of " + user.Username + ": " + err.Error())
+	}
+}
+
+// Method that sets the password of the user the token was mailed to, which also verifies their email
+func (svc *AccountService) ResetPassword(input *models.ResetInput) error {
+
+	nonce, err := parseToken(svc.secret, models.TokenResetPassword, input.Token)
+	if err != nil {
+		return err
+	}
+
+	// Checked first, so a weak password doesn't use the token up
+	if err := svc.policy.Check(input.NewPassword); err != nil {
+		return err
+	}
+
+	token, err := svc.tokens.Consume(digest(nonce), models.TokenResetPassword)
+	if err != nil {
+		return err
+	}
+
+	user, err := svc.users.Get(token.Username)
+	if err != nil {
+		return err
+	}
+
+	if err := user.HashPassword(input.NewPassword); err != nil {
+		return err
+	}
+	if user.Email == token.Email {
+		user.EmailVerified = true
+	}
+	if err := svc.users.Update(&user); err != nil {
+		return err
+	}
+
+	// Other reset links mailed before are no longer needed, nor sessions started with the old password
+	if err := svc.tokens.Revoke(user.Username, models.TokenResetPassword); err != nil {
+		return err
+	}
+	return svc.sessions.LogoutAll(user.Username)
+}
+
+// Method that stores a new token of the purpose for the email of the user and signs it
+func (svc *AccountService) issue(user models.User, purpose string, ttl time.Duration) (string, error) {
+
+	expiresAt := time.Now().Add(ttl)
+	signed, nonce, err := signToken(svc.secret, purpose, expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	token := &models.UserToken{
+		Digest:    digest(nonce),
+		Purpose:   purpose,
+		Username:  user.Username,
+		Email:     user.Email,
+		ExpiresAt: expiresAt,
+	}
+	if err := svc.tokens.Create(token); err != nil {
+		return "", err
+	}
+	return signed, nil
+}
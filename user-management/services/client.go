@@ -0,0 +1,344 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"user-management/middleware"
+	"user-management/models"
+	"user-management/repositories"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const authorizationCodeTTL = 10 * time.Minute // How long a client has to exchange an authorization code
+
+type clientRepository interface {
+	Create(client *models.Client) error
+	GetAll() ([]models.Client, error)
+	Get(clientID string) (models.Client, error)
+	Update(client *models.Client) error
+	Delete(client *models.Client) error
+}
+
+type authorizationCodeRepository interface {
+	Create(code *models.AuthorizationCode) error
+	Consume(digest, clientID string) (models.AuthorizationCode, error)
+}
+
+// ClientService keeps the registry of the clients allowed to get tokens, and the authorization codes users grant them
+type ClientService struct {
+	repo  clientRepository
+	codes authorizationCodeRepository
+	users userRepository
+}
+
+func InitClientService(clientRepo *repositories.ClientRepository, codeRepo *repositories.AuthorizationCodeRepository, userRepo *repositories.UserRepository) *ClientService {
+	return &ClientService{
+		repo:  clientRepo,
+		codes: codeRepo,
+		users: userRepo,
+	}
+}
+
+// Method that registers the client, generating the secret of confidential ones
+func (svc *ClientService) Register(input *models.RegisterClientInput) (models.ClientSecret, error) {
+
+	if err := validateClient(input.Public, &input.ClientInput); err != nil {
+		return models.ClientSecret{}, err
+	}
+
+	client := models.Client{ClientID: input.ClientID, Public: input.Public}
+	setClient(&client, &input.ClientInput)
+
+	var secret string
+	if !client.Public {
+		var err error
+		if secret, err = svc.newSecret(&client); err != nil {
+			return models.ClientSecret{}, err
+		}
+	}
+
+	if err := svc.repo.Create(&client); err != nil {
+		return models.ClientSecret{}, err
+	}
+	return models.ClientSecret{Client: client, Secret: secret}, nil
+}
+
+// Method that registers the public client when missing (E.g the frontend on startup)
+func (svc *ClientService) Bootstrap(clientID, name string, redirectURIs []string) error {
+
+	_, err := svc.repo.Get(clientID)
+	if !isNotFound(err) {
+		return err
+	}
+
+	_, err = svc.Register(&models.RegisterClientInput{
+		ClientID: clientID,
+		Public:   true,
+		ClientInput: models.ClientInput{
+			Name:         name,
+			GrantTypes:   []string{models.GrantAuthorizationCode},
+			RedirectURIs: redirectURIs,
+		},
+	})
+	return err
+}
+
+func (svc *ClientService) GetAll() ([]models.Client, error) {
+
+	return svc.repo.GetAll()
+}
+
+func (svc *ClientService) Get(clientID string) (models.Client, error) {
+
+	return svc.repo.Get(clientID)
+}
+
+func (svc *ClientService) Update(clientID string, input *models.ClientInput) (models.Client, error) {
+
+	client, err := svc.repo.Get(clientID)
+	if err != nil {
+		return models.Client{}, err
+	}
+
+	if err := validateClient(client.Public, input); err != nil {
+		return models.Client{}, err
+	}
+
+	setClient(&client, input)
+	if err := svc.repo.Update(&client); err != nil {
+		return models.Client{}, err
+	}
+	return client, nil
+}
+
+// Method that replaces the secret of the confidential client, the old one no longer working
+func (svc *ClientService) RotateSecret(clientID string) (models.ClientSecret, error) {
+
+	client, err := svc.repo.Get(clientID)
+	if err != nil {
+		return models.ClientSecret{}, err
+	}
+	if client.Public {
+		return models.ClientSecret{}, middleware.NewError(http.StatusUnprocessableEntity, "Public clients have no secret")
+	}
+
+	secret, err := svc.newSecret(&client)
+	if err != nil {
+		return models.ClientSecret{}, err
+	}
+	if err := svc.repo.Update(&client); err != nil {
+		return models.ClientSecret{}, err
+	}
+	return models.ClientSecret{Client: client, Secret: secret}, nil
+}
+
+func (svc *ClientService) Delete(clientID string) error {
+
+	client, err := svc.repo.Get(clientID)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.Delete(&client)
+}
+
+// Method that checks the client can use the grant, with its secret unless it is public
+func (svc *ClientService) Authenticate(clientID, secret, grant string) (models.Client, error) {
+
+	unauthorized := middleware.NewError(http.StatusUnauthorized, "Invalid client credentials")
+
+	client, err := svc.repo.Get(clientID)
+	if err != nil {
+		if isNotFound(err) {
+			return models.Client{}, unauthorized
+		}
+		return models.Client{}, err
+	}
+
+	if !client.AllowsGrant(grant) {
+		log.Println("Error - Client " + clientID + " not allowed the " + grant + " grant")
+		return models.Client{}, unauthorized
+	}
+
+	if client.Public {
+		if secret != "" {
+			return models.Client{}, unauthorized
+		}
+		return client, nil
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(client.SecretHash), []byte(secret)); err != nil {
+		log.Println("Error - Client secret doesn't match for " + clientID)
+		return models.Client{}, unauthorized
+	}
+	return client, nil
+}
+
+// Method that logs the user in to authorize the client, returning the code it exchanges for their tokens
+func (svc *ClientService) Authorize(input *models.AuthorizeInput) (models.Authorization, error) {
+
+	client, err := svc.repo.Get(input.ClientID)
+	if err != nil {
+		if isNotFound(err) {
+			return models.Authorization{}, middleware.NewError(http.StatusBadRequest, "Unknown client")
+		}
+		return models.Authorization{}, err
+	}
+
+	switch {
+	case !client.AllowsGrant(models.GrantAuthorizationCode):
+		return models.Authorization{}, middleware.NewError(http.StatusBadRequest, "Client is not allowed the authorization code grant")
+	case !client.AllowsRedirectURI(input.RedirectURI):
+		return models.Authorization{}, middleware.NewError(http.StatusBadRequest, "Redirect URI is not registered for the client")
+	case !client.AllowsScope(input.Scope):
+		return models.Authorization{}, middleware.NewError(http.StatusBadRequest, "Scope is not allowed for the client")
+	case input.CodeChallengeMethod != models.CodeChallengeS256:
+		return models.Authorization{}, middleware.NewError(http.StatusBadRequest, "Code challenge method should be S256")
+	}
+
+	user, err := svc.users.Get(input.Username)
+	if err == nil {
+		err = user.CheckPassword(input.Password)
+	}
+	if err != nil {
+		if isNotFound(err) {
+			return models.Authorization{}, middleware.NewError(http.StatusUnauthorized, "Error - Incorrect Credentials")
+		}
+		return models.Authorization{}, err
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		return models.Authorization{}, err
+	}
+	if err := svc.codes.Create(&models.AuthorizationCode{
+		Digest:        digest(code),
+		ClientID:      client.ClientID,
+		Username:      user.Username,
+		RedirectURI:   input.RedirectURI,
+		Scope:         input.Scope,
+		CodeChallenge: input.CodeChallenge,
+		ExpiresAt:     time.Now().Add(authorizationCodeTTL),
+	}); err != nil {
+		return models.Authorization{}, err
+	}
+
+	redirect, _ := url.Parse(input.RedirectURI) // Registered URIs were validated
+	query := redirect.Query()
+	query.Set("code", code)
+	if input.State != "" {
+		query.Set("state", input.State)
+	}
+	redirect.RawQuery = query.Encode()
+
+	return models.Authorization{Code: code, State: input.State, RedirectURI: redirect.String()}, nil
+}
+
+// Method that exchanges the authorization code of the client, checking its PKCE verifier, returning the user who authorized it
+func (svc *ClientService) Exchange(clientID, secret, code, redirectURI, verifier, scope string) (string, error) {
+
+	if _, err := svc.Authenticate(clientID, secret, models.GrantAuthorizationCode); err != nil {
+		return "", err
+	}
+
+	authorization, err := svc.codes.Consume(digest(code), clientID)
+	if err != nil {
+		return "", err
+	}
+
+	invalid := middleware.NewError(http.StatusUnauthorized, "Authorization code is invalid, expired or already used")
+	if authorization.RedirectURI != redirectURI {
+		log.Println("Error - Authorization code exchanged with another redirect URI by " + clientID)
+		return "", invalid
+	}
+	if scope != "" && scope != authorization.Scope {
+		log.Println("Error - Authorization code exchanged for another scope by " + clientID)
+		return "", invalid
+	}
+
+	// The challenge is the S256 digest of the verifier only the client that asked for the code knows
+	challenge := sha256.Sum256([]byte(verifier))
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(challenge[:])), []byte(authorization.CodeChallenge)) != 1 {
+		log.Println("Error - PKCE verifier mismatch for " + clientID)
+		return "", invalid
+	}
+
+	return authorization.Username, nil
+}
+
+// Method that sets a new secret for the confidential client, returning it as only its hash is kept
+func (svc *ClientService) newSecret(client *models.Client) (string, error) {
+
+	secret, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("Error hashing client secret")
+		return "", err
+	}
+	client.SecretHash = string(hash)
+	return secret, nil
+}
+
+// Function that checks the grant types, scopes and redirect URIs make sense together
+func validateClient(public bool, input *models.ClientInput) error {
+
+	for _, grant := range input.GrantTypes {
+		switch grant {
+		case models.GrantAuthorizationCode:
+		case models.GrantClientCredentials:
+			if public {
+				return middleware.NewError(http.StatusUnprocessableEntity, "Public clients can only use the authorization_code grant")
+			}
+		default:
+			return middleware.NewError(http.StatusUnprocessableEntity, "Grant types should be authorization_code or client_credentials")
+		}
+	}
+
+	for _, scope := range input.Scopes {
+		if scope == "" || strings.IndexFunc(scope, isNotScopeChar) >= 0 {
+			return middleware.NewError(http.StatusUnprocessableEntity, "Scopes should be printable ASCII without spaces, quotes or backslashes")
+		}
+	}
+
+	if len(input.RedirectURIs) == 0 && input.RequestsGrant(models.GrantAuthorizationCode) {
+		return middleware.NewError(http.StatusUnprocessableEntity, "The authorization_code grant needs at least one redirect URI")
+	}
+	for _, uri := range input.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.Fragment != "" {
+			return middleware.NewError(http.StatusUnprocessableEntity, "Redirect URIs should be absolute http(s) URLs without a fragment: "+uri)
+		}
+	}
+	return nil
+}
+
+// Function that copies the input into the client, never leaving lists nil
+func setClient(client *models.Client, input *models.ClientInput) {
+	client.Name = input.Name
+	client.GrantTypes = append([]string{}, input.GrantTypes...)
+	client.Scopes = append([]string{}, input.Scopes...)
+	client.RedirectURIs = append([]string{}, input.RedirectURIs...)
+}
+
+// Function that rejects what the oauth spec keeps out of scope tokens
+func isNotScopeChar(r rune) bool {
+	return r > unicode.MaxASCII || !unicode.IsGraphic(r) || r == ' ' || r == '"' || r == '\\'
+}
+
+func isNotFound(err error) bool {
+	var mr *middleware.MalformedRequest
+	return errors.As(err, &mr) && mr.GetStatus() == http.StatusNotFound
+}
@@ -1,17 +1,32 @@
 package services
 
-import "user-management/repositories"
+import (
+	"time"
+
+	"user-management/mailer"
+	"user-management/models"
+	"user-management/repositories"
+)
 
 // Repositories contains all the repo structs
 type Services struct {
-	UserService *UserService
+	UserService    *UserService
+	AccountService *AccountService
+	SessionService *SessionService
+	ClientService  *ClientService
 }
 
 // InitRepositories should be called in main.go
-func InitServices(repositories *repositories.Repositories) *Services {
-	userService := InitUserService(repositories.UserRepository)
+func InitServices(repositories *repositories.Repositories, passwordPolicy models.PasswordPolicy, mail mailer.Mailer, tokenSecret, appURL string, refreshTTL time.Duration) *Services {
+	sessionService := InitSessionService(repositories.SessionRepository, repositories.UserTokenRepository, repositories.CodeRepository, refreshTTL)
+	accountService := InitAccountService(repositories.UserRepository, repositories.UserTokenRepository, sessionService, mail, tokenSecret, appURL, passwordPolicy)
+	userService := InitUserService(repositories.UserRepository, passwordPolicy, accountService, sessionService)
+	clientService := InitClientService(repositories.ClientRepository, repositories.CodeRepository, repositories.UserRepository)
 
 	return &Services{
-		UserService: userService,
+		UserService:    userService,
+		AccountService: accountService,
+		SessionService: sessionService,
+		ClientService:  clientService,
 	}
 }
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"time"
+	"user-management/models"
+	"user-management/repositories"
+)
+
+type sessionRepository interface {
+	Create(session *models.Session) error
+	Consume(credential, tokenID, refreshTokenID string) error
+	Revoke(username, tokenID string) error
+	RevokeAll(username string) (int64, error)
+	DeleteExpired() (int64, error)
+}
+
+type expiredTokens interface {
+	DeleteExpired() (int64, error)
+}
+
+// SessionService keeps the tokens issued through oauth, so their refresh tokens can be revoked
+type SessionService struct {
+	repo       sessionRepository
+	userTokens expiredTokens
+	codes      expiredTokens
+	ttl        time.Duration // How long a refresh token can be used
+}
+
+func InitSessionService(sessionRepo *repositories.SessionRepository, userTokenRepo *repositories.UserTokenRepository, codeRepo *repositories.AuthorizationCodeRepository, ttl time.Duration) *SessionService {
+	return &SessionService{
+		repo:       sessionRepo,
+		userTokens: userTokenRepo,
+		codes:      codeRepo,
+		ttl:        ttl,
+	}
+}
+
+// Method that keeps the session of the tokens just issued
+func (svc *SessionService) Store(tokenType, credential, tokenID, refreshTokenID string) error {
+
+	return svc.repo.Create(&models.Session{
+		TokenID:        tokenID,
+		RefreshTokenID: refreshTokenID,
+		Credential:     credential,
+		TokenType:      tokenType,
+		ExpiresAt:      time.Now().Add(svc.ttl),
+	})
+}
+
+// Method that uses the session of the refresh token up, failing if it was revoked or expired
+func (svc *SessionService) Validate(credential, tokenID, refreshTokenID string) error {
+
+	return svc.repo.Consume(credential, tokenID, refreshTokenID)
+}
+
+// Method that revokes the session of the access token, which still works until it expires
+func (svc *SessionService) Logout(username, tokenID string) error {
+
+	return svc.repo.Revoke(username, tokenID)
+}
+
+// Method that revokes every session of the user
+func (svc *SessionService) LogoutAll(username string) error {
+
+	revoked, err := svc.repo.RevokeAll(username)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Revoked sessions of " + username + ": " + strconv.FormatInt(revoked, 10))
+	return nil
+}
+
+// Method that deletes expired sessions, mailed tokens and authorization codes every interval, until the process stops -> Should run on its own goroutine
+func (svc *SessionService) Sweep(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C { // A failed cleanup doesn't hold back the others
+		deleteExpired("sessions", svc.repo.DeleteExpired)
+		deleteExpired("mailed tokens", svc.userTokens.DeleteExpired)
+		deleteExpired("authorization codes", svc.codes.DeleteExpired)
+	}
+}
+
+// Function that runs one cleanup of the sweep, logging how many entries it deleted or why it failed
+func deleteExpired(name string, cleanup func() (int64, error)) {
+	deleted, err := cleanup()
+	if err != nil {
+		log.Println("Error - Failed to delete expired " + name + ": " + err.Error())
+		return
+	}
+	if deleted > 0 {
+		log.Println("Deleted expired " + name + ": " + strconv.FormatInt(deleted, 10))
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-management/middleware"
+)
+
+// Function that signs a new token of the purpose, returning it along with the nonce it is kept by
+// E.g verify.<nonce>.<expires unix>.<signature>
+func signToken(secret []byte, purpose string, expiresAt time.Time) (string, string, error) {
+
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	payload := purpose + "." + nonce + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + signature(secret, payload), nonce, nil
+}
+
+// Function that checks the token was signed for the purpose and hasn't expired, returning its nonce
+func parseToken(secret []byte, purpose, token string) (string, error) {
+
+	invalid := middleware.NewError(http.StatusUnprocessableEntity, "Token is invalid, expired or already used")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] != purpose {
+		log.Println("Error - Token malformed or of another purpose")
+		return "", invalid
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(signature(secret, payload))) {
+		log.Println("Error - Token signature mismatch")
+		return "", invalid
+	}
+
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		log.Println("Error - Token expired")
+		return "", invalid
+	}
+	return parts[1], nil
+}
+
+// Function that generates 32 random bytes, encoded so they fit in URLs
+func randomToken() (string, error) {
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Println("Error generating random token: " + err.Error())
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Function that digests the nonce, which is all that is stored of a token
+func digest(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+func signature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"log"
+	"strings"
+
 	"user-management/models"
 	"user-management/repositories"
 )
@@ -9,26 +12,54 @@ type userRepository interface {
 	Register(user *models.User) error
 	GetAll(sort string) ([]models.User, error)
 	Get(username string) (models.User, error)
+	GetByEmail(email string) (models.User, error)
+	Update(user *models.User) error
 	Delete(user *models.User) error
 }
 
+type emailVerifier interface {
+	SendVerification(user models.User) error
+}
+
+type sessionRevoker interface {
+	LogoutAll(username string) error
+}
+
 type UserService struct {
-	repo userRepository
+	repo     userRepository
+	policy   models.PasswordPolicy
+	verifier emailVerifier
+	sessions sessionRevoker
 }
 
-func InitUserService(userRepo *repositories.UserRepository) *UserService {
+func InitUserService(userRepo *repositories.UserRepository, policy models.PasswordPolicy, accountService *AccountService, sessionService *SessionService) *UserService {
 	return &UserService{
-		repo: userRepo,
+		repo:     userRepo,
+		policy:   policy,
+		verifier: accountService,
+		sessions: sessionService,
 	}
 }
 
 func (svc *UserService) Register(user *models.User) error {
 
+	// Roles are granted, never registered with
+	user.Role = models.RoleUser
+
+	if err := svc.policy.Check(user.GetPassword()); err != nil {
+		return err
+	}
+
 	if err := user.HashPassword(user.GetPassword()); err != nil {
 		return err
 	}
 
-	return svc.repo.Register(user)
+	if err := svc.repo.Register(user); err != nil {
+		return err
+	}
+
+	svc.sendVerification(*user)
+	return nil
 }
 
 func (svc *UserService) GetAll(sort string) ([]models.User, error) {
@@ -36,6 +67,11 @@ func (svc *UserService) GetAll(sort string) ([]models.User, error) {
 	return svc.repo.GetAll(sort)
 }
 
+func (svc *UserService) Get(username string) (models.User, error) {
+
+	return svc.repo.Get(username)
+}
+
 func (svc *UserService) Login(username, password string) error {
 
 	user, err := svc.repo.Get(username)
@@ -46,6 +82,78 @@ func (svc *UserService) Login(username, password string) error {
 	return user.CheckPassword(password)
 }
 
+// Method that changes the email of the user, once their current password is checked
+func (svc *UserService) ChangeEmail(username string, input *models.EmailInput) (models.User, error) {
+
+	user, err := svc.repo.Get(username)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := user.CheckPassword(input.CurrentPassword); err != nil {
+		return models.User{}, err
+	}
+
+	email := strings.ToLower(input.Email)
+	if email == user.Email {
+		return user, nil
+	}
+
+	// The new email is unverified until a token mailed to it comes back
+	user.Email, user.EmailVerified = email, false
+	if err := svc.repo.Update(&user); err != nil {
+		return models.User{}, err
+	}
+
+	svc.sendVerification(user)
+	return user, nil
+}
+
+// Method that changes the password of the user, once their current one is checked
+func (svc *UserService) ChangePassword(username string, input *models.PasswordInput) error {
+
+	user, err := svc.repo.Get(username)
+	if err != nil {
+		return err
+	}
+
+	if err := user.CheckPassword(input.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := svc.policy.Check(input.NewPassword); err != nil {
+		return err
+	}
+
+	if err := user.HashPassword(input.NewPassword); err != nil {
+		return err
+	}
+	if err := svc.repo.Update(&user); err != nil {
+		return err
+	}
+
+	// Sessions started with the old password can't be refreshed
+	return svc.sessions.LogoutAll(user.Username)
+}
+
+// Method that deletes the account of the user, once their current password is checked
+func (svc *UserService) DeleteAccount(username string, input *models.DeleteInput) error {
+
+	user, err := svc.repo.Get(username)
+	if err != nil {
+		return err
+	}
+
+	if err := user.CheckPassword(input.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := svc.repo.Delete(&user); err != nil {
+		return err
+	}
+	return svc.sessions.LogoutAll(user.Username)
+}
+
 func (svc *UserService) Delete(name string) error {
 
 	user, err := svc.repo.Get(name)
@@ -54,5 +162,16 @@ func (svc *UserService) Delete(name string) error {
 	}
 
 	// Delete by id
-	return svc.repo.Delete(&user)
+	if err := svc.repo.Delete(&user); err != nil {
+		return err
+	}
+	return svc.sessions.LogoutAll(user.Username)
+}
+
+// Method that mails the verification link, whose failure only gets logged as users can have it resent
+func (svc *UserService) sendVerification(user models.User) {
+
+	if err := svc.verifier.SendVerification(user); err != nil {
+		log.Println("Error sending verification mail to " + user.Username + ": " + err.Error())
+	}
 }
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/oauth"
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests POST the token mailed on registration, verifying the email once*/
+func TestVerifyEmail(t *testing.T) {
+	username := "verify-" + run
+	register(t, username)
+	token := mailedToken(t, username+"@example.com", "/verify-email")
+
+	// Other services limit what unverified users do by the claim of their token
+	if verified := claim(t, login(t, username, password).Access, "email_verified"); verified != "false" {
+		t.Errorf("token of an unverified user claims email_verified %s", verified)
+	}
+
+	verifyEmail(t, token, http.StatusOK)
+	verifyEmail(t, token, http.StatusUnprocessableEntity)
+
+	access := login(t, username, password).Access
+	if verified := claim(t, access, "email_verified"); verified != "true" {
+		t.Errorf("token of a verified user claims email_verified %s", verified)
+	}
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/user/me").
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var profile struct {
+				EmailVerified bool `json:"email_verified"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+				return err
+			}
+			if !profile.EmailVerified {
+				return errors.New("email should be verified")
+			}
+			return nil
+		}).
+		End()
+
+	apitest.New(). // Nothing left to verify
+			HandlerFunc(router.ServeHTTP).
+			Post("/api/user/me/verification").
+			Header("Authorization", "Bearer "+access).
+			Expect(t).
+			Status(http.StatusConflict).
+			End()
+}
+
+/* Tests POST verification tokens that weren't signed by the service*/
+func TestVerifyEmailForged(t *testing.T) {
+	username := "forged-" + run
+	register(t, username)
+	token := mailedToken(t, username+"@example.com", "/verify-email")
+
+	verifyEmail(t, "not-a-token", http.StatusUnprocessableEntity)
+	verifyEmail(t, tamper(token), http.StatusUnprocessableEntity)
+}
+
+/* Tests POST a new password with the token mailed for it, which works once*/
+func TestResetPassword(t *testing.T) {
+	username := "reset-" + run
+	register(t, username)
+	forgotPassword(t, username+"@example.com")
+	token := mailedToken(t, username+"@example.com", "/reset-password")
+
+	resetPassword(t, token, "weak", http.StatusUnprocessableEntity) // Doesn't use the token up
+	resetPassword(t, token, "another123", http.StatusNoContent)
+	resetPassword(t, token, "yetanother123", http.StatusUnprocessableEntity)
+
+	loginStatus(t, username, password, http.StatusUnauthorized)
+	loginStatus(t, username, "another123", http.StatusOK)
+}
+
+/* Tests POST a new password with the token mailed for verifying the email*/
+func TestResetPasswordWrongPurpose(t *testing.T) {
+	username := "purpose-" + run
+	register(t, username)
+	token := mailedToken(t, username+"@example.com", "/verify-email")
+
+	resetPassword(t, token, "another123", http.StatusUnprocessableEntity)
+}
+
+/* Tests POST a password reset for an email nobody registered, answered like a known one*/
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	forgotPassword(t, "nobody-"+run+"@example.com")
+}
+
+func verifyEmail(t *testing.T, token string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/email/verify").
+		JSON(`{"token": "` + token + `"}`).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+func forgotPassword(t *testing.T, email string) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/password/forgot").
+		JSON(`{"email": "` + email + `"}`).
+		Expect(t).
+		Status(http.StatusNoContent).
+		End()
+}
+
+func resetPassword(t *testing.T, token, next string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/password/reset").
+		JSON(`{"token": "` + token + `", "new_password": "` + next + `"}`).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Waits for the mail to the email with a link to the path, returning the token of the link -> Some mails are sent in the background
+func mailedToken(t *testing.T, email, path string) string {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		for _, message := range mail.Messages() {
+			if message.To != email {
+				continue
+			}
+			for _, line := range strings.Split(message.Body, "\n") {
+				link, err := url.Parse(strings.TrimSpace(line))
+				if err == nil && link.Path == path && link.Query().Get("token") != "" {
+					return link.Query().Get("token")
+				}
+			}
+		}
+	}
+	t.Fatalf("no mail to %s with a link to %s", email, path)
+	return ""
+}
+
+// Reads the claim of the access token
+func claim(t *testing.T, access, name string) string {
+	token, err := oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(os.Getenv("OAUTH_KEY")))).DecryptToken(access)
+	if err != nil {
+		t.Fatalf("access token can't be read: %s", err)
+	}
+	return token.Claims[name]
+}
+
+// Pushes the expiry of the token years ahead, keeping its signature
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return token + "x"
+	}
+	parts[2] = strconv.FormatInt(time.Now().AddDate(10, 0, 0).Unix(), 10)
+	return strings.Join(parts, ".")
+}
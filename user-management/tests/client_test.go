@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/steinfletcher/apitest"
+)
+
+const (
+	redirectURI = "http://localhost:3000/callback"
+	verifier    = "dBjftJeZ4CVP-mJ92K9ZgW9-uFf6yLp1sB4Qz5rJ8Kk"
+)
+
+// Public client of the frontend, and the user logging in through it
+var (
+	publicClient = "spa-" + run
+	oauthUser    = "oauth-" + run
+)
+
+/* Tests POST a public Client and a User, for the authorization code grant, with success*/
+func TestRegisterPublicClient(t *testing.T) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/clients").
+		JSON(`{"client_id": "`+publicClient+`", "public": true, "name": "Frontend", "grant_types": ["authorization_code"], "redirect_uris": ["`+redirectURI+`"]}`).
+		Header("Authorization", "Bearer "+adminHeader).
+		Expect(t).
+		Status(http.StatusCreated).
+		End()
+
+	register(t, oauthUser)
+}
+
+/* Tests GET tokens for an authorization code, which works once*/
+func TestAuthorizationCodeSingleUse(t *testing.T) {
+	code := authorize(t, publicClient, redirectURI)
+
+	exchange(t, code, redirectURI, verifier, "", http.StatusOK)
+	exchange(t, code, redirectURI, verifier, "", http.StatusUnauthorized)
+}
+
+/* Tests GET tokens for an authorization code with a verifier that doesn't match the S256 challenge*/
+func TestAuthorizationCodeVerifierMismatch(t *testing.T) {
+	code := authorize(t, publicClient, redirectURI)
+
+	exchange(t, code, redirectURI, "another-verifier-of-the-same-length-0123456", "", http.StatusUnauthorized)
+}
+
+/* Tests the authorization code grant with a redirect URI other than the registered one*/
+func TestAuthorizationCodeRedirectMismatch(t *testing.T) {
+	// Asking for a code
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/oauth/authorize").
+		JSON(authorizeBody(publicClient, "http://localhost:3000/elsewhere")).
+		Expect(t).
+		Status(http.StatusBadRequest).
+		End()
+
+	// Exchanging the code
+	code := authorize(t, publicClient, redirectURI)
+	exchange(t, code, "http://localhost:3000/elsewhere", verifier, "", http.StatusUnauthorized)
+}
+
+/* Tests GET tokens for an authorization code of a public client sending a secret*/
+func TestAuthorizationCodePublicClientSecret(t *testing.T) {
+	code := authorize(t, publicClient, redirectURI)
+
+	exchange(t, code, redirectURI, verifier, "secret", http.StatusUnauthorized)
+}
+
+/* Tests the secret of a confidential Client is checked against its hash, and no longer works once rotated*/
+func TestConfidentialClientSecret(t *testing.T) {
+	clientID := "reports-" + run
+
+	var secret string
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/clients").
+		JSON(`{"client_id": "`+clientID+`", "name": "Reports", "grant_types": ["client_credentials"], "scopes": ["offers:read"]}`).
+		Header("Authorization", "Bearer "+adminHeader).
+		Expect(t).
+		Status(http.StatusCreated).
+		Assert(decodeSecret(&secret)).
+		End()
+
+	clientCredentials(t, clientID, secret, http.StatusOK)
+	clientCredentials(t, clientID, secret+"x", http.StatusUnauthorized)
+
+	var rotated string
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/clients/"+clientID+"/secret").
+		Header("Authorization", "Bearer "+adminHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(decodeSecret(&rotated)).
+		End()
+
+	clientCredentials(t, clientID, secret, http.StatusUnauthorized)
+	clientCredentials(t, clientID, rotated, http.StatusOK)
+}
+
+// Registers the user, with the test password
+func register(t *testing.T, username string) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/register").
+		JSON(`{"username": "` + username + `", "email": "` + username + `@example.com", "password": "` + password + `"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
+
+// Logs the oauth user in through the client, returning the code it gets for the S256 challenge of the verifier
+func authorize(t *testing.T, clientID, redirect string) string {
+	var authorization struct {
+		Code string `json:"code"`
+	}
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/oauth/authorize").
+		JSON(authorizeBody(clientID, redirect)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			return json.NewDecoder(res.Body).Decode(&authorization)
+		}).
+		End()
+	return authorization.Code
+}
+
+func authorizeBody(clientID, redirect string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+	return `{"client_id": "` + clientID + `", "redirect_uri": "` + redirect + `", "state": "xyz", "code_challenge": "` + base64.RawURLEncoding.EncodeToString(challenge[:]) +
+		`", "code_challenge_method": "S256", "username": "` + oauthUser + `", "password": "` + password + `"}`
+}
+
+// Exchanges the code of the public client for tokens, expecting the status
+func exchange(t *testing.T, code, redirect, codeVerifier, secret string, status int) {
+	request := apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/oauth/token").
+		FormData("grant_type", "authorization_code").
+		FormData("client_id", publicClient).
+		FormData("code", code).
+		FormData("redirect_uri", redirect).
+		FormData("code_verifier", codeVerifier)
+	if secret != "" {
+		request = request.FormData("client_secret", secret)
+	}
+	request.Expect(t).
+		Status(status).
+		End()
+}
+
+// Asks for a client token with the secret, expecting the status
+func clientCredentials(t *testing.T, clientID, secret string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/auth").
+		FormData("grant_type", "client_credentials").
+		FormData("scope", "offers:read").
+		BasicAuth(clientID, secret).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Reads the secret of a registered or rotated client
+func decodeSecret(secret *string) func(res *http.Response, req *http.Request) error {
+	return func(res *http.Response, req *http.Request) error {
+		var body struct {
+			Secret string `json:"client_secret"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+			return err
+		}
+		if body.Secret == "" {
+			return errors.New("client secret missing")
+		}
+		*secret = body.Secret
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests POST a User, answered without their password and as a plain user*/
+func TestRegisterSanitized(t *testing.T) {
+	username := "john.doe_" + run
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/register").
+		JSON(`{"username": "` + username + `", "email": "` + strings.ToUpper(username) + `@Example.com", "password": "` + password + `"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var user map[string]interface{}
+			if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+				return err
+			}
+			if _, ok := user["password"]; ok {
+				return errors.New("password sent back on registration")
+			}
+			if user["role"] != "user" || user["email"] != username+"@example.com" {
+				return errors.New("user should be registered as a user, with the email in lowercase")
+			}
+			return nil
+		}).
+		End()
+}
+
+/* Tests POST a User with a username or an email already registered*/
+func TestRegisterDuplicate(t *testing.T) {
+	username := "duplicate-" + run
+	register(t, username)
+
+	registerStatus(t, username, "other-"+username+"@example.com", password, http.StatusConflict)
+	registerStatus(t, "other-"+username, strings.ToUpper(username)+"@example.com", password, http.StatusConflict)
+}
+
+/* Tests POST a User with a malformed username or email*/
+func TestRegisterMalformed(t *testing.T) {
+	username := "malformed-" + run
+
+	registerStatus(t, "john doe", username+"@example.com", password, http.StatusUnprocessableEntity)
+	registerStatus(t, "jo", username+"@example.com", password, http.StatusUnprocessableEntity)
+	registerStatus(t, "john/doe", username+"@example.com", password, http.StatusUnprocessableEntity)
+	registerStatus(t, username, "not-an-email", password, http.StatusUnprocessableEntity)
+}
+
+/* Tests POST a User with passwords that break the policy*/
+func TestRegisterWeakPassword(t *testing.T) {
+	username := "weak-" + run
+
+	registerStatus(t, username, username+"@example.com", "short1", http.StatusUnprocessableEntity)
+	registerStatus(t, username, username+"@example.com", "nodigitshere", http.StatusUnprocessableEntity)
+	registerStatus(t, username, username+"@example.com", "NOLOWER123", http.StatusUnprocessableEntity)
+	registerStatus(t, username, username+"@example.com", strings.Repeat("a1", 40), http.StatusUnprocessableEntity)
+}
+
+// Registers the user with the email and password, expecting the status
+func registerStatus(t *testing.T, username, email, secret string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/register").
+		JSON(`{"username": "` + username + `", "email": "` + email + `", "password": "` + secret + `"}`).
+		Expect(t).
+		Status(status).
+		End()
+}
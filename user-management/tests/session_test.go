@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/steinfletcher/apitest"
+)
+
+/* Tests refreshing a session, which uses its refresh token up*/
+func TestRefreshSingleUse(t *testing.T) {
+	username := "refresh-" + run
+	register(t, username)
+	session := login(t, username, password)
+
+	next := refresh(t, session.Refresh)
+	refreshStatus(t, session.Refresh, http.StatusUnauthorized)
+	refresh(t, next.Refresh)
+}
+
+/* Tests POST a logout, revoking only the session of the token*/
+func TestLogout(t *testing.T) {
+	username := "logout-" + run
+	register(t, username)
+	first, second := login(t, username, password), login(t, username, password)
+
+	logout(t, "/api/logout", first.Access)
+
+	refreshStatus(t, first.Refresh, http.StatusUnauthorized)
+	refreshStatus(t, second.Refresh, http.StatusOK)
+}
+
+/* Tests POST a logout everywhere, revoking every session of the user*/
+func TestLogoutAll(t *testing.T) {
+	username := "logoutall-" + run
+	register(t, username)
+	first, second := login(t, username, password), login(t, username, password)
+
+	logout(t, "/api/logout/all", first.Access)
+
+	refreshStatus(t, first.Refresh, http.StatusUnauthorized)
+	refreshStatus(t, second.Refresh, http.StatusUnauthorized)
+}
+
+/* Tests changing the password revokes the sessions started with the old one*/
+func TestChangePasswordRevokesSessions(t *testing.T) {
+	username := "revoke-" + run
+	register(t, username)
+	session := login(t, username, password)
+
+	changePassword(t, session.Access, password, "another123", http.StatusNoContent)
+
+	refreshStatus(t, session.Refresh, http.StatusUnauthorized)
+}
+
+// Refreshes the session, returning the tokens of the next one
+func refresh(t *testing.T, token string) tokens {
+	var answered tokens
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/auth").
+		FormData("grant_type", "refresh_token").
+		FormData("refresh_token", token).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			return json.NewDecoder(res.Body).Decode(&answered)
+		}).
+		End()
+	return answered
+}
+
+// Refreshes the session, expecting the status
+func refreshStatus(t *testing.T, token string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/auth").
+		FormData("grant_type", "refresh_token").
+		FormData("refresh_token", token).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+// Logs out of the path with the access token
+func logout(t *testing.T, path, access string) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post(path).
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(http.StatusNoContent).
+		End()
+}
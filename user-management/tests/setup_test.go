@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"user-management/controllers"
+	"user-management/database"
+	"user-management/mailer"
+	"user-management/models"
+	"user-management/repositories"
+	route "user-management/routers"
+	"user-management/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+var router *chi.Mux
+var adminHeader string        // Token of an admin, who registers the clients
+var mail *mailer.MemoryMailer // Keeps the mails sent, so the tests read their tokens
+
+// Ends the usernames, emails and client ids of this run, so the tests can run again on the same database
+var run = strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+
+const password = "secret123"
+
+// Prepares test environment
+func init() {
+	log.Println("Setup Starting")
+
+	// Set Database
+	db, err := database.Connect()
+	if err != nil {
+		log.Println("Error occurred while connecting to database")
+		return
+	}
+
+	// Fetch Oauth Key
+	oauthKey, oauthKeyPresent := os.LookupEnv("OAUTH_KEY")
+	tokenSecret, tokenSecretPresent := os.LookupEnv("TOKEN_SECRET")
+	if !oauthKeyPresent || !tokenSecretPresent {
+		log.Println("Error occurred while fetching essential env variables")
+		return
+	}
+
+	// Defines Token header to be used in requests
+	adminHeader, err = oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(oauthKey))).CryptToken(&oauth.Token{
+		CreationDate: time.Now().UTC(),
+		ExpiresIn:    time.Hour,
+		Claims:       map[string]string{"username": "admin", "role": models.RoleAdmin},
+		TokenType:    oauth.BearerToken,
+	})
+	if err != nil {
+		log.Println("Error occurred while creating the admin token")
+		return
+	}
+
+	// Set Repositories & Controllers & Services
+	mail = mailer.NewMemoryMailer()
+	policy := models.PasswordPolicy{MinLength: 8, RequireLower: true, RequireDigit: true}
+	repositories := repositories.InitRepositories(db)
+	services := services.InitServices(repositories, policy, mail, tokenSecret, "http://localhost:3000", time.Hour)
+	controllers := controllers.InitControllers(services)
+
+	oauthServer := oauth.NewBearerServer(
+		oauthKey,
+		time.Minute*60,
+		&controllers.VerifierController,
+		nil)
+
+	router = chi.NewRouter()
+
+	// Adds Routers
+	router.Post("/api/register", controllers.UserController.Register)
+	router.Post("/api/login", oauthServer.UserCredentials)
+	router.Post("/api/auth", oauthServer.ClientCredentials)
+	router.Post("/api/oauth/token", oauthServer.AuthorizationCode)
+	route.AddUserRouter(router, oauthKey, controllers.UserController)
+	route.AddAccountRouter(router, oauthKey, controllers.AccountController)
+	route.AddSessionRouter(router, oauthKey, controllers.SessionController)
+	route.AddClientRouter(router, oauthKey, controllers.ClientController)
+
+	log.Println("Setup Complete")
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/steinfletcher/apitest"
+)
+
+// Tokens answered on login
+type tokens struct {
+	Access  string `json:"access_token"`
+	Refresh string `json:"refresh_token"`
+}
+
+/* Tests GET the profile of the user, without their password*/
+func TestGetProfile(t *testing.T) {
+	username := "profile-" + run
+	register(t, username)
+	access := login(t, username, password).Access
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/user/me").
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var profile map[string]interface{}
+			if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+				return err
+			}
+			if profile["username"] != username || profile["email"] != username+"@example.com" {
+				return errors.New("profile of another user")
+			}
+			if _, ok := profile["password"]; ok {
+				return errors.New("password sent in the profile")
+			}
+			return nil
+		}).
+		End()
+}
+
+/* Tests PUT the email of the user, which takes their current password and needs verifying again*/
+func TestChangeEmail(t *testing.T) {
+	username := "email-" + run
+	register(t, username)
+	access := login(t, username, password).Access
+
+	changeEmail(t, access, username+"@example.org", "wrong-password1", http.StatusUnauthorized)
+	changeEmail(t, access, "not-an-email", password, http.StatusUnprocessableEntity)
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Put("/api/user/me/email").
+		JSON(`{"email": "`+username+`@example.org", "current_password": "`+password+`"}`).
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var profile struct {
+				Email         string `json:"email"`
+				EmailVerified bool   `json:"email_verified"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+				return err
+			}
+			if profile.Email != username+"@example.org" || profile.EmailVerified {
+				return errors.New("email should be changed and unverified")
+			}
+			return nil
+		}).
+		End()
+}
+
+/* Tests PUT the password of the user, after which only the new one logs in*/
+func TestChangePassword(t *testing.T) {
+	username := "password-" + run
+	register(t, username)
+	access := login(t, username, password).Access
+
+	changePassword(t, access, "wrong-password1", "another123", http.StatusUnauthorized)
+	changePassword(t, access, password, "weak", http.StatusUnprocessableEntity)
+	changePassword(t, access, password, "another123", http.StatusNoContent)
+
+	loginStatus(t, username, password, http.StatusUnauthorized)
+	loginStatus(t, username, "another123", http.StatusOK)
+}
+
+/* Tests DELETE the account of the user, which takes their current password*/
+func TestDeleteAccount(t *testing.T) {
+	username := "delete-" + run
+	register(t, username)
+	access := login(t, username, password).Access
+
+	deleteAccount(t, access, "wrong-password1", http.StatusUnauthorized)
+	deleteAccount(t, access, password, http.StatusNoContent)
+
+	loginStatus(t, username, password, http.StatusUnauthorized)
+}
+
+/* Tests GET every User, which only admins can*/
+func TestGetUsers(t *testing.T) {
+	username := "lister-" + run
+	register(t, username)
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/user").
+		Header("Authorization", "Bearer "+login(t, username, password).Access).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Get("/api/user").
+		Header("Authorization", "Bearer "+adminHeader).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			var users []map[string]interface{}
+			if err := json.NewDecoder(res.Body).Decode(&users); err != nil {
+				return err
+			}
+			for _, user := range users {
+				if _, ok := user["password"]; ok {
+					return errors.New("password sent in the listing")
+				}
+			}
+			return nil
+		}).
+		End()
+}
+
+// Logs the user in with the password, returning their tokens
+func login(t *testing.T, username, secret string) tokens {
+	var answered tokens
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/login").
+		FormData("grant_type", "password").
+		FormData("username", username).
+		FormData("password", secret).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(func(res *http.Response, req *http.Request) error {
+			return json.NewDecoder(res.Body).Decode(&answered)
+		}).
+		End()
+	return answered
+}
+
+// Logs the user in with the password, expecting the status
+func loginStatus(t *testing.T, username, secret string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Post("/api/login").
+		FormData("grant_type", "password").
+		FormData("username", username).
+		FormData("password", secret).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+func changeEmail(t *testing.T, access, email, current string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Put("/api/user/me/email").
+		JSON(`{"email": "`+email+`", "current_password": "`+current+`"}`).
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+func changePassword(t *testing.T, access, current, next string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Put("/api/user/me/password").
+		JSON(`{"current_password": "`+current+`", "new_password": "`+next+`"}`).
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(status).
+		End()
+}
+
+func deleteAccount(t *testing.T, access, current string, status int) {
+	apitest.New().
+		HandlerFunc(router.ServeHTTP).
+		Delete("/api/user/me").
+		JSON(`{"current_password": "`+current+`"}`).
+		Header("Authorization", "Bearer "+access).
+		Expect(t).
+		Status(status).
+		End()
+}
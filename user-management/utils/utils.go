@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"net/http"
+	"user-management/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+)
+
+func GetFieldFromURL(r *http.Request, field string) string {
+	return chi.URLParam(r, field)
+}
+
+func GetUsernameFromToken(r *http.Request) (string, error) {
+	claims := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+
+	if username, ok := claims["username"]; ok {
+		return username, nil
+	}
+
+	return "", middleware.NewError(http.StatusForbidden, "Error - Token doesn't belong to a user")
+}
+
+func GetTokenIDFromToken(r *http.Request) (string, error) {
+	claims := r.Context().Value(oauth.ClaimsContext).(map[string]string)
+
+	if tokenID, ok := claims["token_id"]; ok {
+		return tokenID, nil
+	}
+
+	return "", middleware.NewError(http.StatusUnauthorized, "Token has no id, log in again")
+}
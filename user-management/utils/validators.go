@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"user-management/middleware"
+
+	"github.com/asaskevich/govalidator"
+)
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+)
+
+// Registers the validation tag of usernames
+func init() {
+	govalidator.TagMap["username"] = govalidator.Validator(IsUsername)
+}
+
+// IsUsername checks the string is 3 to 30 ASCII letters, digits, dots, dashes or underscores
+func IsUsername(str string) bool {
+	if len(str) < minUsernameLength || len(str) > maxUsernameLength {
+		return false
+	}
+	for _, r := range str {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func ValidateStruct(value interface{}) error {
+	if _, err := govalidator.ValidateStruct(value); err != nil {
+		log.Println("Error - Model validation failed: " + err.Error())
+		return middleware.NewError(http.StatusUnprocessableEntity, "Error occurred, model validation failed: "+err.Error())
+	}
+	return nil
+}